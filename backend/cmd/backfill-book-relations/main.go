@@ -0,0 +1,38 @@
+// Command backfill-book-relations links existing books to authors and genres
+// records by matching their free-text author and genre columns, creating any
+// authors and genres that do not exist yet. It prints a JSON report listing
+// the fuzzy matches it made so they can be reviewed.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+
+	"book-recommendation-system/backend/database"
+	"book-recommendation-system/backend/repositories"
+	"book-recommendation-system/backend/services"
+)
+
+func main() {
+	db, err := database.ConnectDB(database.DefaultConfig())
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.CloseDB(db)
+
+	backfiller := services.NewBookRelationBackfiller(
+		repositories.NewBookRepository(db),
+		repositories.NewAuthorRepository(db),
+		repositories.NewGenreRepository(db),
+	)
+	report, err := backfiller.Run(context.Background())
+	if err != nil {
+		log.Fatalf("Backfill failed: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(report)
+}
@@ -0,0 +1,38 @@
+// Command backfill-isbns rewrites the ISBNs of existing books as bare
+// ISBN-13s, the form books have been stored under since ISBNs were
+// normalised, so that lookups by ISBN find them. A book whose normalised ISBN
+// is already another book's is merged into it. It prints a JSON report
+// listing the merges and the ISBNs that could not be normalised.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+
+	"book-recommendation-system/backend/database"
+	"book-recommendation-system/backend/repositories"
+	"book-recommendation-system/backend/services"
+)
+
+func main() {
+	db, err := database.ConnectDB(database.DefaultConfig())
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.CloseDB(db)
+
+	books := repositories.NewBookRepository(db)
+	holdings := repositories.NewHoldingRepository(db)
+	duplicates := services.NewDuplicateService(books, holdings, repositories.NewLibraryRepository(db), repositories.NewHoldRepository(db))
+	backfiller := services.NewISBNBackfiller(books, duplicates)
+	report, err := backfiller.Run(context.Background())
+	if err != nil {
+		log.Fatalf("Backfill failed: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(report)
+}
@@ -0,0 +1,125 @@
+// Command import-catalog bulk imports books from a CSV, JSON Lines, MARC 21,
+// MARCXML or ONIX 3.0 file, creating, updating or deleting them by ISBN and
+// linking their contributors and genres. With -library, the file is taken to
+// be that library's catalogue and its copies are recorded as holdings. It
+// prints a JSON report with the result of every row. An interrupted import can
+// be resumed by passing the job ID from the previous run with -job.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"book-recommendation-system/backend/database"
+	"book-recommendation-system/backend/repositories"
+	"book-recommendation-system/backend/services"
+)
+
+func main() {
+	file := flag.String("file", "", "path of the file to import")
+	format := flag.String("format", "", "csv, jsonl, marc, marcxml or onix (default: from the file extension, or the root element of .xml files)")
+	libraryID := flag.String("library", "", "ID of the library whose catalogue the file is")
+	jobID := flag.String("job", "", "ID of an interrupted import job to resume")
+	flag.Parse()
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v", *file, err)
+	}
+	defer f.Close()
+	if *format == "" {
+		switch strings.ToLower(filepath.Ext(*file)) {
+		case ".csv":
+			*format = services.ImportFormatCSV
+		case ".jsonl", ".ndjson":
+			*format = services.ImportFormatJSONL
+		case ".mrc", ".marc":
+			*format = services.ImportFormatMARC
+		case ".xml":
+			if *format, err = xmlFormat(f); err != nil {
+				log.Fatalf("Cannot tell the format of %s (%v); pass -format", *file, err)
+			}
+		case ".onix":
+			*format = services.ImportFormatONIX
+		default:
+			log.Fatalf("Cannot tell the format of %s; pass -format", *file)
+		}
+	}
+
+	reader, err := services.NewRecordReader(*format, f)
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", *file, err)
+	}
+
+	db, err := database.ConnectDB(database.DefaultConfig())
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.CloseDB(db)
+
+	importer := services.NewCatalogImportService(
+		repositories.NewBookRepository(db),
+		repositories.NewAuthorRepository(db),
+		repositories.NewGenreRepository(db),
+		repositories.NewLibraryRepository(db),
+		repositories.NewHoldingRepository(db),
+		repositories.NewImportJobRepository(db),
+	)
+	report, err := importer.ImportCatalog(context.Background(), reader, services.ImportOptions{
+		Source:    filepath.Base(*file),
+		Format:    *format,
+		LibraryID: *libraryID,
+		JobID:     *jobID,
+	})
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(report)
+}
+
+// xmlFormat tells MARCXML from ONIX by the root element of an XML file, and
+// rewinds the file for reading.
+func xmlFormat(f *os.File) (string, error) {
+	dec := xml.NewDecoder(f)
+	var format string
+	for format == "" {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return "", errors.New("no root element")
+		}
+		if err != nil {
+			return "", err
+		}
+		root, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch root.Name.Local {
+		case "ONIXMessage":
+			format = services.ImportFormatONIX
+		case "collection", "record":
+			format = services.ImportFormatMARCXML
+		default:
+			return "", fmt.Errorf("unknown root element <%s>", root.Name.Local)
+		}
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return format, nil
+}
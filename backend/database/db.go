@@ -1,7 +1,6 @@
 package database
 
 import (
-	"database/sql"
 	"fmt"
 	"log"
 
@@ -19,6 +18,17 @@ type Config struct {
 	SSLMode  string
 }
 
+// DefaultConfig returns the connection parameters for the local development database.
+func DefaultConfig() Config {
+	return Config{
+		Host:     "localhost",
+		Port:     "5432",
+		User:     "user",     // Replace with your DB user
+		Password: "password", // Replace with your DB password
+		DBName:   "bookrecsys",
+	}
+}
+
 // ConnectDB establishes a connection to the PostgreSQL database.
 func ConnectDB(cfg Config) (*sqlx.DB, error) {
 	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
@@ -3,6 +3,7 @@ module book-recommendation-system/backend
 go 1.25
 
 require (
+	github.com/go-chi/chi/v5 v5.3.2
 	github.com/jmoiron/sqlx v1.3.5
 	github.com/lib/pq v1.10.9
-)
\ No newline at end of file
+)
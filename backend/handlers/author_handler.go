@@ -1,9 +1,10 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"book-recommendation-system/backend/models"
 	"book-recommendation-system/backend/services"
@@ -43,13 +44,20 @@ func (h *AuthorHandler) GetAuthorByID(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(author)
 }
 
-// GetAllAuthors handles the request to get all authors.
+// GetAllAuthors handles the request to get a page of all authors (?cursor=, ?limit=).
 func (h *AuthorHandler) GetAllAuthors(w http.ResponseWriter, r *http.Request) {
-	authors, err := h.service.GetAllAuthors(r.Context())
+	page, err := parsePageRequest(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	authors, err := h.service.GetAllAuthors(r.Context(), page)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
+	setPageLinks(r, authors)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(authors)
@@ -118,3 +126,60 @@ func (h *AuthorHandler) DeleteAuthor(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// GetAuthorBooks handles the request to get an author's bibliography with
+// reader statistics.
+func (h *AuthorHandler) GetAuthorBooks(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Author ID is required", http.StatusBadRequest)
+		return
+	}
+
+	bibliography, err := h.service.GetAuthorBibliography(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if bibliography == nil {
+		http.Error(w, "Author not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(bibliography)
+}
+
+// GetRelatedAuthors handles the request to get the authors most read by
+// readers of an author; ?limit= caps the results.
+func (h *AuthorHandler) GetRelatedAuthors(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Author ID is required", http.StatusBadRequest)
+		return
+	}
+	limit := 0
+	if rawLimit := r.URL.Query().Get("limit"); rawLimit != "" {
+		var err error
+		limit, err = strconv.Atoi(rawLimit)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid limit %q", rawLimit), http.StatusBadRequest)
+			return
+		}
+	}
+
+	related, err := h.service.GetRelatedAuthors(r.Context(), id, limit)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if related == nil {
+		http.Error(w, "Author not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(related)
+}
@@ -1,9 +1,10 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"book-recommendation-system/backend/models"
 	"book-recommendation-system/backend/services"
@@ -43,16 +44,120 @@ func (h *BookHandler) GetBookByID(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(book)
 }
 
-// GetAllBooks handles the request to get all books.
+// GetBookByISBN handles the request to get a book by its ISBN-10 or ISBN-13.
+func (h *BookHandler) GetBookByISBN(w http.ResponseWriter, r *http.Request) {
+	isbn := chi.URLParam(r, "isbn")
+	if isbn == "" {
+		http.Error(w, "ISBN is required", http.StatusBadRequest)
+		return
+	}
+
+	book, err := h.service.GetBookByISBN(r.Context(), isbn)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if book == nil {
+		http.Error(w, "Book not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(book)
+}
+
+// GetAllBooks handles the request to browse books. Optional filters are ?genre=,
+// ?genre_id=, ?author=, ?format=, ?publisher= and ?subject= (repeatable), ?year_from=,
+// ?year_to=, ?pages_min=, ?pages_max=, ?language= and ?available=; a genre also
+// matches books in its subgenres. ?sort= orders by title, author
+// or publication_year, descending with a "-" prefix. The response is a page (?cursor=,
+// ?limit=) with facet counts per genre, author, decade and format.
 func (h *BookHandler) GetAllBooks(w http.ResponseWriter, r *http.Request) {
-	books, err := h.service.GetAllBooks(r.Context())
+	filter, err := parseBookFilter(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	page, err := parsePageRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	result, err := h.service.QueryBooks(r.Context(), filter, page)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+	setPageLinks(r, &result.Page)
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(books)
+	json.NewEncoder(w).Encode(result)
+}
+
+// parseBookFilter reads catalogue filters from the query string.
+func parseBookFilter(r *http.Request) (models.BookFilter, error) {
+	query := r.URL.Query()
+	filter := models.BookFilter{
+		Genres:     query["genre"],
+		GenreIDs:   query["genre_id"],
+		Authors:    query["author"],
+		Formats:    query["format"],
+		Publishers: query["publisher"],
+		Subjects:   query["subject"],
+		Language:   query.Get("language"),
+		Sort:       query.Get("sort"),
+	}
+	for _, number := range []struct {
+		param string
+		dest  *int
+	}{
+		{"year_from", &filter.YearFrom},
+		{"year_to", &filter.YearTo},
+		{"pages_min", &filter.PagesMin},
+		{"pages_max", &filter.PagesMax},
+	} {
+		if raw := query.Get(number.param); raw != "" {
+			v, err := strconv.Atoi(raw)
+			if err != nil {
+				return filter, fmt.Errorf("invalid %s %q", number.param, raw)
+			}
+			*number.dest = v
+		}
+	}
+	if raw := query.Get("available"); raw != "" {
+		available, err := strconv.ParseBool(raw)
+		if err != nil {
+			return filter, fmt.Errorf("invalid available %q", raw)
+		}
+		filter.Available = &available
+	}
+	return filter, nil
+}
+
+// SearchBooks handles the request to full-text search books with ?q=. Quoted phrases
+// match adjacent words and a trailing * matches a prefix; ?limit= caps the results.
+func (h *BookHandler) SearchBooks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	limit := 0
+	if rawLimit := query.Get("limit"); rawLimit != "" {
+		var err error
+		limit, err = strconv.Atoi(rawLimit)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid limit %q", rawLimit), http.StatusBadRequest)
+			return
+		}
+	}
+
+	results, err := h.service.SearchBooks(r.Context(), query.Get("q"), limit)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
 }
 
 // CreateBook handles the request to create a new book.
@@ -66,7 +171,7 @@ func (h *BookHandler) CreateBook(w http.ResponseWriter, r *http.Request) {
 
 	err = h.service.CreateBook(r.Context(), &book)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
 
@@ -94,7 +199,7 @@ func (h *BookHandler) UpdateBook(w http.ResponseWriter, r *http.Request) {
 
 	err = h.service.UpdateBook(r.Context(), &book)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
 
@@ -112,7 +217,7 @@ func (h *BookHandler) DeleteBook(w http.ResponseWriter, r *http.Request) {
 
 	err := h.service.DeleteBook(r.Context(), id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
 
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"book-recommendation-system/backend/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// coverCacheControl lets clients and proxies cache covers for a day. Cover
+// URLs carry a version that changes on upload, so a new cover is fetched
+// straight away.
+const coverCacheControl = "public, max-age=86400"
+
+// maxCoverFormOverhead allows for the multipart framing around an uploaded cover.
+const maxCoverFormOverhead = 1 << 20
+
+// CoverHandler handles HTTP requests for book cover images.
+type CoverHandler struct {
+	service services.CoverService
+}
+
+// NewCoverHandler creates a new CoverHandler.
+func NewCoverHandler(s services.CoverService) *CoverHandler {
+	return &CoverHandler{service: s}
+}
+
+// UploadCover handles the request to upload a book's cover, sent as the
+// "cover" file of a multipart form. It responds with the updated book.
+func (h *CoverHandler) UploadCover(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Book ID is required", http.StatusBadRequest)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, services.MaxCoverBytes+maxCoverFormOverhead)
+	file, _, err := r.FormFile("cover")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("a cover image file is required in the \"cover\" form field: %v", err), http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	book, err := h.service.UploadCover(r.Context(), id, file)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if book == nil {
+		http.Error(w, "Book not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(book)
+}
+
+// GetCover handles the request to get a book's cover image in a given
+// ?size= (small, medium, large or original; large by default). Conditional
+// and range requests are supported.
+func (h *CoverHandler) GetCover(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Book ID is required", http.StatusBadRequest)
+		return
+	}
+
+	cover, err := h.service.GetCover(r.Context(), id, r.URL.Query().Get("size"))
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if cover == nil {
+		http.Error(w, "Cover not found", http.StatusNotFound)
+		return
+	}
+	defer cover.Close()
+
+	w.Header().Set("Content-Type", cover.ContentType)
+	w.Header().Set("Cache-Control", coverCacheControl)
+	w.Header().Set("ETag", fmt.Sprintf(`"%x-%x"`, cover.ModTime.UnixNano(), cover.Size))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, "", cover.ModTime, cover)
+}
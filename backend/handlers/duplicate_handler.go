@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"book-recommendation-system/backend/models"
+	"book-recommendation-system/backend/services"
+)
+
+// DuplicateHandler handles admin HTTP requests for reviewing and merging
+// duplicate books.
+type DuplicateHandler struct {
+	service services.DuplicateService
+}
+
+// NewDuplicateHandler creates a new DuplicateHandler.
+func NewDuplicateHandler(s services.DuplicateService) *DuplicateHandler {
+	return &DuplicateHandler{service: s}
+}
+
+// GetDuplicates handles the request to list candidate duplicate books for
+// review, most similar first; ?limit= caps the results.
+func (h *DuplicateHandler) GetDuplicates(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if rawLimit := r.URL.Query().Get("limit"); rawLimit != "" {
+		var err error
+		limit, err = strconv.Atoi(rawLimit)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid limit %q", rawLimit), http.StatusBadRequest)
+			return
+		}
+	}
+
+	candidates, err := h.service.FindDuplicates(r.Context(), limit)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(candidates)
+}
+
+// MergeBooks handles the request to merge duplicate books into a surviving one.
+func (h *DuplicateHandler) MergeBooks(w http.ResponseWriter, r *http.Request) {
+	var req models.BookMergeRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.MergeBooks(r.Context(), req)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
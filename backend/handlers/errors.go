@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"book-recommendation-system/backend/models"
+	"book-recommendation-system/backend/services"
+)
+
+// errorStatus maps a service error to an HTTP status code.
+func errorStatus(err error) int {
+	if errors.Is(err, services.ErrInvalidInput) || errors.Is(err, models.ErrInvalidCursor) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
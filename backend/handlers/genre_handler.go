@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
 	"net/http"
 
@@ -43,14 +42,21 @@ func (h *GenreHandler) GetGenreByID(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(genre)
 }
 
-// GetAllGenres handles the request to get all genres.
+// GetAllGenres handles the request to get a page of all genres (?cursor=, ?limit=).
 func (h *GenreHandler) GetAllGenres(w http.ResponseWriter, r *http.Request) {
-	genres, err := h.service.GetAllGenres(r.Context())
+	page, err := parsePageRequest(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	genres, err := h.service.GetAllGenres(r.Context(), page)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+	setPageLinks(r, genres)
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(genres)
 }
@@ -66,7 +72,7 @@ func (h *GenreHandler) CreateGenre(w http.ResponseWriter, r *http.Request) {
 
 	err = h.service.CreateGenre(r.Context(), &genre)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
 
@@ -94,7 +100,7 @@ func (h *GenreHandler) UpdateGenre(w http.ResponseWriter, r *http.Request) {
 
 	err = h.service.UpdateGenre(r.Context(), &genre)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
 
@@ -118,3 +124,69 @@ func (h *GenreHandler) DeleteGenre(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// GetGenreTree handles the request to get the whole genre taxonomy as trees
+// under the top-level genres.
+func (h *GenreHandler) GetGenreTree(w http.ResponseWriter, r *http.Request) {
+	tree, err := h.service.GetGenreTree(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tree)
+}
+
+// GetGenreSubtree handles the request to get a genre with all its subgenres.
+func (h *GenreHandler) GetGenreSubtree(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Genre ID is required", http.StatusBadRequest)
+		return
+	}
+
+	node, err := h.service.GetGenreSubtree(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if node == nil {
+		http.Error(w, "Genre not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(node)
+}
+
+// GetGenreBooks handles the request to get a page of the books in a genre or
+// any of its subgenres (?cursor=, ?limit=).
+func (h *GenreHandler) GetGenreBooks(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Genre ID is required", http.StatusBadRequest)
+		return
+	}
+	page, err := parsePageRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	books, err := h.service.GetGenreBooks(r.Context(), id, page)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if books == nil {
+		http.Error(w, "Genre not found", http.StatusNotFound)
+		return
+	}
+	setPageLinks(r, books)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(books)
+}
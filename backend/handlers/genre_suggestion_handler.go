@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"book-recommendation-system/backend/models"
+	"book-recommendation-system/backend/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// GenreSuggestionHandler handles admin HTTP requests for generating and
+// reviewing genre suggestions for untagged books.
+type GenreSuggestionHandler struct {
+	service services.GenreSuggestionService
+}
+
+// NewGenreSuggestionHandler creates a new GenreSuggestionHandler.
+func NewGenreSuggestionHandler(s services.GenreSuggestionService) *GenreSuggestionHandler {
+	return &GenreSuggestionHandler{service: s}
+}
+
+// GenerateSuggestions handles the request to retrain the genre classifier and
+// replace the pending suggestions.
+func (h *GenreSuggestionHandler) GenerateSuggestions(w http.ResponseWriter, r *http.Request) {
+	run, err := h.service.GenerateSuggestions(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(run)
+}
+
+// GetSuggestions handles the request to get a page of genre suggestions,
+// most confident first (?status=pending|accepted|rejected, ?cursor=, ?limit=).
+func (h *GenreSuggestionHandler) GetSuggestions(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePageRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	suggestions, err := h.service.GetGenreSuggestions(r.Context(), r.URL.Query().Get("status"), page)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+	setPageLinks(r, suggestions)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(suggestions)
+}
+
+// AcceptSuggestion handles the request to accept a genre suggestion, tagging
+// the book with the genre.
+func (h *GenreSuggestionHandler) AcceptSuggestion(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, models.GenreSuggestionAccepted)
+}
+
+// RejectSuggestion handles the request to reject a genre suggestion.
+func (h *GenreSuggestionHandler) RejectSuggestion(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, models.GenreSuggestionRejected)
+}
+
+func (h *GenreSuggestionHandler) review(w http.ResponseWriter, r *http.Request, status string) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Suggestion ID is required", http.StatusBadRequest)
+		return
+	}
+
+	suggestion, err := h.service.ReviewGenreSuggestion(r.Context(), id, status)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if suggestion == nil {
+		http.Error(w, "Suggestion not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(suggestion)
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"book-recommendation-system/backend/models"
+	"book-recommendation-system/backend/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// HoldHandler handles HTTP requests for holds on books.
+type HoldHandler struct {
+	service services.HoldService
+}
+
+// NewHoldHandler creates a new HoldHandler.
+func NewHoldHandler(s services.HoldService) *HoldHandler {
+	return &HoldHandler{service: s}
+}
+
+// PlaceHold handles the request to place a hold on a book.
+func (h *HoldHandler) PlaceHold(w http.ResponseWriter, r *http.Request) {
+	var hold models.Hold
+	err := json.NewDecoder(r.Body).Decode(&hold)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.PlaceHold(r.Context(), &hold)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(hold)
+}
+
+// GetHolds handles the request to get a page of holds, oldest first,
+// optionally filtered by ?user_id=, ?book_id=, ?pickup_library_id= and one or
+// more ?status=.
+func (h *HoldHandler) GetHolds(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePageRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	query := r.URL.Query()
+	filter := models.HoldFilter{
+		UserID:          query.Get("user_id"),
+		BookID:          query.Get("book_id"),
+		PickupLibraryID: query.Get("pickup_library_id"),
+		Statuses:        query["status"],
+	}
+
+	holds, err := h.service.GetHolds(r.Context(), filter, page)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+	setPageLinks(r, holds)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(holds)
+}
+
+// GetHoldByID handles the request to get a hold by its ID.
+func (h *HoldHandler) GetHoldByID(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Hold ID is required", http.StatusBadRequest)
+		return
+	}
+
+	hold, err := h.service.GetHoldByID(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if hold == nil {
+		http.Error(w, "Hold not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hold)
+}
+
+// CancelHold handles the request to cancel a waiting or ready hold.
+func (h *HoldHandler) CancelHold(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Hold ID is required", http.StatusBadRequest)
+		return
+	}
+
+	hold, err := h.service.CancelHold(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if hold == nil {
+		http.Error(w, "Hold not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hold)
+}
+
+// GetHoldPosition handles the request to get a hold's place in its book's
+// queue and when a copy is expected to be ready for it.
+func (h *HoldHandler) GetHoldPosition(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Hold ID is required", http.StatusBadRequest)
+		return
+	}
+
+	position, err := h.service.GetHoldPosition(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if position == nil {
+		http.Error(w, "Hold not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(position)
+}
+
+// GetBookHoldQueue handles the request to get the queue of waiting holds on a
+// book, first in line first.
+func (h *HoldHandler) GetBookHoldQueue(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Book ID is required", http.StatusBadRequest)
+		return
+	}
+
+	holds, err := h.service.GetBookHoldQueue(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if holds == nil {
+		http.Error(w, "Book not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(holds)
+}
+
+// ExpireHolds handles the request to expire lapsed holds and pass the copies
+// they leave on the hold shelf to the next holds in line.
+func (h *HoldHandler) ExpireHolds(w http.ResponseWriter, r *http.Request) {
+	run, err := h.service.ExpireHolds(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(run)
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"book-recommendation-system/backend/models"
+	"book-recommendation-system/backend/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// HoldingHandler handles HTTP requests for library holdings.
+type HoldingHandler struct {
+	service services.HoldingService
+}
+
+// NewHoldingHandler creates a new HoldingHandler.
+func NewHoldingHandler(s services.HoldingService) *HoldingHandler {
+	return &HoldingHandler{service: s}
+}
+
+// GetHoldingByID handles the request to get a holding by its ID.
+func (h *HoldingHandler) GetHoldingByID(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Holding ID is required", http.StatusBadRequest)
+		return
+	}
+
+	holding, err := h.service.GetHoldingByID(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if holding == nil {
+		http.Error(w, "Holding not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(holding)
+}
+
+// GetHoldings handles the request to get a page of holdings, optionally
+// filtered by ?library_id=, ?book_id=, ?barcode= and ?status= (repeatable).
+func (h *HoldingHandler) GetHoldings(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePageRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	query := r.URL.Query()
+	filter := models.HoldingFilter{
+		LibraryID: query.Get("library_id"),
+		BookID:    query.Get("book_id"),
+		Barcode:   query.Get("barcode"),
+		Statuses:  query["status"],
+	}
+
+	holdings, err := h.service.GetHoldings(r.Context(), filter, page)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+	setPageLinks(r, holdings)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(holdings)
+}
+
+// CreateHolding handles the request to add a copy of a book to a library.
+func (h *HoldingHandler) CreateHolding(w http.ResponseWriter, r *http.Request) {
+	var holding models.Holding
+	err := json.NewDecoder(r.Body).Decode(&holding)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = h.service.CreateHolding(r.Context(), &holding)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(holding)
+}
+
+// UpdateHolding handles the request to update an existing holding.
+func (h *HoldingHandler) UpdateHolding(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Holding ID is required", http.StatusBadRequest)
+		return
+	}
+
+	var holding models.Holding
+	err := json.NewDecoder(r.Body).Decode(&holding)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	holding.ID = id // Ensure the ID from the URL is used
+
+	updated, err := h.service.UpdateHolding(r.Context(), &holding)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if updated == nil {
+		http.Error(w, "Holding not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// DeleteHolding handles the request to delete a holding by its ID.
+func (h *HoldingHandler) DeleteHolding(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Holding ID is required", http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.DeleteHolding(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetBookAvailability handles the request to list the copies of a book held
+// across all libraries and how many can be borrowed.
+func (h *HoldingHandler) GetBookAvailability(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Book ID is required", http.StatusBadRequest)
+		return
+	}
+
+	availability, err := h.service.GetBookAvailability(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if availability == nil {
+		http.Error(w, "Book not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(availability)
+}
+
+// GetLibraryBooks handles the request to get a page of the books a library
+// holds, with its copies of each. It takes the same filters and ?sort= as
+// GET /books; ?available= considers only the library's copies.
+func (h *HoldingHandler) GetLibraryBooks(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Library ID is required", http.StatusBadRequest)
+		return
+	}
+	page, err := parsePageRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter, err := parseBookFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	books, err := h.service.GetLibraryBooks(r.Context(), id, filter, page)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if books == nil {
+		http.Error(w, "Library not found", http.StatusNotFound)
+		return
+	}
+	setPageLinks(r, books)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(books)
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+
+	"book-recommendation-system/backend/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// ImportHandler handles HTTP requests for bulk catalogue imports.
+type ImportHandler struct {
+	service services.CatalogImportService
+}
+
+// NewImportHandler creates a new ImportHandler.
+func NewImportHandler(s services.CatalogImportService) *ImportHandler {
+	return &ImportHandler{service: s}
+}
+
+// importFormats maps request content types to import formats.
+var importFormats = map[string]string{
+	"text/csv":                services.ImportFormatCSV,
+	"application/jsonl":       services.ImportFormatJSONL,
+	"application/x-ndjson":    services.ImportFormatJSONL,
+	"application/marc":        services.ImportFormatMARC,
+	"application/marcxml+xml": services.ImportFormatMARCXML,
+}
+
+// ImportBooks handles the request to bulk import books from a CSV, JSON Lines, MARC 21,
+// MARCXML or ONIX 3.0 body, streamed as it is read. The format is taken from
+// ?format=csv|jsonl|marc|marcxml|onix or the Content-Type. Pass ?library_id= to record the
+// library as holding the imported copies, and ?job_id= to resume an interrupted import,
+// skipping rows it already committed.
+func (h *ImportHandler) ImportBooks(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		format = importFormats[mediaType]
+	}
+	if format == "" {
+		http.Error(w, "Import format is required: pass ?format=csv, jsonl, marc, marcxml or onix", http.StatusBadRequest)
+		return
+	}
+
+	reader, err := services.NewRecordReader(format, r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+	report, err := h.service.ImportCatalog(r.Context(), reader, services.ImportOptions{
+		Source:    "upload",
+		Format:    format,
+		LibraryID: r.URL.Query().Get("library_id"),
+		JobID:     r.URL.Query().Get("job_id"),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// GetImportJob handles the request to get the progress of an import job.
+func (h *ImportHandler) GetImportJob(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Import job ID is required", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.service.GetImportJob(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if job == nil {
+		http.Error(w, "Import job not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
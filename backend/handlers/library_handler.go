@@ -1,9 +1,10 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"book-recommendation-system/backend/models"
 	"book-recommendation-system/backend/services"
@@ -43,11 +44,64 @@ func (h *LibraryHandler) GetLibraryByID(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(library)
 }
 
-// GetAllLibraries handles the request to get all libraries.
+// GetAllLibraries handles the request to get a page of all libraries (?cursor=, ?limit=).
 func (h *LibraryHandler) GetAllLibraries(w http.ResponseWriter, r *http.Request) {
-	libraries, err := h.service.GetAllLibraries(r.Context())
+	page, err := parsePageRequest(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	libraries, err := h.service.GetAllLibraries(r.Context(), page)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+	setPageLinks(r, libraries)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(libraries)
+}
+
+// GetNearbyLibraries handles the request to find the libraries nearest a
+// point (?lat=, ?lng=), within ?radius_km= and capped at ?limit=.
+func (h *LibraryHandler) GetNearbyLibraries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	rawLat, rawLng := query.Get("lat"), query.Get("lng")
+	if rawLat == "" || rawLng == "" {
+		http.Error(w, "lat and lng are required", http.StatusBadRequest)
+		return
+	}
+	lat, err := strconv.ParseFloat(rawLat, 64)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid lat %q", rawLat), http.StatusBadRequest)
+		return
+	}
+	lng, err := strconv.ParseFloat(rawLng, 64)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid lng %q", rawLng), http.StatusBadRequest)
+		return
+	}
+	var radius float64
+	if rawRadius := query.Get("radius_km"); rawRadius != "" {
+		radius, err = strconv.ParseFloat(rawRadius, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid radius_km %q", rawRadius), http.StatusBadRequest)
+			return
+		}
+	}
+	limit := 0
+	if rawLimit := query.Get("limit"); rawLimit != "" {
+		limit, err = strconv.Atoi(rawLimit)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid limit %q", rawLimit), http.StatusBadRequest)
+			return
+		}
+	}
+
+	libraries, err := h.service.GetNearbyLibraries(r.Context(), lat, lng, radius, limit)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
 
@@ -66,7 +120,7 @@ func (h *LibraryHandler) CreateLibrary(w http.ResponseWriter, r *http.Request) {
 
 	err = h.service.CreateLibrary(r.Context(), &library)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
 
@@ -94,7 +148,7 @@ func (h *LibraryHandler) UpdateLibrary(w http.ResponseWriter, r *http.Request) {
 
 	err = h.service.UpdateLibrary(r.Context(), &library)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
 
@@ -112,7 +166,7 @@ func (h *LibraryHandler) DeleteLibrary(w http.ResponseWriter, r *http.Request) {
 
 	err := h.service.DeleteLibrary(r.Context(), id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
 
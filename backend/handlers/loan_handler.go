@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"book-recommendation-system/backend/models"
+	"book-recommendation-system/backend/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// LoanHandler handles HTTP requests for circulation.
+type LoanHandler struct {
+	service services.LoanService
+}
+
+// NewLoanHandler creates a new LoanHandler.
+func NewLoanHandler(s services.LoanService) *LoanHandler {
+	return &LoanHandler{service: s}
+}
+
+// GetLoanByID handles the request to get a loan by its ID.
+func (h *LoanHandler) GetLoanByID(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Loan ID is required", http.StatusBadRequest)
+		return
+	}
+
+	loan, err := h.service.GetLoanByID(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if loan == nil {
+		http.Error(w, "Loan not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(loan)
+}
+
+// GetLoans handles the request to get a page of loans, soonest due first,
+// optionally filtered by ?user_id=, ?library_id=, ?book_id=, ?holding_id=
+// and ?status= (active, overdue or returned).
+func (h *LoanHandler) GetLoans(w http.ResponseWriter, r *http.Request) {
+	page, err := parsePageRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	query := r.URL.Query()
+	filter := models.LoanFilter{
+		UserID:    query.Get("user_id"),
+		LibraryID: query.Get("library_id"),
+		BookID:    query.Get("book_id"),
+		HoldingID: query.Get("holding_id"),
+		Status:    query.Get("status"),
+	}
+
+	loans, err := h.service.GetLoans(r.Context(), filter, page)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+	setPageLinks(r, loans)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(loans)
+}
+
+// GetOverdueLoans handles the request to get a page of the loans past their
+// due date, most overdue first.
+func (h *LoanHandler) GetOverdueLoans(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	query.Set("status", models.LoanStatusOverdue)
+	r.URL.RawQuery = query.Encode()
+	h.GetLoans(w, r)
+}
+
+// CheckOut handles the request to lend a copy, given by holding_id or
+// barcode, to a user.
+func (h *LoanHandler) CheckOut(w http.ResponseWriter, r *http.Request) {
+	var req models.CheckOutRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	loan, err := h.service.CheckOut(r.Context(), req)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(loan)
+}
+
+// CheckInByBarcode handles the request to return the loaned copy with the
+// barcode in the body. The response carries the closed loan and any hold the
+// copy now waits on the hold shelf for.
+func (h *LoanHandler) CheckInByBarcode(w http.ResponseWriter, r *http.Request) {
+	var req models.CheckInRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.CheckInByBarcode(r.Context(), req.Barcode)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// RenewLoan handles the request to renew a loan.
+func (h *LoanHandler) RenewLoan(w http.ResponseWriter, r *http.Request) {
+	h.update(w, r, h.service.RenewLoan)
+}
+
+// CheckIn handles the request to return a loan's copy. The response carries
+// the closed loan and any hold the copy now waits on the hold shelf for.
+func (h *LoanHandler) CheckIn(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Loan ID is required", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.CheckIn(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if result == nil {
+		http.Error(w, "Loan not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// update applies a change to the loan in the URL and responds with the
+// changed loan.
+func (h *LoanHandler) update(w http.ResponseWriter, r *http.Request, change func(ctx context.Context, id string) (*models.Loan, error)) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Loan ID is required", http.StatusBadRequest)
+		return
+	}
+
+	loan, err := change(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if loan == nil {
+		http.Error(w, "Loan not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(loan)
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"book-recommendation-system/backend/models"
+)
+
+// parsePageRequest reads the ?cursor= and ?limit= pagination parameters. The
+// limit is capped at models.MaxPageLimit by the repositories.
+func parsePageRequest(r *http.Request) (models.PageRequest, error) {
+	query := r.URL.Query()
+	page := models.PageRequest{Cursor: query.Get("cursor")}
+	if rawLimit := query.Get("limit"); rawLimit != "" {
+		limit, err := strconv.Atoi(rawLimit)
+		if err != nil || limit <= 0 {
+			return page, fmt.Errorf("invalid limit %q", rawLimit)
+		}
+		page.Limit = limit
+	}
+	return page, nil
+}
+
+// setPageLinks fills in the next and previous page links of a page as the
+// request URL with its cursor replaced.
+func setPageLinks[T any](r *http.Request, page *models.Page[T]) {
+	link := func(cursor string) string {
+		if cursor == "" {
+			return ""
+		}
+		u := *r.URL
+		query := u.Query()
+		query.Set("cursor", cursor)
+		u.RawQuery = query.Encode()
+		return u.RequestURI()
+	}
+	page.Links = models.PageLinks{Next: link(page.NextCursor), Prev: link(page.PrevCursor)}
+}
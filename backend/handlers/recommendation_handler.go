@@ -3,7 +3,11 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"book-recommendation-system/backend/models"
 	"book-recommendation-system/backend/services"
@@ -16,25 +20,29 @@ type RecommendationHandler struct {
 }
 
 // NewRecommendationHandler creates a new RecommendationHandler.
+func NewRecommendationHandler(s services.RecommendationService) *RecommendationHandler {
 	return &RecommendationHandler{service: s}
 }
 
-// GetAllRecommendations handles the request to get all recommendations.
+// GetAllRecommendations handles the request to get a page of all recommendations (?cursor=, ?limit=).
 func (h *RecommendationHandler) GetAllRecommendations(w http.ResponseWriter, r *http.Request) {
-	recommendations, err := h.service.GetAllRecommendations(r.Context())
+	page, err := parsePageRequest(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	recommendations, err := h.service.GetAllRecommendations(r.Context(), page)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+	setPageLinks(r, recommendations)
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(recommendations)
 }
 
-// GetRecommendationByID handles the request to get a recommendation by its ID.
-	return &RecommendationHandler{service: s}
-}
-
 // GetRecommendationByID handles the request to get a recommendation by its ID.
 func (h *RecommendationHandler) GetRecommendationByID(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -58,7 +66,26 @@ func (h *RecommendationHandler) GetRecommendationByID(w http.ResponseWriter, r *
 	json.NewEncoder(w).Encode(recommendation)
 }
 
+// defaultNearbyRadiusKm is used when ?near= is given without ?radius_km=.
+const defaultNearbyRadiusKm = 10.0
+
+const (
+	// defaultRealtimeBudget is the deadline for real-time scoring when the
+	// request does not set ?budget_ms=; maxRealtimeBudget caps what it may set.
+	defaultRealtimeBudget = 200 * time.Millisecond
+	maxRealtimeBudget     = 2 * time.Second
+	defaultRealtimeLimit  = 20
+	maxRealtimeLimit      = 100
+)
+
+// RecommendationTierHeader reports which tier served a real-time recommendation request.
+const RecommendationTierHeader = "X-Recommendation-Tier"
+
 // GetRecommendationsByUserID handles the request to get recommendations for a specific user ID.
+// An optional comma-separated "context" query parameter (e.g., ?context=weekend,mobile)
+// re-weights the recommendations for that context. An optional "near=lat,lng" with
+// "radius_km" restricts them to books available at nearby libraries, or boosts
+// those books when "mode=boost" is given.
 func (h *RecommendationHandler) GetRecommendationsByUserID(w http.ResponseWriter, r *http.Request) {
 	userID := chi.URLParam(r, "userID")
 	if userID == "" {
@@ -66,7 +93,35 @@ func (h *RecommendationHandler) GetRecommendationsByUserID(w http.ResponseWriter
 		return
 	}
 
-	recommendations, err := h.service.GetRecommendationsByUserID(r.Context(), userID)
+	query := r.URL.Query()
+	var contextTokens []string
+	if rawContext := query.Get("context"); rawContext != "" {
+		contextTokens = strings.Split(rawContext, ",")
+	}
+
+	if rawNear := query.Get("near"); rawNear != "" {
+		near, err := parseNearbyQuery(rawNear, query.Get("radius_km"), query.Get("mode"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		nearby, err := h.service.GetNearbyRecommendationsByUserID(r.Context(), userID, contextTokens, near)
+		if err != nil {
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(nearby)
+		return
+	}
+
+	var recommendations []models.Recommendation
+	var err error
+	if contextTokens != nil {
+		recommendations, err = h.service.GetContextualRecommendationsByUserID(r.Context(), userID, contextTokens)
+	} else {
+		recommendations, err = h.service.GetRecommendationsByUserID(r.Context(), userID)
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -76,6 +131,121 @@ func (h *RecommendationHandler) GetRecommendationsByUserID(w http.ResponseWriter
 	json.NewEncoder(w).Encode(recommendations)
 }
 
+// GetRealtimeRecommendationsByUserID handles the request to compute fresh recommendations
+// for a user within a latency budget (?budget_ms=), falling back to precomputed and then
+// popular recommendations. The serving tier is reported in the X-Recommendation-Tier header.
+func (h *RecommendationHandler) GetRealtimeRecommendationsByUserID(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	if userID == "" {
+		http.Error(w, "User ID is required", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	budget := defaultRealtimeBudget
+	if rawBudget := query.Get("budget_ms"); rawBudget != "" {
+		ms, err := strconv.Atoi(rawBudget)
+		if err != nil || ms <= 0 {
+			http.Error(w, fmt.Sprintf("invalid budget_ms %q", rawBudget), http.StatusBadRequest)
+			return
+		}
+		budget = min(time.Duration(ms)*time.Millisecond, maxRealtimeBudget)
+	}
+	limit := defaultRealtimeLimit
+	if rawLimit := query.Get("limit"); rawLimit != "" {
+		n, err := strconv.Atoi(rawLimit)
+		if err != nil || n <= 0 {
+			http.Error(w, fmt.Sprintf("invalid limit %q", rawLimit), http.StatusBadRequest)
+			return
+		}
+		limit = min(n, maxRealtimeLimit)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), budget)
+	defer cancel()
+	recommendations, tier, err := h.service.ScoreRecommendationsForUser(ctx, userID, limit)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(RecommendationTierHeader, tier)
+	json.NewEncoder(w).Encode(recommendations)
+}
+
+// GetGroupRecommendations handles the request to get a single list of recommendations
+// for a group of users, such as a book club.
+func (h *RecommendationHandler) GetGroupRecommendations(w http.ResponseWriter, r *http.Request) {
+	var req models.GroupRecommendationRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	recommendations, err := h.service.GetGroupRecommendations(r.Context(), req)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recommendations)
+}
+
+// GetContinueReading handles the request to get the next unread volume of each
+// series a user has started.
+func (h *RecommendationHandler) GetContinueReading(w http.ResponseWriter, r *http.Request) {
+	userID := chi.URLParam(r, "userID")
+	if userID == "" {
+		http.Error(w, "User ID is required", http.StatusBadRequest)
+		return
+	}
+
+	continueReading, err := h.service.GetContinueReading(r.Context(), userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(continueReading)
+}
+
+// parseNearbyQuery parses the "near", "radius_km" and "mode" query parameters.
+func parseNearbyQuery(rawNear, rawRadius, mode string) (services.NearbyQuery, error) {
+	var near services.NearbyQuery
+	latStr, lngStr, found := strings.Cut(rawNear, ",")
+	if !found {
+		return near, fmt.Errorf("near must be of the form lat,lng")
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return near, fmt.Errorf("invalid latitude %q", latStr)
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(lngStr), 64)
+	if err != nil || lng < -180 || lng > 180 {
+		return near, fmt.Errorf("invalid longitude %q", lngStr)
+	}
+	radius := defaultNearbyRadiusKm
+	if rawRadius != "" {
+		radius, err = strconv.ParseFloat(rawRadius, 64)
+		if err != nil || radius <= 0 {
+			return near, fmt.Errorf("invalid radius_km %q", rawRadius)
+		}
+	}
+	switch mode {
+	case "", "restrict":
+	case "boost":
+		near.Boost = true
+	default:
+		return near, fmt.Errorf("invalid mode %q: must be restrict or boost", mode)
+	}
+	near.Latitude, near.Longitude, near.RadiusKm = lat, lng, radius
+	return near, nil
+}
+
 // CreateRecommendation handles the request to create a new recommendation.
 func (h *RecommendationHandler) CreateRecommendation(w http.ResponseWriter, r *http.Request) {
 	var recommendation models.Recommendation
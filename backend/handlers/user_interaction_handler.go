@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
 	"net/http"
 
@@ -16,25 +15,29 @@ type UserInteractionHandler struct {
 }
 
 // NewUserInteractionHandler creates a new UserInteractionHandler.
+func NewUserInteractionHandler(s services.UserInteractionService) *UserInteractionHandler {
 	return &UserInteractionHandler{service: s}
 }
 
-// GetAllUserInteractions handles the request to get all user interactions.
+// GetAllUserInteractions handles the request to get a page of all user interactions (?cursor=, ?limit=).
 func (h *UserInteractionHandler) GetAllUserInteractions(w http.ResponseWriter, r *http.Request) {
-	userInteractions, err := h.service.GetAllUserInteractions(r.Context())
+	page, err := parsePageRequest(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	userInteractions, err := h.service.GetAllUserInteractions(r.Context(), page)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
+	setPageLinks(r, userInteractions)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(userInteractions)
 }
 
-// GetUserInteractionByID handles the request to get a user interaction by its ID.
-	return &UserInteractionHandler{service: s}
-}
-
 // GetUserInteractionByID handles the request to get a user interaction by its ID.
 func (h *UserInteractionHandler) GetUserInteractionByID(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -87,7 +90,7 @@ func (h *UserInteractionHandler) CreateUserInteraction(w http.ResponseWriter, r
 
 	err = h.service.CreateUserInteraction(r.Context(), &userInteraction)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
 
@@ -115,7 +118,7 @@ func (h *UserInteractionHandler) UpdateUserInteraction(w http.ResponseWriter, r
 
 	err = h.service.UpdateUserInteraction(r.Context(), &userInteraction)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), errorStatus(err))
 		return
 	}
 
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"book-recommendation-system/backend/models"
+	"book-recommendation-system/backend/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// WorkHandler handles HTTP requests for works and their editions.
+type WorkHandler struct {
+	service services.WorkService
+}
+
+// NewWorkHandler creates a new WorkHandler.
+func NewWorkHandler(s services.WorkService) *WorkHandler {
+	return &WorkHandler{service: s}
+}
+
+// GetWorkByID handles the request to get a work and its editions.
+func (h *WorkHandler) GetWorkByID(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Work ID is required", http.StatusBadRequest)
+		return
+	}
+
+	work, err := h.service.GetWorkByID(r.Context(), id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if work == nil {
+		http.Error(w, "Work not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(work)
+}
+
+// AddEditions handles the request to group books into a work as its editions.
+func (h *WorkHandler) AddEditions(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Work ID is required", http.StatusBadRequest)
+		return
+	}
+
+	var req models.WorkEditionsRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	work, err := h.service.AddEditions(r.Context(), id, req.BookIDs)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if work == nil {
+		http.Error(w, "Work not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(work)
+}
+
+// SplitEdition handles the request to remove an edition from a work, which
+// makes it the only edition of a new work returned in the response.
+func (h *WorkHandler) SplitEdition(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	bookID := chi.URLParam(r, "bookID")
+	if id == "" || bookID == "" {
+		http.Error(w, "Work ID and book ID are required", http.StatusBadRequest)
+		return
+	}
+
+	work, err := h.service.SplitEdition(r.Context(), id, bookID)
+	if err != nil {
+		http.Error(w, err.Error(), errorStatus(err))
+		return
+	}
+
+	if work == nil {
+		http.Error(w, "Work not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(work)
+}
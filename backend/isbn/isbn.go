@@ -0,0 +1,146 @@
+// Package isbn validates and normalises International Standard Book Numbers.
+//
+// Books are stored under their ISBN-13 without hyphens or spaces, so the
+// same book entered as "0-306-40615-2" or "978-0-306-40615-7" is recognised
+// as one.
+package isbn
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalidLength is returned for input that is not 10 or 13 characters
+	// long once separators are removed.
+	ErrInvalidLength = errors.New("isbn: must have 10 or 13 digits")
+	// ErrInvalidCharacter is returned for input containing anything other
+	// than digits, separators and a final ISBN-10 check character X.
+	ErrInvalidCharacter = errors.New("isbn: invalid character")
+	// ErrInvalidPrefix is returned for an ISBN-13 that does not start with
+	// 978 or 979, such as an ISSN (977) or another EAN-13 barcode.
+	ErrInvalidPrefix = errors.New("isbn: ISBN-13 must start with 978 or 979")
+	// ErrInvalidChecksum is returned when the check digit does not match.
+	ErrInvalidChecksum = errors.New("isbn: check digit does not match")
+	// ErrNoISBN10 is returned when converting an ISBN-13 with a 979 prefix,
+	// which has no ISBN-10 form.
+	ErrNoISBN10 = errors.New("isbn: only 978-prefixed ISBN-13s have an ISBN-10 form")
+)
+
+// Strip removes hyphens and spaces and upper-cases a trailing x.
+func Strip(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' || r == '‐' || r == '‑' {
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(s))
+	return strings.ToUpper(s)
+}
+
+// Validate reports whether s is a valid ISBN-10 or ISBN-13, ignoring hyphens and spaces.
+func Validate(s string) error {
+	s = Strip(s)
+	switch len(s) {
+	case 10:
+		return validate10(s)
+	case 13:
+		return validate13(s)
+	default:
+		return fmt.Errorf("%w, got %d", ErrInvalidLength, len(s))
+	}
+}
+
+// Normalize validates s and returns it as an ISBN-13 without separators.
+func Normalize(s string) (string, error) {
+	s = Strip(s)
+	if err := Validate(s); err != nil {
+		return "", err
+	}
+	if len(s) == 10 {
+		return convert10To13(s), nil
+	}
+	return s, nil
+}
+
+// ToISBN13 converts a valid ISBN-10 or ISBN-13 to an ISBN-13 without separators.
+func ToISBN13(s string) (string, error) {
+	return Normalize(s)
+}
+
+// ToISBN10 converts a valid ISBN-10 or 978-prefixed ISBN-13 to an ISBN-10
+// without separators.
+func ToISBN10(s string) (string, error) {
+	s, err := Normalize(s)
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(s, "978") {
+		return "", ErrNoISBN10
+	}
+	body := s[3:12]
+	return body + string(checkDigit10(body)), nil
+}
+
+func validate10(s string) error {
+	for i, r := range s {
+		if !isDigit(r) && !(i == 9 && r == 'X') {
+			return fmt.Errorf("%w %q", ErrInvalidCharacter, r)
+		}
+	}
+	if checkDigit10(s[:9]) != s[9] {
+		return ErrInvalidChecksum
+	}
+	return nil
+}
+
+func validate13(s string) error {
+	for _, r := range s {
+		if !isDigit(r) {
+			return fmt.Errorf("%w %q", ErrInvalidCharacter, r)
+		}
+	}
+	if !strings.HasPrefix(s, "978") && !strings.HasPrefix(s, "979") {
+		return ErrInvalidPrefix
+	}
+	if checkDigit13(s[:12]) != s[12] {
+		return ErrInvalidChecksum
+	}
+	return nil
+}
+
+// checkDigit10 computes the ISBN-10 check character for nine digits.
+func checkDigit10(digits string) byte {
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(digits[i]-'0') * (10 - i)
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return 'X'
+	}
+	return byte('0' + check)
+}
+
+// checkDigit13 computes the ISBN-13 check digit for twelve digits.
+func checkDigit13(digits string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		d := int(digits[i] - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func convert10To13(s string) string {
+	body := "978" + s[:9]
+	return body + string(checkDigit13(body))
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
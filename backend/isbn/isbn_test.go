@@ -0,0 +1,89 @@
+package isbn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want error
+	}{
+		{"0306406152", nil},
+		{"0-306-40615-2", nil},
+		{" 0 306 40615 2 ", nil},
+		{"080442957X", nil},
+		{"0-8044-2957-x", nil},
+		{"9780306406157", nil},
+		{"978-0-306-40615-7", nil},
+		{"9791090636071", nil},
+		{"0306406153", ErrInvalidChecksum},
+		{"9780306406158", ErrInvalidChecksum},
+		{"03064O6152", ErrInvalidCharacter},
+		{"X306406152", ErrInvalidCharacter},
+		{"978030640615X", ErrInvalidCharacter},
+		{"9770306406158", ErrInvalidPrefix},
+		{"", ErrInvalidLength},
+		{"12345", ErrInvalidLength},
+		{"97803064061577", ErrInvalidLength},
+	}
+	for _, tt := range tests {
+		if err := Validate(tt.in); !errors.Is(err, tt.want) {
+			t.Errorf("Validate(%q) = %v, want %v", tt.in, err, tt.want)
+		}
+	}
+}
+
+func TestToISBN13(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"0-306-40615-2", "9780306406157"},
+		{"080442957X", "9780804429573"},
+		{"080442957x", "9780804429573"},
+		{"978-0-306-40615-7", "9780306406157"},
+		{"979-10-90636-07-1", "9791090636071"},
+	}
+	for _, tt := range tests {
+		got, err := ToISBN13(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ToISBN13(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := ToISBN13("0306406153"); !errors.Is(err, ErrInvalidChecksum) {
+		t.Errorf("ToISBN13 of a bad check digit = %v, want %v", err, ErrInvalidChecksum)
+	}
+}
+
+func TestToISBN10(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"9780306406157", "0306406152"},
+		{"978-0-8044-2957-3", "080442957X"},
+		{"0-306-40615-2", "0306406152"},
+	}
+	for _, tt := range tests {
+		got, err := ToISBN10(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ToISBN10(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := ToISBN10("9791090636071"); !errors.Is(err, ErrNoISBN10) {
+		t.Errorf("ToISBN10 of a 979 ISBN = %v, want %v", err, ErrNoISBN10)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, isbn10 := range []string{"0306406152", "080442957X", "0131103628", "0000000000"} {
+		isbn13, err := ToISBN13(isbn10)
+		if err != nil {
+			t.Fatalf("ToISBN13(%q): %v", isbn10, err)
+		}
+		back, err := ToISBN10(isbn13)
+		if err != nil || back != isbn10 {
+			t.Errorf("ToISBN10(ToISBN13(%q)) = %q, %v", isbn10, back, err)
+		}
+	}
+}
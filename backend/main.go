@@ -1,8 +1,10 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"net/http"
+	"os"
 
 	"book-recommendation-system/backend/database"
 	"github.com/go-chi/chi/v5"
@@ -10,17 +12,16 @@ import (
 	"book-recommendation-system/backend/handlers"
 	"book-recommendation-system/backend/repositories"
 	"book-recommendation-system/backend/services"
+	"book-recommendation-system/backend/storage"
 )
 
+// defaultCoverStorageDir is where uploaded covers are kept unless
+// COVER_STORAGE_DIR says otherwise.
+const defaultCoverStorageDir = "data/covers"
+
 func main() {
 	// Database configuration
-	dbConfig := database.Config{
-		Host:     "localhost",
-		Port:     "5432",
-		User:     "user",     // Replace with your DB user
-		Password: "password", // Replace with your DB password
-		DBName:   "bookrecsys",
-	}
+	dbConfig := database.DefaultConfig()
 
 	db, err := database.ConnectDB(dbConfig)
 	if err != nil {
@@ -29,6 +30,15 @@ func main() {
 	defer database.CloseDB(db)
 	log.Println("Successfully connected to the database!")
 
+	coverStorageDir := os.Getenv("COVER_STORAGE_DIR")
+	if coverStorageDir == "" {
+		coverStorageDir = defaultCoverStorageDir
+	}
+	coverStore, err := storage.NewLocalStore(coverStorageDir)
+	if err != nil {
+		log.Fatalf("Failed to open cover storage: %v", err)
+	}
+
 	// Initialize repositories
 	bookRepo := repositories.NewBookRepository(db)
 	authorRepo := repositories.NewAuthorRepository(db)
@@ -36,14 +46,28 @@ func main() {
 	libraryRepo := repositories.NewLibraryRepository(db)
 	userInteractionRepo := repositories.NewUserInteractionRepository(db)
 	recommendationRepo := repositories.NewRecommendationRepository(db)
+	holdingRepo := repositories.NewHoldingRepository(db)
+	importJobRepo := repositories.NewImportJobRepository(db)
+	workRepo := repositories.NewWorkRepository(db)
+	genreSuggestionRepo := repositories.NewGenreSuggestionRepository(db)
+	loanRepo := repositories.NewLoanRepository(db)
+	holdRepo := repositories.NewHoldRepository(db)
 
 	// Initialize services
 	bookService := services.NewBookService(bookRepo)
 	authorService := services.NewAuthorService(authorRepo)
-	genreService := services.NewGenreService(genreRepo)
+	genreService := services.NewGenreService(genreRepo, bookRepo)
 	libraryService := services.NewLibraryService(libraryRepo)
 	userInteractionService := services.NewUserInteractionService(userInteractionRepo)
-	recommendationService := services.NewRecommendationService(recommendationRepo)
+	recommendationService := services.NewRecommendationService(recommendationRepo, userInteractionRepo, holdingRepo, bookRepo, genreRepo)
+	catalogImportService := services.NewCatalogImportService(bookRepo, authorRepo, genreRepo, libraryRepo, holdingRepo, importJobRepo)
+	workService := services.NewWorkService(workRepo, bookRepo)
+	duplicateService := services.NewDuplicateService(bookRepo, holdingRepo, libraryRepo, holdRepo)
+	coverService := services.NewCoverService(bookRepo, coverStore)
+	genreSuggestionService := services.NewGenreSuggestionService(bookRepo, genreSuggestionRepo)
+	holdingService := services.NewHoldingService(holdingRepo, bookRepo, libraryRepo, holdRepo)
+	loanService := services.NewLoanService(loanRepo, holdingRepo, libraryRepo, holdRepo)
+	holdService := services.NewHoldService(holdRepo, bookRepo, holdingRepo, loanRepo, libraryRepo)
 
 	// Initialize handlers
 	bookHandler := handlers.NewBookHandler(bookService)
@@ -52,6 +76,14 @@ func main() {
 	libraryHandler := handlers.NewLibraryHandler(libraryService)
 	userInteractionHandler := handlers.NewUserInteractionHandler(userInteractionService)
 	recommendationHandler := handlers.NewRecommendationHandler(recommendationService)
+	importHandler := handlers.NewImportHandler(catalogImportService)
+	workHandler := handlers.NewWorkHandler(workService)
+	duplicateHandler := handlers.NewDuplicateHandler(duplicateService)
+	coverHandler := handlers.NewCoverHandler(coverService)
+	genreSuggestionHandler := handlers.NewGenreSuggestionHandler(genreSuggestionService)
+	holdingHandler := handlers.NewHoldingHandler(holdingService)
+	loanHandler := handlers.NewLoanHandler(loanService)
+	holdHandler := handlers.NewHoldHandler(holdService)
 	r := chi.NewRouter()
 
 	r.Use(middleware.RequestID)
@@ -60,14 +92,14 @@ func main() {
 	r.Use(middleware.URLFormat)
 
 	// Setup routes
-	setupRoutes(r, bookHandler, authorHandler, genreHandler, libraryHandler, userInteractionHandler, recommendationHandler)
+	setupRoutes(r, bookHandler, authorHandler, genreHandler, libraryHandler, userInteractionHandler, recommendationHandler, importHandler, workHandler, duplicateHandler, coverHandler, genreSuggestionHandler, holdingHandler, loanHandler, holdHandler)
 
 	fmt.Println("Server starting on port :8080...")
 	log.Fatal(http.ListenAndServe(":8080", r))
 }
 
 // setupRoutes configures all the API routes.
-func setupRoutes(r *chi.Mux, bookH *handlers.BookHandler, authorH *handlers.AuthorHandler, genreH *handlers.GenreHandler, libraryH *handlers.LibraryHandler, userInteractionH *handlers.UserInteractionHandler, recommendationH *handlers.RecommendationHandler) {
+func setupRoutes(r *chi.Mux, bookH *handlers.BookHandler, authorH *handlers.AuthorHandler, genreH *handlers.GenreHandler, libraryH *handlers.LibraryHandler, userInteractionH *handlers.UserInteractionHandler, recommendationH *handlers.RecommendationHandler, importH *handlers.ImportHandler, workH *handlers.WorkHandler, duplicateH *handlers.DuplicateHandler, coverH *handlers.CoverHandler, genreSuggestionH *handlers.GenreSuggestionHandler, holdingH *handlers.HoldingHandler, loanH *handlers.LoanHandler, holdH *handlers.HoldHandler) {
 	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("Welcome to the Book Recommendation System Backend!"))
 	})
@@ -75,15 +107,24 @@ func setupRoutes(r *chi.Mux, bookH *handlers.BookHandler, authorH *handlers.Auth
 	r.Route("/books", func(r chi.Router) {
 		r.Post("/", bookH.CreateBook)
 		r.Get("/", bookH.GetAllBooks)
+		r.Get("/search", bookH.SearchBooks)
+		r.Post("/import", importH.ImportBooks)
+		r.Get("/isbn/{isbn}", bookH.GetBookByISBN)
 		r.Get("/{id}", bookH.GetBookByID)
 		r.Put("/{id}", bookH.UpdateBook)
 		r.Delete("/{id}", bookH.DeleteBook)
+		r.Put("/{id}/cover", coverH.UploadCover)
+		r.Get("/{id}/cover", coverH.GetCover)
+		r.Get("/{id}/availability", holdingH.GetBookAvailability)
+		r.Get("/{id}/holds", holdH.GetBookHoldQueue)
 	})
 
 	r.Route("/authors", func(r chi.Router) {
 		r.Post("/", authorH.CreateAuthor)
 		r.Get("/", authorH.GetAllAuthors)
 		r.Get("/{id}", authorH.GetAuthorByID)
+		r.Get("/{id}/books", authorH.GetAuthorBooks)
+		r.Get("/{id}/related", authorH.GetRelatedAuthors)
 		r.Put("/{id}", authorH.UpdateAuthor)
 		r.Delete("/{id}", authorH.DeleteAuthor)
 	})
@@ -91,7 +132,10 @@ func setupRoutes(r *chi.Mux, bookH *handlers.BookHandler, authorH *handlers.Auth
 	r.Route("/genres", func(r chi.Router) {
 		r.Post("/", genreH.CreateGenre)
 		r.Get("/", genreH.GetAllGenres)
+		r.Get("/tree", genreH.GetGenreTree)
 		r.Get("/{id}", genreH.GetGenreByID)
+		r.Get("/{id}/tree", genreH.GetGenreSubtree)
+		r.Get("/{id}/books", genreH.GetGenreBooks)
 		r.Put("/{id}", genreH.UpdateGenre)
 		r.Delete("/{id}", genreH.DeleteGenre) // Added missing delete
 	})
@@ -99,11 +143,39 @@ func setupRoutes(r *chi.Mux, bookH *handlers.BookHandler, authorH *handlers.Auth
 	r.Route("/libraries", func(r chi.Router) {
 		r.Post("/", libraryH.CreateLibrary)
 		r.Get("/", libraryH.GetAllLibraries)
+		r.Get("/nearby", libraryH.GetNearbyLibraries)
 		r.Get("/{id}", libraryH.GetLibraryByID)
+		r.Get("/{id}/books", holdingH.GetLibraryBooks)
 		r.Put("/{id}", libraryH.UpdateLibrary)
 		r.Delete("/{id}", libraryH.DeleteLibrary) // Added missing delete
 	})
 
+	r.Route("/holdings", func(r chi.Router) {
+		r.Post("/", holdingH.CreateHolding)
+		r.Get("/", holdingH.GetHoldings)
+		r.Get("/{id}", holdingH.GetHoldingByID)
+		r.Put("/{id}", holdingH.UpdateHolding)
+		r.Delete("/{id}", holdingH.DeleteHolding)
+	})
+
+	r.Route("/loans", func(r chi.Router) {
+		r.Post("/", loanH.CheckOut)
+		r.Get("/", loanH.GetLoans)
+		r.Get("/overdue", loanH.GetOverdueLoans)
+		r.Post("/check-in", loanH.CheckInByBarcode)
+		r.Get("/{id}", loanH.GetLoanByID)
+		r.Post("/{id}/renew", loanH.RenewLoan)
+		r.Post("/{id}/check-in", loanH.CheckIn)
+	})
+
+	r.Route("/holds", func(r chi.Router) {
+		r.Post("/", holdH.PlaceHold)
+		r.Get("/", holdH.GetHolds)
+		r.Get("/{id}", holdH.GetHoldByID)
+		r.Get("/{id}/position", holdH.GetHoldPosition)
+		r.Post("/{id}/cancel", holdH.CancelHold)
+	})
+
 	r.Route("/user-interactions", func(r chi.Router) {
 		r.Post("/", userInteractionH.CreateUserInteraction)
 		r.Get("/", userInteractionH.GetAllUserInteractions) // Changed to GetAll
@@ -115,10 +187,36 @@ func setupRoutes(r *chi.Mux, bookH *handlers.BookHandler, authorH *handlers.Auth
 
 	r.Route("/recommendations", func(r chi.Router) {
 		r.Post("/", recommendationH.CreateRecommendation)
+		r.Post("/group", recommendationH.GetGroupRecommendations)
 		r.Get("/", recommendationH.GetAllRecommendations) // Changed to GetAll
 		r.Get("/{id}", recommendationH.GetRecommendationByID)
 		r.Get("/user/{userID}", recommendationH.GetRecommendationsByUserID)
+		r.Get("/user/{userID}/realtime", recommendationH.GetRealtimeRecommendationsByUserID)
 		r.Put("/{id}", recommendationH.UpdateRecommendation)
 		r.Delete("/{id}", recommendationH.DeleteRecommendation)
 	})
+
+	r.Route("/imports", func(r chi.Router) {
+		r.Get("/{id}", importH.GetImportJob)
+	})
+
+	r.Route("/works", func(r chi.Router) {
+		r.Get("/{id}", workH.GetWorkByID)
+		r.Post("/{id}/editions", workH.AddEditions)
+		r.Delete("/{id}/editions/{bookID}", workH.SplitEdition)
+	})
+
+	r.Route("/admin", func(r chi.Router) {
+		r.Get("/books/duplicates", duplicateH.GetDuplicates)
+		r.Post("/books/duplicates/merge", duplicateH.MergeBooks)
+		r.Get("/genre-suggestions", genreSuggestionH.GetSuggestions)
+		r.Post("/genre-suggestions/generate", genreSuggestionH.GenerateSuggestions)
+		r.Post("/genre-suggestions/{id}/accept", genreSuggestionH.AcceptSuggestion)
+		r.Post("/genre-suggestions/{id}/reject", genreSuggestionH.RejectSuggestion)
+		r.Post("/holds/expire", holdH.ExpireHolds)
+	})
+
+	r.Route("/users/{userID}", func(r chi.Router) {
+		r.Get("/continue-reading", recommendationH.GetContinueReading)
+	})
 }
@@ -0,0 +1,121 @@
+package marc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ISO 2709 delimiters.
+const (
+	subfieldDelimiter = 0x1F
+	fieldTerminator   = 0x1E
+	recordTerminator  = 0x1D
+)
+
+const (
+	leaderLength         = 24
+	directoryEntryLength = 12
+)
+
+// Reader reads binary MARC 21 (ISO 2709) records.
+type Reader struct {
+	r     *bufio.Reader
+	index int
+}
+
+// NewReader returns a Reader reading records from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Read returns the next record, or io.EOF when there are no more. Errors
+// wrapping ErrMalformedRecord affect only the record just read.
+func (mr *Reader) Read() (*Record, error) {
+	// Records are located by their terminator rather than by the length in
+	// the leader, so one record with a wrong length does not lose the rest.
+	raw, err := mr.r.ReadBytes(recordTerminator)
+	// Tolerate line breaks some tools write between records.
+	raw = bytes.TrimLeft(raw, "\r\n")
+	if len(raw) == 0 && errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("marc: failed to read record: %w", err)
+	}
+	mr.index++
+	if raw[len(raw)-1] != recordTerminator {
+		return nil, fmt.Errorf("%w %d: missing record terminator", ErrMalformedRecord, mr.index)
+	}
+	rec, perr := parseRecord(raw)
+	if perr != nil {
+		return nil, fmt.Errorf("%w %d: %v", ErrMalformedRecord, mr.index, perr)
+	}
+	return rec, nil
+}
+
+// parseRecord decodes one record, including its terminator.
+func parseRecord(raw []byte) (*Record, error) {
+	if len(raw) < leaderLength+1 {
+		return nil, errors.New("record shorter than its leader")
+	}
+	leader := raw[:leaderLength]
+	base, ok := parseDigits(leader[12:17])
+	if !ok || base <= leaderLength || base > len(raw) {
+		return nil, fmt.Errorf("invalid base address of data %q", leader[12:17])
+	}
+	directory := raw[leaderLength : base-1]
+	if raw[base-1] != fieldTerminator || len(directory)%directoryEntryLength != 0 {
+		return nil, errors.New("invalid directory")
+	}
+	data := raw[base:]
+
+	rec := &Record{Leader: string(leader)}
+	for i := 0; i < len(directory); i += directoryEntryLength {
+		entry := directory[i : i+directoryEntryLength]
+		tag := string(entry[:3])
+		length, ok1 := parseDigits(entry[3:7])
+		start, ok2 := parseDigits(entry[7:12])
+		if !ok1 || !ok2 || length < 1 || start+length > len(data) {
+			return nil, fmt.Errorf("invalid directory entry for field %s", tag)
+		}
+		field := bytes.TrimSuffix(data[start:start+length], []byte{fieldTerminator})
+
+		if isControlTag(tag) {
+			rec.ControlFields = append(rec.ControlFields, ControlField{Tag: tag, Value: string(field)})
+			continue
+		}
+		if len(field) < 2 {
+			return nil, fmt.Errorf("field %s has no indicators", tag)
+		}
+		df := DataField{Tag: tag, Ind1: field[0], Ind2: field[1]}
+		for _, sf := range bytes.Split(field[2:], []byte{subfieldDelimiter}) {
+			if len(sf) == 0 {
+				continue // the split yields an empty element before the first delimiter
+			}
+			df.Subfields = append(df.Subfields, Subfield{Code: sf[0], Value: string(sf[1:])})
+		}
+		rec.DataFields = append(rec.DataFields, df)
+	}
+	return rec, nil
+}
+
+// parseDigits decodes a fixed-width unsigned decimal number, such as a
+// directory entry's length or starting position. Unlike strconv.Atoi it
+// rejects signs and spaces, which would otherwise let an entry point outside
+// the record.
+func parseDigits(b []byte) (int, bool) {
+	if len(b) == 0 {
+		return 0, false
+	}
+	n := 0
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		n = n*10 + int(c-'0')
+	}
+	return n, true
+}
@@ -0,0 +1,99 @@
+package marc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// buildRecord encodes fields, given as tag and body without terminator, as
+// one binary MARC record.
+func buildRecord(fields ...[2]string) []byte {
+	var dir, data strings.Builder
+	for _, f := range fields {
+		body := f[1] + string(rune(fieldTerminator))
+		fmt.Fprintf(&dir, "%s%04d%05d", f[0], len(body), data.Len())
+		data.WriteString(body)
+	}
+	base := leaderLength + dir.Len() + 1
+	total := base + data.Len() + 1
+	return []byte(fmt.Sprintf("%05dnam a22%05d a 4500", total, base) + dir.String() + string(rune(fieldTerminator)) + data.String() + string(rune(recordTerminator)))
+}
+
+// patch returns a copy of raw with s written at offset.
+func patch(raw []byte, offset int, s string) []byte {
+	out := bytes.Clone(raw)
+	copy(out[offset:], s)
+	return out
+}
+
+var testFields = [][2]string{
+	{"001", "ocm12345"},
+	{"245", "10\x1faThe hobbit /\x1fcJ.R.R. Tolkien."},
+}
+
+// Offsets of the second directory entry's length and starting position.
+const (
+	secondLength = leaderLength + directoryEntryLength + 3
+	secondStart  = leaderLength + directoryEntryLength + 7
+)
+
+func TestReadBinary(t *testing.T) {
+	raw := buildRecord(testFields...)
+	rec, err := NewReader(bytes.NewReader(raw)).Read()
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(rec.ControlFields) != 1 || rec.ControlFields[0].Value != "ocm12345" {
+		t.Errorf("ControlFields = %+v", rec.ControlFields)
+	}
+	if len(rec.DataFields) != 1 || len(rec.DataFields[0].Subfields) != 2 || rec.DataFields[0].Subfields[0].Value != "The hobbit /" {
+		t.Errorf("DataFields = %+v", rec.DataFields)
+	}
+}
+
+func TestReadBinaryMalformed(t *testing.T) {
+	raw := buildRecord(testFields...)
+	truncated := append(bytes.Clone(raw[:len(raw)-10]), recordTerminator)
+
+	tests := []struct {
+		name string
+		raw  []byte
+	}{
+		{"shorter than leader", []byte("00010nam\x1d")},
+		{"truncated data", truncated},
+		{"negative start", patch(raw, secondStart, "-0001")},
+		{"negative length", patch(raw, secondLength, "-001")},
+		{"length past end", patch(raw, secondLength, "9999")},
+		{"start past end", patch(raw, secondStart, "99999")},
+		{"spaces in entry", patch(raw, secondStart, "    9")},
+		{"signed base address", patch(raw, 12, "+0049")},
+		{"base address past end", patch(raw, 12, "99999")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReader(bytes.NewReader(tt.raw))
+			if _, err := r.Read(); !errors.Is(err, ErrMalformedRecord) {
+				t.Fatalf("Read() error = %v, want %v", err, ErrMalformedRecord)
+			}
+			if _, err := r.Read(); err != io.EOF {
+				t.Errorf("second Read() error = %v, want io.EOF", err)
+			}
+		})
+	}
+}
+
+func TestReadBinarySkipsMalformedRecord(t *testing.T) {
+	good := buildRecord(testFields...)
+	bad := patch(good, secondStart, "-0001")
+	r := NewReader(bytes.NewReader(append(bad, good...)))
+	if _, err := r.Read(); !errors.Is(err, ErrMalformedRecord) {
+		t.Fatalf("first Read() error = %v, want %v", err, ErrMalformedRecord)
+	}
+	if _, err := r.Read(); err != nil {
+		t.Errorf("second Read() error = %v, want the next record", err)
+	}
+}
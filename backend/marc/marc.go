@@ -0,0 +1,93 @@
+// Package marc reads bibliographic records in MARC 21, both the binary
+// ISO 2709 transmission format and MARCXML.
+//
+// Only the record structure is decoded; interpreting tags is left to the
+// caller. Binary records whose leader does not declare UTF-8 (leader/09 "a")
+// are assumed to be MARC-8 and are passed through unconverted, which is
+// correct for their ASCII subset.
+package marc
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrMalformedRecord is wrapped by errors for a record that could not be
+// decoded. The reader stays positioned at the next record, so reading can
+// continue after such an error.
+var ErrMalformedRecord = errors.New("marc: malformed record")
+
+// Record is one MARC record.
+type Record struct {
+	Leader        string
+	ControlFields []ControlField
+	DataFields    []DataField
+}
+
+// ControlField is a field with tag 001-009, which has a value but no
+// indicators or subfields.
+type ControlField struct {
+	Tag   string
+	Value string
+}
+
+// DataField is a field with tag 010-999.
+type DataField struct {
+	Tag       string
+	Ind1      byte
+	Ind2      byte
+	Subfields []Subfield
+}
+
+// Subfield is one coded element of a data field.
+type Subfield struct {
+	Code  byte
+	Value string
+}
+
+// ControlField returns the value of the first control field with tag, or "".
+func (r *Record) ControlField(tag string) string {
+	for _, f := range r.ControlFields {
+		if f.Tag == tag {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+// Fields returns the data fields with tag, in record order.
+func (r *Record) Fields(tag string) []DataField {
+	var fields []DataField
+	for _, f := range r.DataFields {
+		if f.Tag == tag {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// Subfield returns the value of the first subfield with code, or "".
+func (f DataField) Subfield(code byte) string {
+	for _, sf := range f.Subfields {
+		if sf.Code == code {
+			return sf.Value
+		}
+	}
+	return ""
+}
+
+// SubfieldValues returns the values of every subfield with code.
+func (f DataField) SubfieldValues(code byte) []string {
+	var values []string
+	for _, sf := range f.Subfields {
+		if sf.Code == code {
+			values = append(values, sf.Value)
+		}
+	}
+	return values
+}
+
+// isControlTag reports whether tag is a control field tag (001-009).
+func isControlTag(tag string) bool {
+	return strings.HasPrefix(tag, "00")
+}
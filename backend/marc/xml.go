@@ -0,0 +1,96 @@
+package marc
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// xmlRecord is the MARCXML form of a record. Element names are matched in
+// any namespace, so documents with or without the MARC 21 slim namespace
+// are accepted.
+type xmlRecord struct {
+	Leader        string `xml:"leader"`
+	ControlFields []struct {
+		Tag   string `xml:"tag,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"controlfield"`
+	DataFields []struct {
+		Tag       string `xml:"tag,attr"`
+		Ind1      string `xml:"ind1,attr"`
+		Ind2      string `xml:"ind2,attr"`
+		Subfields []struct {
+			Code  string `xml:"code,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"subfield"`
+	} `xml:"datafield"`
+}
+
+// XMLReader reads MARCXML records, either a single <record> or any number
+// of them inside a <collection>, without loading the whole document.
+type XMLReader struct {
+	d     *xml.Decoder
+	index int
+}
+
+// NewXMLReader returns an XMLReader reading records from r.
+func NewXMLReader(r io.Reader) *XMLReader {
+	return &XMLReader{d: xml.NewDecoder(r)}
+}
+
+// Read returns the next record, or io.EOF when there are no more. Errors
+// wrapping ErrMalformedRecord affect only the record just read; any other
+// error means the document cannot be read further.
+func (xr *XMLReader) Read() (*Record, error) {
+	for {
+		tok, err := xr.d.Token()
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("marc: failed to read MARCXML: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+
+		var raw xmlRecord
+		if err := xr.d.DecodeElement(&raw, &start); err != nil {
+			return nil, fmt.Errorf("marc: failed to read MARCXML: %w", err)
+		}
+		xr.index++
+		return raw.record(xr.index)
+	}
+}
+
+func (raw *xmlRecord) record(index int) (*Record, error) {
+	rec := &Record{Leader: raw.Leader}
+	for _, cf := range raw.ControlFields {
+		rec.ControlFields = append(rec.ControlFields, ControlField{Tag: cf.Tag, Value: cf.Value})
+	}
+	for _, f := range raw.DataFields {
+		if len(f.Tag) != 3 || isControlTag(f.Tag) {
+			return nil, fmt.Errorf("%w %d: invalid data field tag %q", ErrMalformedRecord, index, f.Tag)
+		}
+		df := DataField{Tag: f.Tag, Ind1: indicator(f.Ind1), Ind2: indicator(f.Ind2)}
+		for _, sf := range f.Subfields {
+			if len(sf.Code) != 1 {
+				return nil, fmt.Errorf("%w %d: invalid subfield code %q in field %s", ErrMalformedRecord, index, sf.Code, f.Tag)
+			}
+			df.Subfields = append(df.Subfields, Subfield{Code: sf.Code[0], Value: sf.Value})
+		}
+		rec.DataFields = append(rec.DataFields, df)
+	}
+	return rec, nil
+}
+
+// indicator returns an indicator attribute as a byte, blank when absent.
+func indicator(s string) byte {
+	if s = strings.TrimSpace(s); s == "" {
+		return ' '
+	}
+	return s[0]
+}
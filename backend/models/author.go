@@ -1,7 +1,48 @@
 package models
 
+import "github.com/lib/pq"
+
 type Author struct {
 	ID        string `json:"id" db:"id"`
 	Name      string `json:"name" db:"name"`
 	Biography string `json:"biography" db:"biography"`
 }
+
+// AuthorBook is a book an author contributed to, with their roles on it.
+type AuthorBook struct {
+	Book
+	Roles pq.StringArray `json:"roles" db:"roles"`
+}
+
+// GenreCount is a genre with the number of books in it.
+type GenreCount struct {
+	Genre
+	BookCount int `json:"book_count" db:"book_count"`
+}
+
+// AuthorStats aggregates the books an author wrote and how readers engage
+// with them.
+type AuthorStats struct {
+	BookCount     int          `json:"book_count" db:"book_count"`
+	Readers       int          `json:"readers" db:"readers"` // distinct users who read, borrowed or rated a book
+	RatingCount   int          `json:"rating_count" db:"rating_count"`
+	AverageRating float64      `json:"average_rating" db:"average_rating"` // 0 if no book has been rated
+	TopGenres     []GenreCount `json:"top_genres" db:"-"`
+}
+
+// AuthorBibliography is an author with their books, oldest first, and
+// statistics for an author page.
+type AuthorBibliography struct {
+	Author
+	Stats AuthorStats  `json:"stats"`
+	Books []AuthorBook `json:"books"`
+}
+
+// RelatedAuthor is an author whose books are read by readers of another.
+// Similarity is the shared readers as a share of the geometric mean of both
+// authors' readers, between 0 and 1.
+type RelatedAuthor struct {
+	Author
+	SharedReaders int     `json:"shared_readers" db:"shared_readers"`
+	Similarity    float64 `json:"similarity" db:"similarity"`
+}
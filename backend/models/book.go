@@ -1,12 +1,117 @@
 package models
 
+import "github.com/lib/pq"
+
 type Book struct {
-	ID              string `json:"id" db:"id"`
-	Title           string `json:"title" db:"title"`
-	Author          string `json:"author" db:"author"`
-	ISBN            string `json:"isbn" db:"isbn"`
-	Description     string `json:"description" db:"description"`
-	CoverImageURL   string `json:"cover_image_url" db:"cover_image_url"`
-	Genre           string `json:"genre" db:"genre"`
-	PublicationYear int    `json:"publication_year" db:"publication_year"`
+	ID              string  `json:"id" db:"id"`
+	Title           string  `json:"title" db:"title"`
+	Author          string  `json:"author" db:"author"`
+	ISBN            string  `json:"isbn" db:"isbn"`
+	Description     string  `json:"description" db:"description"`
+	CoverImageURL   string  `json:"cover_image_url" db:"cover_image_url"`
+	Genre           string  `json:"genre" db:"genre"`
+	PublicationYear int     `json:"publication_year" db:"publication_year"`
+	Series          string  `json:"series,omitempty" db:"series"`
+	SeriesPosition  float64 `json:"series_position,omitempty" db:"series_position"` // e.g., 1, 2, 2.5 for a novella
+	Language        string  `json:"language,omitempty" db:"language"`               // ISO 639 code, e.g., "en"
+	PageCount       int     `json:"page_count,omitempty" db:"page_count"`
+	Publisher       string  `json:"publisher,omitempty" db:"publisher"`
+	Format          string  `json:"format,omitempty" db:"format"`   // one of the BookFormat constants
+	Edition         string  `json:"edition,omitempty" db:"edition"` // e.g., "2nd edition"
+	// Subjects are free subject tags, such as "dragons" or "world war ii",
+	// stored lower-cased.
+	Subjects pq.StringArray `json:"subjects,omitempty" db:"subjects"`
+
+	// WorkID is the work this book is an edition of. A book created without
+	// one becomes the only edition of a new work.
+	WorkID string `json:"work_id" db:"work_id"`
+
+	// Authors and Genres are the linked author and genre records. On create
+	// and update a nil slice leaves the links unchanged.
+	Authors []BookAuthor `json:"authors,omitempty" db:"-"`
+	Genres  []BookGenre  `json:"genres,omitempty" db:"-"`
+}
+
+// Book formats.
+const (
+	BookFormatHardcover = "hardcover"
+	BookFormatPaperback = "paperback"
+	BookFormatEbook     = "ebook"
+	BookFormatAudio     = "audio"
+)
+
+// Contributor roles for BookAuthor.
+const (
+	AuthorRoleAuthor      = "author"
+	AuthorRoleTranslator  = "translator"
+	AuthorRoleIllustrator = "illustrator"
+)
+
+// BookAuthor is an author linked to a book in a given role.
+type BookAuthor struct {
+	BookID string `json:"-" db:"book_id"`
+	Author
+	Role string `json:"role" db:"role"`
+}
+
+// BookGenre is a genre linked to a book.
+type BookGenre struct {
+	BookID string `json:"-" db:"book_id"`
+	Genre
+}
+
+// ContinueReading is the next unread volume of a series a user has started.
+type ContinueReading struct {
+	Series           string  `json:"series"`
+	VolumesRead      int     `json:"volumes_read"`
+	LastReadPosition float64 `json:"last_read_position"`
+	NextBook         Book    `json:"next_book"`
+}
+
+// BookSearchResult is a book matching a full-text search, with its relevance
+// rank and the matching text highlighted with <mark> tags.
+type BookSearchResult struct {
+	Book
+	Rank           float64 `json:"rank" db:"rank"`
+	TitleHighlight string  `json:"title_highlight" db:"title_highlight"`
+	Snippet        string  `json:"snippet" db:"snippet"`
+}
+
+// BookFilter narrows and orders a catalogue query. Zero values mean "no filter".
+type BookFilter struct {
+	Genres     []string // books in any of the genres or their subgenres, by name or alias
+	GenreIDs   []string // books in any of the genres or their subgenres
+	Authors    []string
+	YearFrom   int
+	YearTo     int
+	Language   string
+	Formats    []string
+	Publishers []string
+	Subjects   []string // books with any of the subjects
+	PagesMin   int
+	PagesMax   int
+	LibraryID  string // only books the library holds a copy of
+	Available  *bool  // only books with (true) or without (false) an available copy, at LibraryID if set
+	Sort       string // e.g., "title", "-publication_year"
+}
+
+// FacetCount is the number of matching books with a given facet value.
+type FacetCount struct {
+	Value string `json:"value" db:"value"`
+	Count int    `json:"count" db:"count"`
+}
+
+// BookFacets holds facet counts for a catalogue query. Each facet is counted
+// with every filter applied except its own, so it lists the alternatives.
+type BookFacets struct {
+	Genres  []FacetCount `json:"genres"`
+	Authors []FacetCount `json:"authors"`
+	Decades []FacetCount `json:"decades"`
+	Formats []FacetCount `json:"formats"`
+}
+
+// BookQueryResult is a page of catalogue query results with facet counts.
+type BookQueryResult struct {
+	Page[Book]
+	Facets BookFacets `json:"facets"`
 }
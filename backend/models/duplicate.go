@@ -0,0 +1,34 @@
+package models
+
+// Reasons two books are reported as duplicates.
+const (
+	DuplicateReasonISBN        = "isbn"         // the same ISBN, possibly written differently
+	DuplicateReasonTitleAuthor = "title_author" // near-identical title and author
+)
+
+// DuplicateCandidate is a pair of books that look like the same catalogue
+// record, for review before merging. Book is the suggested survivor.
+type DuplicateCandidate struct {
+	Book       Book    `json:"book"`
+	Duplicate  Book    `json:"duplicate"`
+	Reason     string  `json:"reason"`
+	Similarity float64 `json:"similarity"` // from 0 to 1
+}
+
+// BookMergeRequest asks for duplicate books to be merged into a surviving one.
+type BookMergeRequest struct {
+	SurvivorID   string   `json:"survivor_id"`
+	DuplicateIDs []string `json:"duplicate_ids"`
+}
+
+// BookMergeResult reports what a merge moved to the surviving book.
+type BookMergeResult struct {
+	SurvivorID           string   `json:"survivor_id"`
+	MergedIDs            []string `json:"merged_ids"`
+	InteractionsMoved    int64    `json:"interactions_moved"`
+	RecommendationsMoved int64    `json:"recommendations_moved"`
+	HoldingsMoved        int64    `json:"holdings_moved"`
+	LoansMoved           int64    `json:"loans_moved"`
+	HoldsMoved           int64    `json:"holds_moved"`
+	HoldsCancelled       int64    `json:"holds_cancelled"` // a reader's second open hold on the merged book
+}
@@ -1,6 +1,16 @@
 package models
 
+import "github.com/lib/pq"
+
 type Genre struct {
-	ID   string `json:"id" db:"id"`
-	Name string `json:"name" db:"name"`
+	ID       string         `json:"id" db:"id"`
+	Name     string         `json:"name" db:"name"`
+	ParentID string         `json:"parent_id,omitempty" db:"parent_id"` // "" for a top-level genre
+	Aliases  pq.StringArray `json:"aliases,omitempty" db:"aliases"`     // other names for the genre, e.g., "Sci-Fi"
+}
+
+// GenreNode is a genre with its subgenres, for browsing the taxonomy.
+type GenreNode struct {
+	Genre
+	Children []GenreNode `json:"children"`
 }
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Genre suggestion review states.
+const (
+	GenreSuggestionPending  = "pending"
+	GenreSuggestionAccepted = "accepted"
+	GenreSuggestionRejected = "rejected"
+)
+
+// GenreSuggestion is a genre the classifier suggests for an untagged book,
+// awaiting review. Accepting it links the book to the genre.
+type GenreSuggestion struct {
+	ID         string     `json:"id" db:"id"`
+	BookID     string     `json:"book_id" db:"book_id"`
+	BookTitle  string     `json:"book_title" db:"book_title"`
+	GenreID    string     `json:"genre_id" db:"genre_id"`
+	GenreName  string     `json:"genre_name" db:"genre_name"`
+	Confidence float64    `json:"confidence" db:"confidence"` // from 0 to 1
+	Status     string     `json:"status" db:"status"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty" db:"reviewed_at"`
+}
+
+// GenreSuggestionRun reports what a classification run trained on and
+// suggested.
+type GenreSuggestionRun struct {
+	TrainingBooks   int `json:"training_books"`   // labelled books the classifier learnt from
+	Genres          int `json:"genres"`           // genres with enough examples to be suggested
+	BooksClassified int `json:"books_classified"` // untagged books with enough text to classify
+	Suggestions     int `json:"suggestions"`      // new suggestions awaiting review
+}
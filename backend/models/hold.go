@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Hold states. A hold waits in its book's queue until a copy is assigned to
+// it, is then ready for pickup, and is closed by the reader borrowing the
+// copy, cancelling, or running out of time.
+const (
+	HoldStatusWaiting   = "waiting"
+	HoldStatusReady     = "ready"
+	HoldStatusFulfilled = "fulfilled"
+	HoldStatusCancelled = "cancelled"
+	HoldStatusExpired   = "expired"
+)
+
+// Hold is a reader's place in the queue for a book.
+type Hold struct {
+	ID              string     `json:"id" db:"id"`
+	BookID          string     `json:"book_id" db:"book_id"`
+	UserID          string     `json:"user_id" db:"user_id"`
+	LibraryID       string     `json:"library_id,omitempty" db:"library_id"` // only copies at this library fill the hold; empty for any library
+	PickupLibraryID string     `json:"pickup_library_id" db:"pickup_library_id"`
+	Status          string     `json:"status" db:"status"`
+	HoldingID       string     `json:"holding_id,omitempty" db:"holding_id"` // the copy waiting on the hold shelf
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty" db:"expires_at"` // the reader no longer wants the book after this
+	ReadyAt         *time.Time `json:"ready_at,omitempty" db:"ready_at"`
+	PickupBy        *time.Time `json:"pickup_by,omitempty" db:"pickup_by"`
+	ClosedAt        *time.Time `json:"closed_at,omitempty" db:"closed_at"`
+}
+
+// HoldFilter narrows a listing of holds. Zero values mean "no filter".
+type HoldFilter struct {
+	UserID          string
+	BookID          string
+	PickupLibraryID string
+	Statuses        []string // holds in any of the statuses
+}
+
+// HoldPosition is where a hold stands in its book's queue and when a copy
+// is expected to be ready for it.
+type HoldPosition struct {
+	HoldID           string     `json:"hold_id"`
+	Status           string     `json:"status"`
+	Position         int        `json:"position"`        // 1 for the next hold to be filled; 0 once ready
+	QueueLength      int        `json:"queue_length"`    // waiting holds for the book
+	EligibleCopies   int        `json:"eligible_copies"` // copies that can fill the hold, whatever their state
+	EstimatedReadyAt *time.Time `json:"estimated_ready_at,omitempty"`
+}
+
+// HoldExpiryRun reports what a hold expiry run closed.
+type HoldExpiryRun struct {
+	Expired        int `json:"expired"`         // holds past their expiry or pickup date
+	CopiesReleased int `json:"copies_released"` // copies taken off the hold shelf
+	CopiesAssigned int `json:"copies_assigned"` // released copies assigned to the next hold
+}
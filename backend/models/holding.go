@@ -0,0 +1,66 @@
+package models
+
+// Holding is a single copy of a book held by a library.
+type Holding struct {
+	ID            string `json:"id" db:"id"`
+	LibraryID     string `json:"library_id" db:"library_id"`
+	LibraryName   string `json:"library_name,omitempty" db:"library_name"` // read-only
+	BookID        string `json:"book_id" db:"book_id"`
+	Barcode       string `json:"barcode,omitempty" db:"barcode"`               // unique across all libraries
+	ShelfLocation string `json:"shelf_location,omitempty" db:"shelf_location"` // e.g., "Fiction A-C, shelf 3"
+	Status        string `json:"status" db:"status"`
+}
+
+// Copy states.
+const (
+	// HoldingStatusAvailable marks a copy that can be borrowed right now.
+	HoldingStatusAvailable = "available"
+	HoldingStatusOnLoan    = "on_loan"
+	HoldingStatusOnHold    = "on_hold" // on the hold shelf for the reader it is assigned to
+	HoldingStatusLost      = "lost"
+	HoldingStatusInRepair  = "in_repair"
+)
+
+// HoldingFilter narrows a listing of holdings. Zero values mean "no filter".
+type HoldingFilter struct {
+	LibraryID string
+	BookID    string
+	Barcode   string
+	Statuses  []string // holdings in any of the statuses
+}
+
+// BookLocation describes where available copies of a book can be found.
+type BookLocation struct {
+	BookID          string  `json:"book_id" db:"book_id"`
+	LibraryID       string  `json:"library_id" db:"library_id"`
+	LibraryName     string  `json:"library_name" db:"library_name"`
+	AvailableCopies int     `json:"available_copies" db:"available_copies"`
+	DistanceKm      float64 `json:"distance_km" db:"distance_km"`
+}
+
+// LibraryAvailability lists the copies of a book one library holds.
+type LibraryAvailability struct {
+	LibraryID       string    `json:"library_id"`
+	LibraryName     string    `json:"library_name"`
+	TotalCopies     int       `json:"total_copies"`
+	AvailableCopies int       `json:"available_copies"`
+	Copies          []Holding `json:"copies"`
+}
+
+// BookAvailability is where copies of a book are held and which can be
+// borrowed, across all libraries.
+type BookAvailability struct {
+	BookID          string                `json:"book_id"`
+	TotalCopies     int                   `json:"total_copies"`
+	AvailableCopies int                   `json:"available_copies"`
+	Libraries       []LibraryAvailability `json:"libraries"`
+}
+
+// LibraryBook is a book in a library's collection with the library's copies
+// of it.
+type LibraryBook struct {
+	Book
+	TotalCopies     int       `json:"total_copies"`
+	AvailableCopies int       `json:"available_copies"`
+	Copies          []Holding `json:"copies"`
+}
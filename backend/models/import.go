@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+// ImportRecord is one catalogue entry read from an import source. Contributors
+// and genres are given by name and linked to existing records, or created, on
+// import.
+type ImportRecord struct {
+	Row          int // 1-based position in the source, used for reports and resuming
+	Book         Book
+	Contributors []ImportContributor
+	GenreNames   []string
+	Copies       int // copies held by the import's library, if it has one
+	// Partial marks an update that only carries some of the book's details:
+	// empty fields, and contributors or genres when none are given, keep
+	// their current values.
+	Partial bool
+	// Delete marks a record asking for the book with Book.ISBN to be removed.
+	Delete bool
+}
+
+// ImportContributor is a person credited on an imported book, with one of the
+// AuthorRole constants.
+type ImportContributor struct {
+	Name string
+	Role string
+}
+
+// Import row outcomes.
+const (
+	ImportStatusCreated  = "created"
+	ImportStatusUpdated  = "updated"
+	ImportStatusDeleted  = "deleted"
+	ImportStatusRejected = "rejected"
+)
+
+// ImportRowResult reports what happened to one source row.
+type ImportRowResult struct {
+	Row    int    `json:"row"`
+	ISBN   string `json:"isbn,omitempty"`
+	BookID string `json:"book_id,omitempty"`
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Import job states.
+const (
+	ImportJobRunning   = "running"
+	ImportJobCompleted = "completed"
+	ImportJobFailed    = "failed"
+)
+
+// ImportJob tracks a catalogue import so an interrupted import can resume
+// after the last committed row.
+type ImportJob struct {
+	ID               string    `json:"id" db:"id"`
+	Source           string    `json:"source" db:"source"`
+	Format           string    `json:"format" db:"format"`
+	LibraryID        string    `json:"library_id,omitempty" db:"library_id"`
+	Status           string    `json:"status" db:"status"`
+	LastCommittedRow int       `json:"last_committed_row" db:"last_committed_row"`
+	Created          int       `json:"created" db:"created"`
+	Updated          int       `json:"updated" db:"updated"`
+	Deleted          int       `json:"deleted" db:"deleted"`
+	Rejected         int       `json:"rejected" db:"rejected"`
+	Error            string    `json:"error,omitempty" db:"error"`
+	StartedAt        time.Time `json:"started_at" db:"started_at"`
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ImportReport is the outcome of an import run: the job's running totals and
+// the result of every row processed in this run, or of the rejected rows only
+// when the run asked for that.
+type ImportReport struct {
+	Job  ImportJob         `json:"job"`
+	Rows []ImportRowResult `json:"rows"`
+}
@@ -6,4 +6,15 @@ type Library struct {
 	Address   string  `json:"address" db:"address"`
 	Latitude  float64 `json:"latitude" db:"latitude"`
 	Longitude float64 `json:"longitude" db:"longitude"`
+
+	// Circulation policy.
+	LoanPeriodDays int  `json:"loan_period_days" db:"loan_period_days"` // also how far a renewal extends a loan
+	MaxRenewals    *int `json:"max_renewals" db:"max_renewals"`         // nil until defaulted, as 0 turns renewals off
+	HoldPickupDays int  `json:"hold_pickup_days" db:"hold_pickup_days"` // how long a copy waits on the hold shelf
+}
+
+// NearbyLibrary is a library with its great-circle distance from a point.
+type NearbyLibrary struct {
+	Library
+	DistanceKm float64 `json:"distance_km" db:"distance_km"`
 }
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Loan is a copy of a book checked out to a user.
+type Loan struct {
+	ID           string     `json:"id" db:"id"`
+	HoldingID    string     `json:"holding_id" db:"holding_id"`
+	BookID       string     `json:"book_id" db:"book_id"`
+	LibraryID    string     `json:"library_id" db:"library_id"`
+	UserID       string     `json:"user_id" db:"user_id"`
+	CheckedOutAt time.Time  `json:"checked_out_at" db:"checked_out_at"`
+	DueAt        time.Time  `json:"due_at" db:"due_at"`
+	Renewals     int        `json:"renewals" db:"renewals"`
+	ReturnedAt   *time.Time `json:"returned_at,omitempty" db:"returned_at"`
+	Overdue      bool       `json:"overdue" db:"overdue"` // read-only: still out after its due date
+}
+
+// Loan listing states.
+const (
+	LoanStatusActive   = "active"
+	LoanStatusOverdue  = "overdue"
+	LoanStatusReturned = "returned"
+)
+
+// LoanFilter narrows a listing of loans. Zero values mean "no filter".
+type LoanFilter struct {
+	UserID    string
+	LibraryID string
+	BookID    string
+	HoldingID string
+	Status    string // "active", "overdue" or "returned"
+}
+
+// CheckOutRequest asks to lend a copy, identified by its holding ID or its
+// barcode, to a user.
+type CheckOutRequest struct {
+	UserID    string `json:"user_id"`
+	HoldingID string `json:"holding_id"`
+	Barcode   string `json:"barcode"`
+}
+
+// CheckInResult is a returned loan and, if the copy was assigned to the next
+// hold in line, that hold, so the copy can be sent to its pickup library.
+type CheckInResult struct {
+	Loan Loan  `json:"loan"`
+	Hold *Hold `json:"hold,omitempty"`
+}
+
+// CheckInRequest identifies a returned copy by its barcode.
+type CheckInRequest struct {
+	Barcode string `json:"barcode"`
+}
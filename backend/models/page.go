@@ -0,0 +1,34 @@
+package models
+
+import "errors"
+
+// Page size limits for list endpoints.
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded or
+// belongs to a different listing.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// PageRequest asks for one page of a listing. Cursor is empty for the first
+// page and otherwise an opaque value from a previous Page.
+type PageRequest struct {
+	Cursor string
+	Limit  int
+}
+
+// Page is one page of a listing with cursors to the neighbouring pages.
+type Page[T any] struct {
+	Items      []T       `json:"items"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+	PrevCursor string    `json:"prev_cursor,omitempty"`
+	Links      PageLinks `json:"links"`
+}
+
+// PageLinks are ready-to-follow URLs for the neighbouring pages.
+type PageLinks struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
@@ -9,3 +9,33 @@ type Recommendation struct {
 	Score       float64   `json:"score" db:"score"`
 	GeneratedAt time.Time `json:"generated_at" db:"generated_at"`
 }
+
+// NearbyRecommendation is a recommendation annotated with the nearby libraries,
+// nearest first, that currently have the book available.
+type NearbyRecommendation struct {
+	Recommendation
+	Libraries []BookLocation `json:"libraries"`
+}
+
+// GroupRecommendationRequest asks for a single list of books for a group of users.
+type GroupRecommendationRequest struct {
+	UserIDs  []string `json:"user_ids"`
+	Strategy string   `json:"strategy"` // "average", "least_misery", "most_pleasure" or "fairness"
+	Limit    int      `json:"limit"`
+}
+
+// GroupRecommendation is a book recommended to a group, with each member's
+// expected preference for it.
+type GroupRecommendation struct {
+	BookID  string             `json:"book_id"`
+	Score   float64            `json:"score"`
+	Members []MemberPreference `json:"members"`
+}
+
+// MemberPreference explains how a group member is expected to like a book.
+// Score is the member's predicted preference normalised to [0, 1].
+type MemberPreference struct {
+	UserID      string  `json:"user_id"`
+	Score       float64 `json:"score"`
+	Explanation string  `json:"explanation"`
+}
@@ -8,4 +8,33 @@ type UserInteraction struct {
 	BookID          string    `json:"book_id" db:"book_id"`
 	InteractionType string    `json:"interaction_type" db:"interaction_type"` // e.g., "view", "click", "rating"
 	Timestamp       time.Time `json:"timestamp" db:"timestamp"`
+	Rating          int       `json:"rating,omitempty" db:"rating"` // 1 to 5 stars, set only on "rating" interactions
+
+	// Optional context captured by the client at the time of the interaction.
+	Device         string `json:"device,omitempty" db:"device"`                     // e.g., "mobile", "tablet", "desktop"
+	Locale         string `json:"locale,omitempty" db:"locale"`                     // BCP 47 tag, e.g., "en-GB"
+	Location       string `json:"location,omitempty" db:"location"`                 // coarse location, e.g., a city or region
+	ClientTimeZone string `json:"client_time_zone,omitempty" db:"client_time_zone"` // IANA zone, e.g., "Europe/London"
+}
+
+// Known interaction types.
+const (
+	InteractionTypeView   = "view"
+	InteractionTypeClick  = "click"
+	InteractionTypeLike   = "like"
+	InteractionTypeRating = "rating"
+	InteractionTypeRead   = "read"
+	InteractionTypeBorrow = "borrow"
+)
+
+// Bounds of the star rating given with a "rating" interaction.
+const (
+	MinRating = 1
+	MaxRating = 5
+)
+
+// BookPopularity counts how many interactions a book has received.
+type BookPopularity struct {
+	BookID           string `json:"book_id" db:"book_id"`
+	InteractionCount int    `json:"interaction_count" db:"interaction_count"`
 }
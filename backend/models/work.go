@@ -0,0 +1,18 @@
+package models
+
+// Work groups the editions and translations of one book, such as its
+// hardback, paperback and French translation. Interactions with any edition
+// count towards the work when recommending.
+type Work struct {
+	ID     string `json:"id" db:"id"`
+	Title  string `json:"title" db:"title"`
+	Author string `json:"author" db:"author"`
+
+	// Editions are the work's books, newest first.
+	Editions []Book `json:"editions" db:"-"`
+}
+
+// WorkEditionsRequest is the body of a request to add editions to a work.
+type WorkEditionsRequest struct {
+	BookIDs []string `json:"book_ids"`
+}
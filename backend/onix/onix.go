@@ -0,0 +1,272 @@
+// Package onix reads product records from ONIX for Books 3.0 messages.
+//
+// Products are decoded one at a time, so feeds of any size can be streamed.
+// Only reference tag names are understood, not the short tags, and only the
+// composites needed to describe a book in the catalogue are decoded.
+package onix
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Notification types (code list 1).
+const (
+	NotificationEarly      = "01"
+	NotificationAdvance    = "02"
+	NotificationConfirmed  = "03"
+	NotificationUpdate     = "04" // block update: only the blocks present replace earlier data
+	NotificationDelete     = "05"
+	NotificationTestUpdate = "88"
+	NotificationTestRecord = "89"
+)
+
+// Product identifier types (code list 5).
+const (
+	ProductIDISBN10 = "02"
+	ProductIDGTIN13 = "03"
+	ProductIDISBN13 = "15"
+)
+
+// Product form code prefixes (code list 150): audio and digital products;
+// hardback and paperback are BB and BC.
+const (
+	ProductFormAudio     = "A"
+	ProductFormHardback  = "BB"
+	ProductFormPaperback = "BC"
+	ProductFormDigital   = "E"
+)
+
+// Extent types (code list 23) and units (code list 24).
+const (
+	ExtentMainContentPages = "00"
+	ExtentContentPages     = "11"
+	ExtentUnitPages        = "03"
+)
+
+// Language roles (code list 22) and publishing roles (code list 45).
+const (
+	LanguageRoleText        = "01"
+	PublishingRolePublisher = "01"
+)
+
+// Title types (code list 15) and title element levels (code list 149).
+const (
+	TitleTypeDistinctive = "01"
+	TitleLevelProduct    = "01"
+	TitleLevelCollection = "02"
+)
+
+// Subject scheme identifiers (code list 26).
+const (
+	SubjectSchemeBISAC = "10"
+)
+
+// Text types (code list 153).
+const (
+	TextTypeShortDescription = "02"
+	TextTypeDescription      = "03"
+)
+
+// Resource content types (code list 158) and modes (code list 159).
+const (
+	ResourceFrontCover = "01"
+	ResourceModeImage  = "03"
+)
+
+// Publishing date roles (code list 163).
+const (
+	PublishingDatePublication = "01"
+)
+
+// Product is one <Product> record.
+type Product struct {
+	RecordReference    string              `xml:"RecordReference"`
+	NotificationType   string              `xml:"NotificationType"`
+	ProductIdentifiers []ProductIdentifier `xml:"ProductIdentifier"`
+	DescriptiveDetail  *DescriptiveDetail  `xml:"DescriptiveDetail"`
+	CollateralDetail   *CollateralDetail   `xml:"CollateralDetail"`
+	PublishingDetail   *PublishingDetail   `xml:"PublishingDetail"`
+}
+
+// ProductIdentifier is a <ProductIdentifier> composite.
+type ProductIdentifier struct {
+	ProductIDType string `xml:"ProductIDType"`
+	IDValue       string `xml:"IDValue"`
+}
+
+// DescriptiveDetail is block 1 of a product.
+type DescriptiveDetail struct {
+	ProductForm      string        `xml:"ProductForm"`
+	Collections      []Collection  `xml:"Collection"`
+	TitleDetails     []TitleDetail `xml:"TitleDetail"`
+	Contributors     []Contributor `xml:"Contributor"`
+	EditionStatement string        `xml:"EditionStatement"`
+	Languages        []Language    `xml:"Language"`
+	Extents          []Extent      `xml:"Extent"`
+	Subjects         []Subject     `xml:"Subject"`
+}
+
+// Collection is a <Collection> composite, such as a publisher's series.
+type Collection struct {
+	CollectionType string        `xml:"CollectionType"`
+	TitleDetails   []TitleDetail `xml:"TitleDetail"`
+}
+
+// TitleDetail is a <TitleDetail> composite.
+type TitleDetail struct {
+	TitleType     string         `xml:"TitleType"`
+	TitleElements []TitleElement `xml:"TitleElement"`
+}
+
+// TitleElement is a <TitleElement> composite.
+type TitleElement struct {
+	TitleElementLevel  string `xml:"TitleElementLevel"`
+	PartNumber         string `xml:"PartNumber"`
+	TitleText          string `xml:"TitleText"`
+	TitlePrefix        string `xml:"TitlePrefix"`
+	TitleWithoutPrefix string `xml:"TitleWithoutPrefix"`
+	Subtitle           string `xml:"Subtitle"`
+}
+
+// Title returns the full title, joining a separate prefix back on.
+func (e TitleElement) Title() string {
+	if e.TitleText != "" {
+		return e.TitleText
+	}
+	if e.TitlePrefix != "" {
+		return e.TitlePrefix + " " + e.TitleWithoutPrefix
+	}
+	return e.TitleWithoutPrefix
+}
+
+// Contributor is a <Contributor> composite.
+type Contributor struct {
+	ContributorRoles   []string `xml:"ContributorRole"`
+	PersonName         string   `xml:"PersonName"`
+	PersonNameInverted string   `xml:"PersonNameInverted"`
+	NamesBeforeKey     string   `xml:"NamesBeforeKey"`
+	KeyNames           string   `xml:"KeyNames"`
+	CorporateName      string   `xml:"CorporateName"`
+}
+
+// Name returns the contributor's name in natural order where it is given.
+func (c Contributor) Name() string {
+	switch {
+	case c.PersonName != "":
+		return c.PersonName
+	case c.KeyNames != "" && c.NamesBeforeKey != "":
+		return c.NamesBeforeKey + " " + c.KeyNames
+	case c.KeyNames != "":
+		return c.KeyNames
+	case c.PersonNameInverted != "":
+		return c.PersonNameInverted
+	default:
+		return c.CorporateName
+	}
+}
+
+// Language is a <Language> composite. LanguageCode is an ISO 639-2/B code.
+type Language struct {
+	LanguageRole string `xml:"LanguageRole"`
+	LanguageCode string `xml:"LanguageCode"`
+}
+
+// Extent is an <Extent> composite, such as a page count.
+type Extent struct {
+	ExtentType  string `xml:"ExtentType"`
+	ExtentValue string `xml:"ExtentValue"`
+	ExtentUnit  string `xml:"ExtentUnit"`
+}
+
+// Subject is a <Subject> composite.
+type Subject struct {
+	MainSubject             *struct{} `xml:"MainSubject"`
+	SubjectSchemeIdentifier string    `xml:"SubjectSchemeIdentifier"`
+	SubjectCode             string    `xml:"SubjectCode"`
+	SubjectHeadingText      string    `xml:"SubjectHeadingText"`
+}
+
+// CollateralDetail is block 2 of a product.
+type CollateralDetail struct {
+	TextContents        []TextContent        `xml:"TextContent"`
+	SupportingResources []SupportingResource `xml:"SupportingResource"`
+}
+
+// TextContent is a <TextContent> composite. Text holds the element's raw
+// content, which may be XHTML or escaped HTML depending on its textformat.
+type TextContent struct {
+	TextType string `xml:"TextType"`
+	Text     struct {
+		Format string `xml:"textformat,attr"`
+		Raw    string `xml:",innerxml"`
+	} `xml:"Text"`
+}
+
+// SupportingResource is a <SupportingResource> composite.
+type SupportingResource struct {
+	ResourceContentType string `xml:"ResourceContentType"`
+	ResourceMode        string `xml:"ResourceMode"`
+	ResourceVersions    []struct {
+		ResourceLink string `xml:"ResourceLink"`
+	} `xml:"ResourceVersion"`
+}
+
+// PublishingDetail is block 4 of a product.
+type PublishingDetail struct {
+	Publishers      []Publisher      `xml:"Publisher"`
+	PublishingDates []PublishingDate `xml:"PublishingDate"`
+}
+
+// Publisher is a <Publisher> composite.
+type Publisher struct {
+	PublishingRole string `xml:"PublishingRole"`
+	PublisherName  string `xml:"PublisherName"`
+}
+
+// PublishingDate is a <PublishingDate> composite.
+type PublishingDate struct {
+	PublishingDateRole string `xml:"PublishingDateRole"`
+	Date               string `xml:"Date"`
+}
+
+// Reader reads the products of an ONIX message.
+type Reader struct {
+	d *xml.Decoder
+}
+
+// NewReader returns a Reader reading products from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{d: xml.NewDecoder(r)}
+}
+
+// Read returns the next product, or io.EOF when there are no more.
+func (or *Reader) Read() (*Product, error) {
+	for {
+		tok, err := or.d.Token()
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("onix: failed to read message: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local == "product" {
+			return nil, errors.New("onix: short tag messages are not supported; send reference tags")
+		}
+		if start.Name.Local != "Product" {
+			continue
+		}
+
+		var p Product
+		if err := or.d.DecodeElement(&p, &start); err != nil {
+			return nil, fmt.Errorf("onix: failed to read product: %w", err)
+		}
+		return &p, nil
+	}
+}
@@ -0,0 +1,177 @@
+// Package openlibrary reads Open Library data dumps.
+//
+// A dump has one record per line, as tab-separated type, key, revision, last
+// modified time and JSON document. Dumps are usually gzipped; Open detects
+// this from the file's content, so compressed and uncompressed files can be
+// used alike.
+package openlibrary
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Record types.
+const (
+	TypeEdition = "/type/edition"
+	TypeWork    = "/type/work"
+	TypeAuthor  = "/type/author"
+)
+
+// Record is one line of a dump. JSON is decoded on demand, since most
+// records of a dump are usually skipped.
+type Record struct {
+	Line int // 1-based line number in the dump
+	Type string
+	Key  string
+	JSON []byte
+}
+
+// Ref is a reference to another record, such as {"key": "/works/OL45883W"}.
+type Ref struct {
+	Key string `json:"key"`
+}
+
+// Text is a text value, which dumps give either as a plain string or as
+// {"type": "/type/text", "value": "..."}.
+type Text string
+
+// UnmarshalJSON accepts both forms of a text value.
+func (t *Text) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*t = Text(s)
+		return nil
+	}
+	var typed struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return err
+	}
+	*t = Text(typed.Value)
+	return nil
+}
+
+// Edition is a /type/edition document.
+type Edition struct {
+	Key            string   `json:"key"`
+	Title          string   `json:"title"`
+	Subtitle       string   `json:"subtitle"`
+	ISBN10         []string `json:"isbn_10"`
+	ISBN13         []string `json:"isbn_13"`
+	Works          []Ref    `json:"works"`
+	Authors        []Ref    `json:"authors"`
+	Languages      []Ref    `json:"languages"`
+	PublishDate    string   `json:"publish_date"`
+	Series         []string `json:"series"`
+	Subjects       []string `json:"subjects"`
+	Description    Text     `json:"description"`
+	Covers         []int64  `json:"covers"`
+	NumberOfPages  int      `json:"number_of_pages"`
+	Publishers     []string `json:"publishers"`
+	PhysicalFormat string   `json:"physical_format"`
+	EditionName    string   `json:"edition_name"`
+}
+
+// Work is a /type/work document.
+type Work struct {
+	Key         string       `json:"key"`
+	Title       string       `json:"title"`
+	Subjects    []string     `json:"subjects"`
+	Description Text         `json:"description"`
+	Authors     []WorkAuthor `json:"authors"`
+	Covers      []int64      `json:"covers"`
+}
+
+// WorkAuthor is an entry of a work's authors.
+type WorkAuthor struct {
+	Author Ref `json:"author"`
+}
+
+// Author is a /type/author document.
+type Author struct {
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+// LanguageCode returns the code of a language reference such as
+// "/languages/eng", which is a MARC (ISO 639-2/B) code.
+func LanguageCode(ref Ref) string {
+	return strings.TrimPrefix(ref.Key, "/languages/")
+}
+
+// CoverURL returns the URL of the large image of an Open Library cover ID.
+func CoverURL(id int64) string {
+	return fmt.Sprintf("https://covers.openlibrary.org/b/id/%d-L.jpg", id)
+}
+
+// gzipMagic starts every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// Open opens a local dump file, decompressing it if it is gzipped.
+func Open(path string) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("openlibrary: %w", err)
+	}
+	br := bufio.NewReaderSize(f, 1<<20)
+	magic, err := br.Peek(len(gzipMagic))
+	if err != nil && !errors.Is(err, io.EOF) {
+		f.Close()
+		return nil, fmt.Errorf("openlibrary: failed to read %s: %w", path, err)
+	}
+	if !bytes.Equal(magic, gzipMagic) {
+		return readCloser{Reader: br, Closer: f}, nil
+	}
+	zr, err := gzip.NewReader(br)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("openlibrary: failed to decompress %s: %w", path, err)
+	}
+	return readCloser{Reader: zr, Closer: f}, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// Reader reads the records of a dump.
+type Reader struct {
+	r    *bufio.Reader
+	line int
+}
+
+// NewReader returns a Reader reading records from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReaderSize(r, 1<<20)}
+}
+
+// Read returns the next record, or io.EOF when there are no more. Lines
+// that do not have the five dump columns are skipped.
+func (dr *Reader) Read() (*Record, error) {
+	for {
+		line, err := dr.r.ReadBytes('\n')
+		if len(line) == 0 && errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("openlibrary: failed to read dump: %w", err)
+		}
+		dr.line++
+
+		fields := bytes.SplitN(bytes.TrimRight(line, "\r\n"), []byte{'\t'}, 5)
+		if len(fields) < 5 {
+			continue
+		}
+		return &Record{Line: dr.line, Type: string(fields[0]), Key: string(fields[1]), JSON: fields[4]}, nil
+	}
+}
@@ -11,10 +11,13 @@ import (
 // AuthorRepository defines the interface for author data operations.
 type AuthorRepository interface {
 	GetAuthorByID(ctx context.Context, id string) (*models.Author, error)
-	GetAllAuthors(ctx context.Context) ([]models.Author, error)
+	GetAllAuthors(ctx context.Context, page models.PageRequest) (*models.Page[models.Author], error)
 	CreateAuthor(ctx context.Context, author *models.Author) error
 	UpdateAuthor(ctx context.Context, author *models.Author) error
 	DeleteAuthor(ctx context.Context, id string) error
+	GetAuthorBooks(ctx context.Context, authorID string) ([]models.AuthorBook, error)
+	GetAuthorStats(ctx context.Context, authorID string, readerTypes []string, topGenres int) (*models.AuthorStats, error)
+	GetRelatedAuthors(ctx context.Context, authorID string, readerTypes []string, limit int) ([]models.RelatedAuthor, error)
 }
 
 // authorRepository implements AuthorRepository using sqlx.
@@ -37,10 +40,16 @@ func (r *authorRepository) GetAuthorByID(ctx context.Context, id string) (*model
 	return &author, nil
 }
 
-// GetAllAuthors retrieves all authors.
-func (r *authorRepository) GetAllAuthors(ctx context.Context) ([]models.Author, error) {
-	var authors []models.Author
-	err := r.db.SelectContext(ctx, &authors, "SELECT id, name, biography FROM authors")
+// authorKeyset orders authors by name for pagination.
+var authorKeyset = keyset[models.Author]{
+	name:    "authors",
+	columns: []string{"name", "id"},
+	values:  func(a models.Author) []any { return []any{a.Name, a.ID} },
+}
+
+// GetAllAuthors retrieves a page of all authors.
+func (r *authorRepository) GetAllAuthors(ctx context.Context, page models.PageRequest) (*models.Page[models.Author], error) {
+	authors, err := selectPage(ctx, r.db, "SELECT id, name, biography FROM authors", nil, authorKeyset, page)
 	if err != nil {
 		return nil, fmt.Errorf("error getting all authors: %w", err)
 	}
@@ -75,3 +84,91 @@ func (r *authorRepository) DeleteAuthor(ctx context.Context, id string) error {
 	}
 	return nil
 }
+
+// GetAuthorBooks retrieves the books an author contributed to in any role,
+// oldest first.
+func (r *authorRepository) GetAuthorBooks(ctx context.Context, authorID string) ([]models.AuthorBook, error) {
+	books := []models.AuthorBook{}
+	err := r.db.SelectContext(ctx, &books, `SELECT `+bookColumns+`, array_agg(ba.role ORDER BY ba.role) AS roles
+		FROM books JOIN book_authors ba ON ba.book_id = books.id
+		WHERE ba.author_id = $1
+		GROUP BY books.id
+		ORDER BY books.publication_year, books.title, books.id`, authorID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting author books: %w", err)
+	}
+	return books, nil
+}
+
+// GetAuthorStats counts the books an author wrote, the distinct users with
+// an interaction of one of readerTypes on any of them, and their ratings,
+// with up to topGenres of the genres they wrote most in.
+func (r *authorRepository) GetAuthorStats(ctx context.Context, authorID string, readerTypes []string, topGenres int) (*models.AuthorStats, error) {
+	var stats models.AuthorStats
+	query, args, err := sqlx.In(`WITH author_books AS (
+			SELECT book_id FROM book_authors WHERE author_id = ? AND role = 'author'
+		)
+		SELECT (SELECT COUNT(*) FROM author_books) AS book_count,
+			COUNT(DISTINCT ui.user_id) FILTER (WHERE ui.interaction_type IN (?)) AS readers,
+			COUNT(*) FILTER (WHERE ui.interaction_type = 'rating' AND ui.rating > 0) AS rating_count,
+			COALESCE(AVG(ui.rating) FILTER (WHERE ui.interaction_type = 'rating' AND ui.rating > 0), 0)::float8 AS average_rating
+		FROM user_interactions ui
+		WHERE ui.book_id IN (SELECT book_id FROM author_books)`, authorID, readerTypes)
+	if err != nil {
+		return nil, fmt.Errorf("error building author stats query: %w", err)
+	}
+	if err := r.db.GetContext(ctx, &stats, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("error getting author stats: %w", err)
+	}
+
+	stats.TopGenres = []models.GenreCount{}
+	err = r.db.SelectContext(ctx, &stats.TopGenres, `SELECT g.id, g.name, COUNT(*) AS book_count
+		FROM book_authors ba JOIN book_genres bg ON bg.book_id = ba.book_id JOIN genres g ON g.id = bg.genre_id
+		WHERE ba.author_id = $1 AND ba.role = 'author'
+		GROUP BY g.id, g.name
+		ORDER BY book_count DESC, g.name
+		LIMIT $2`, authorID, topGenres)
+	if err != nil {
+		return nil, fmt.Errorf("error getting author top genres: %w", err)
+	}
+	return &stats, nil
+}
+
+// GetRelatedAuthors retrieves up to limit authors whose books were read by
+// readers of an author's books, most similar first. Readers are users with an
+// interaction of one of readerTypes.
+func (r *authorRepository) GetRelatedAuthors(ctx context.Context, authorID string, readerTypes []string, limit int) ([]models.RelatedAuthor, error) {
+	query, args, err := sqlx.In(`WITH author_readers AS (
+			SELECT DISTINCT ba.author_id, ui.user_id
+			FROM user_interactions ui JOIN book_authors ba ON ba.book_id = ui.book_id AND ba.role = 'author'
+			WHERE ui.interaction_type IN (?)
+				AND ui.user_id IN (
+					SELECT ui2.user_id FROM user_interactions ui2 JOIN book_authors ba2 ON ba2.book_id = ui2.book_id
+					WHERE ba2.author_id = ? AND ba2.role = 'author' AND ui2.interaction_type IN (?)
+				)
+		),
+		shared AS (
+			SELECT author_id, COUNT(*) AS shared_readers
+			FROM author_readers WHERE author_id <> ?
+			GROUP BY author_id
+		)
+		SELECT a.id, a.name, a.biography, s.shared_readers,
+			s.shared_readers / sqrt((SELECT COUNT(*) FROM author_readers WHERE author_id = ?)::float8 * readers.total) AS similarity
+		FROM shared s
+		JOIN authors a ON a.id = s.author_id
+		CROSS JOIN LATERAL (
+			SELECT COUNT(DISTINCT ui.user_id) AS total
+			FROM user_interactions ui JOIN book_authors ba ON ba.book_id = ui.book_id
+			WHERE ba.author_id = s.author_id AND ba.role = 'author' AND ui.interaction_type IN (?)
+		) readers
+		ORDER BY similarity DESC, s.shared_readers DESC, a.name, a.id
+		LIMIT ?`, readerTypes, authorID, readerTypes, authorID, authorID, readerTypes, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error building related authors query: %w", err)
+	}
+	related := []models.RelatedAuthor{}
+	if err := r.db.SelectContext(ctx, &related, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("error getting related authors: %w", err)
+	}
+	return related, nil
+}
@@ -2,19 +2,41 @@ package repositories
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"book-recommendation-system/backend/models"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+// bookColumns is the column list selected for models.Book.
+const bookColumns = "id, title, author, isbn, description, cover_image_url, genre, publication_year, series, series_position, language, page_count, publisher, format, edition, subjects, work_id"
+
 // BookRepository defines the interface for book data operations.
 type BookRepository interface {
 	GetBookByID(ctx context.Context, id string) (*models.Book, error)
-	GetAllBooks(ctx context.Context) ([]models.Book, error)
+	GetBookByISBN(ctx context.Context, isbn string) (*models.Book, error)
+	GetAllBooks(ctx context.Context, page models.PageRequest) (*models.Page[models.Book], error)
+	GetBooksByIDs(ctx context.Context, ids []string) ([]models.Book, error)
+	GetBooksBySeries(ctx context.Context, series []string) ([]models.Book, error)
+	GetBooksByWorkIDs(ctx context.Context, workIDs []string) ([]models.Book, error)
+	SearchBooks(ctx context.Context, q string, limit int) ([]models.BookSearchResult, error)
+	QueryBooks(ctx context.Context, filter models.BookFilter, page models.PageRequest) (*models.Page[models.Book], error)
+	GetBookFacets(ctx context.Context, filter models.BookFilter) (*models.BookFacets, error)
+	GetBookAuthors(ctx context.Context, bookIDs []string) ([]models.BookAuthor, error)
+	GetBookGenres(ctx context.Context, bookIDs []string) ([]models.BookGenre, error)
+	SetBookRelations(ctx context.Context, bookID string, authors []models.BookAuthor, genres []models.BookGenre) error
+	UpsertBooksByISBN(ctx context.Context, books []models.Book) ([]UpsertResult, error)
+	MergeBooks(ctx context.Context, survivorID string, duplicateIDs []string) (*models.BookMergeResult, error)
 	CreateBook(ctx context.Context, book *models.Book) error
 	UpdateBook(ctx context.Context, book *models.Book) error
-	DeleteBook(ctx context.Context, id string) error
+	SetBookCover(ctx context.Context, id, coverImageURL string) error
+	SetBookISBN(ctx context.Context, id, isbn string) error
+	DeleteBook(ctx context.Context, id string) (bool, error)
 }
 
 // bookRepository implements BookRepository using sqlx.
@@ -30,48 +52,644 @@ func NewBookRepository(db *sqlx.DB) BookRepository {
 // GetBookByID retrieves a book by its ID.
 func (r *bookRepository) GetBookByID(ctx context.Context, id string) (*models.Book, error) {
 	var book models.Book
-	err := r.db.GetContext(ctx, &book, "SELECT id, title, author, isbn, description, cover_image_url, genre, publication_year FROM books WHERE id=$1", id)
+	err := r.db.GetContext(ctx, &book, "SELECT "+bookColumns+" FROM books WHERE id=$1", id)
 	if err != nil {
 		return nil, fmt.Errorf("error getting book by ID: %w", err)
 	}
 	return &book, nil
 }
 
-// GetAllBooks retrieves all books.
-func (r *bookRepository) GetAllBooks(ctx context.Context) ([]models.Book, error) {
-	var books []models.Book
-	err := r.db.SelectContext(ctx, &books, "SELECT id, title, author, isbn, description, cover_image_url, genre, publication_year FROM books")
+// GetBookByISBN retrieves a book by its normalised ISBN-13, returning nil if there is none.
+func (r *bookRepository) GetBookByISBN(ctx context.Context, isbn string) (*models.Book, error) {
+	var book models.Book
+	err := r.db.GetContext(ctx, &book, "SELECT "+bookColumns+" FROM books WHERE isbn=$1", isbn)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting book by ISBN: %w", err)
+	}
+	return &book, nil
+}
+
+// GetAllBooks retrieves a page of all books ordered by title.
+func (r *bookRepository) GetAllBooks(ctx context.Context, page models.PageRequest) (*models.Page[models.Book], error) {
+	books, err := r.QueryBooks(ctx, models.BookFilter{}, page)
 	if err != nil {
 		return nil, fmt.Errorf("error getting all books: %w", err)
 	}
 	return books, nil
 }
 
-// CreateBook creates a new book.
+// GetBooksByIDs retrieves the books with the given IDs.
+func (r *bookRepository) GetBooksByIDs(ctx context.Context, ids []string) ([]models.Book, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In("SELECT "+bookColumns+" FROM books WHERE id IN (?)", ids)
+	if err != nil {
+		return nil, fmt.Errorf("error building books by IDs query: %w", err)
+	}
+	var books []models.Book
+	err = r.db.SelectContext(ctx, &books, r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting books by IDs: %w", err)
+	}
+	return books, nil
+}
+
+// GetBooksBySeries retrieves every volume of the given series, ordered by series and position.
+func (r *bookRepository) GetBooksBySeries(ctx context.Context, series []string) ([]models.Book, error) {
+	if len(series) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In("SELECT "+bookColumns+" FROM books WHERE series IN (?) ORDER BY series, series_position", series)
+	if err != nil {
+		return nil, fmt.Errorf("error building books by series query: %w", err)
+	}
+	var books []models.Book
+	err = r.db.SelectContext(ctx, &books, r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting books by series: %w", err)
+	}
+	return books, nil
+}
+
+// GetBooksByWorkIDs retrieves every edition of the given works, ordered by
+// work and then newest first.
+func (r *bookRepository) GetBooksByWorkIDs(ctx context.Context, workIDs []string) ([]models.Book, error) {
+	if len(workIDs) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In("SELECT "+bookColumns+" FROM books WHERE work_id IN (?) ORDER BY work_id, publication_year DESC, id", workIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error building books by work IDs query: %w", err)
+	}
+	var books []models.Book
+	err = r.db.SelectContext(ctx, &books, r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting books by work IDs: %w", err)
+	}
+	return books, nil
+}
+
+// SearchBooks runs a full-text search over title, author, genre and description,
+// returning the best-ranked matches with highlighted title and description snippets.
+func (r *bookRepository) SearchBooks(ctx context.Context, q string, limit int) ([]models.BookSearchResult, error) {
+	tsQuery := buildTSQuery(q)
+	if tsQuery == "" {
+		return []models.BookSearchResult{}, nil
+	}
+	query := `SELECT ` + bookColumns + `, rank,
+			ts_headline('english', title, q, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS title_highlight,
+			ts_headline('english', coalesce(description, ''), q, 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10') AS snippet
+		FROM (
+			SELECT books.*, q, ts_rank(search_vector, q) AS rank
+			FROM books, to_tsquery('english', $1) q
+			WHERE search_vector @@ q
+			ORDER BY rank DESC, id
+			LIMIT $2
+		) matches
+		ORDER BY rank DESC, id`
+	var results []models.BookSearchResult
+	err := r.db.SelectContext(ctx, &results, query, tsQuery, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error searching books: %w", err)
+	}
+	return results, nil
+}
+
+// bookSortOrders maps the sort orders accepted by QueryBooks to the keyset
+// they paginate by. Each ends with id as a tiebreaker to keep the order stable.
+var bookSortOrders = map[string]keyset[models.Book]{
+	"title": {name: "books:title", columns: []string{"title", "id"},
+		values: func(b models.Book) []any { return []any{b.Title, b.ID} }},
+	"-title": {name: "books:-title", columns: []string{"title", "id"}, desc: true,
+		values: func(b models.Book) []any { return []any{b.Title, b.ID} }},
+	"author": {name: "books:author", columns: []string{"author", "title", "id"},
+		values: func(b models.Book) []any { return []any{b.Author, b.Title, b.ID} }},
+	"-author": {name: "books:-author", columns: []string{"author", "title", "id"}, desc: true,
+		values: func(b models.Book) []any { return []any{b.Author, b.Title, b.ID} }},
+	"publication_year": {name: "books:publication_year", columns: []string{"COALESCE(publication_year, 0)", "id"},
+		values: func(b models.Book) []any { return []any{b.PublicationYear, b.ID} }},
+	"-publication_year": {name: "books:-publication_year", columns: []string{"COALESCE(publication_year, 0)", "id"}, desc: true,
+		values: func(b models.Book) []any { return []any{b.PublicationYear, b.ID} }},
+}
+
+// DefaultBookSort is the sort order used when a BookFilter does not set one.
+const DefaultBookSort = "title"
+
+// IsValidBookSort reports whether QueryBooks accepts the sort order.
+func IsValidBookSort(sort string) bool {
+	_, ok := bookSortOrders[sort]
+	return ok
+}
+
+// maxFacetValues caps how many values are counted per facet.
+const maxFacetValues = 50
+
+// bookFilterConditions translates a BookFilter into query conditions.
+func bookFilterConditions(filter models.BookFilter) *queryBuilder {
+	b := &queryBuilder{}
+	if len(filter.Genres) > 0 {
+		// A genre matches books in any of its subgenres as well, by name or
+		// alias.
+		names := lowerAll(filter.Genres)
+		list := placeholders(len(names))
+		genres := fmt.Sprintf(genreSubtreeQuery, "lower(name) IN ("+list+") OR id IN (SELECT genre_id FROM genre_aliases WHERE lower(name) IN ("+list+"))")
+		args := make([]any, 0, 3*len(names))
+		for range 3 {
+			for _, name := range names {
+				args = append(args, name)
+			}
+		}
+		b.where("genre", "lower(genre) IN ("+list+") OR EXISTS (SELECT 1 FROM book_genres bg WHERE bg.book_id = books.id AND bg.genre_id IN ("+genres+"))", args...)
+	}
+	if len(filter.GenreIDs) > 0 {
+		genres := fmt.Sprintf(genreSubtreeQuery, "id IN ("+placeholders(len(filter.GenreIDs))+")")
+		args := make([]any, len(filter.GenreIDs))
+		for i, id := range filter.GenreIDs {
+			args[i] = id
+		}
+		b.where("genre", "EXISTS (SELECT 1 FROM book_genres bg WHERE bg.book_id = books.id AND bg.genre_id IN ("+genres+"))", args...)
+	}
+	b.whereIn("author", "lower(author)", lowerAll(filter.Authors))
+	if filter.YearFrom != 0 {
+		b.where("year", "publication_year >= ?", filter.YearFrom)
+	}
+	if filter.YearTo != 0 {
+		b.where("year", "publication_year <= ?", filter.YearTo)
+	}
+	if filter.Language != "" {
+		b.where("language", "language = ?", filter.Language)
+	}
+	b.whereIn("format", "format", lowerAll(filter.Formats))
+	b.whereIn("publisher", "lower(publisher)", lowerAll(filter.Publishers))
+	if len(filter.Subjects) > 0 {
+		b.where("subject", "subjects && ?", pq.StringArray(lowerAll(filter.Subjects)))
+	}
+	if filter.PagesMin != 0 {
+		b.where("pages", "page_count >= ?", filter.PagesMin)
+	}
+	if filter.PagesMax != 0 {
+		b.where("pages", "page_count BETWEEN 1 AND ?", filter.PagesMax)
+	}
+	if filter.LibraryID != "" {
+		b.where("library", "EXISTS (SELECT 1 FROM holdings h WHERE h.book_id = books.id AND h.library_id = ?)", filter.LibraryID)
+	}
+	if filter.Available != nil {
+		available := "EXISTS (SELECT 1 FROM holdings h WHERE h.book_id = books.id AND h.status = ?"
+		args := []any{models.HoldingStatusAvailable}
+		if filter.LibraryID != "" {
+			available += " AND h.library_id = ?"
+			args = append(args, filter.LibraryID)
+		}
+		available += ")"
+		if !*filter.Available {
+			available = "NOT " + available
+		}
+		b.where("available", available, args...)
+	}
+	return b
+}
+
+func lowerAll(values []string) []string {
+	lowered := make([]string, len(values))
+	for i, v := range values {
+		lowered[i] = strings.ToLower(v)
+	}
+	return lowered
+}
+
+// QueryBooks retrieves a page of the books matching a filter in the requested order.
+func (r *bookRepository) QueryBooks(ctx context.Context, filter models.BookFilter, page models.PageRequest) (*models.Page[models.Book], error) {
+	sort := filter.Sort
+	if sort == "" {
+		sort = DefaultBookSort
+	}
+	ks, ok := bookSortOrders[sort]
+	if !ok {
+		return nil, fmt.Errorf("error querying books: unknown sort order %q", sort)
+	}
+
+	books, err := selectPage(ctx, r.db, "SELECT "+bookColumns+" FROM books", bookFilterConditions(filter), ks, page)
+	if err != nil {
+		return nil, fmt.Errorf("error querying books: %w", err)
+	}
+	return books, nil
+}
+
+// GetBookFacets counts the books matching a filter per genre, author, decade and format.
+// Each facet ignores the filter on its own dimension.
+func (r *bookRepository) GetBookFacets(ctx context.Context, filter models.BookFilter) (*models.BookFacets, error) {
+	conditions := bookFilterConditions(filter)
+	facets := &models.BookFacets{}
+	// Genres and authors are filtered case-insensitively, so they are counted
+	// that way too, under their most common spelling.
+	for _, facet := range []struct {
+		dimension string
+		value     string
+		group     string
+		present   string // excludes books without a value for the facet
+		dest      *[]models.FacetCount
+	}{
+		{"genre", "MODE() WITHIN GROUP (ORDER BY genre)", "lower(genre)", "genre <> ''", &facets.Genres},
+		{"author", "MODE() WITHIN GROUP (ORDER BY author)", "lower(author)", "author <> ''", &facets.Authors},
+		{"year", "((publication_year / 10) * 10)::text", "1", "publication_year > 0", &facets.Decades},
+		{"format", "format", "1", "format <> ''", &facets.Formats},
+	} {
+		where, args := conditions.whereClause(facet.dimension)
+		if where == "" {
+			where = " WHERE "
+		} else {
+			where += " AND "
+		}
+		query := "SELECT " + facet.value + " AS value, COUNT(*) AS count FROM books" + where +
+			facet.present + " GROUP BY " + facet.group + " ORDER BY count DESC, value LIMIT ?"
+		args = append(args, maxFacetValues)
+		*facet.dest = []models.FacetCount{}
+		err := r.db.SelectContext(ctx, facet.dest, r.db.Rebind(query), args...)
+		if err != nil {
+			return nil, fmt.Errorf("error counting %s facet: %w", facet.dimension, err)
+		}
+	}
+	return facets, nil
+}
+
+// CreateBook creates a new book and its author and genre links. A book
+// without a work ID becomes the only edition of a new work with the book's ID;
+// one whose work does not exist yet creates it.
 func (r *bookRepository) CreateBook(ctx context.Context, book *models.Book) error {
-	query := `INSERT INTO books (id, title, author, isbn, description, cover_image_url, genre, publication_year) VALUES (:id, :title, :author, :isbn, :description, :cover_image_url, :genre, :publication_year)`
-	_, err := r.db.NamedExecContext(ctx, query, book)
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error creating book: %w", err)
+	}
+	defer tx.Rollback()
+
+	if book.WorkID == "" {
+		book.WorkID = book.ID
+	}
+	query := `INSERT INTO books (id, title, author, isbn, description, cover_image_url, genre, publication_year, series, series_position, language, page_count, publisher, format, edition, subjects, work_id) VALUES (:id, :title, :author, :isbn, :description, :cover_image_url, :genre, :publication_year, :series, :series_position, :language, :page_count, :publisher, :format, :edition, COALESCE(:subjects, '{}'), :work_id)`
+	_, err = tx.NamedExecContext(ctx, query, book)
 	if err != nil {
 		return fmt.Errorf("error creating book: %w", err)
 	}
+	if err := ensureWork(ctx, tx, book); err != nil {
+		return fmt.Errorf("error creating book: %w", err)
+	}
+	if err := setBookRelations(ctx, tx, book.ID, book.Authors, book.Genres); err != nil {
+		return fmt.Errorf("error creating book: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error creating book: %w", err)
+	}
 	return nil
 }
 
-// UpdateBook updates an existing book.
+// UpdateBook updates an existing book, replacing its author and genre links
+// when they are set. The book's work is not changed; see WorkRepository.
 func (r *bookRepository) UpdateBook(ctx context.Context, book *models.Book) error {
-	query := `UPDATE books SET title=:title, author=:author, isbn=:isbn, description=:description, cover_image_url=:cover_image_url, genre=:genre, publication_year=:publication_year WHERE id=:id`
-	_, err := r.db.NamedExecContext(ctx, query, book)
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("error updating book: %w", err)
 	}
+	defer tx.Rollback()
+
+	query := `UPDATE books SET title=:title, author=:author, isbn=:isbn, description=:description, cover_image_url=:cover_image_url, genre=:genre, publication_year=:publication_year, series=:series, series_position=:series_position, language=:language, page_count=:page_count, publisher=:publisher, format=:format, edition=:edition, subjects=COALESCE(:subjects, '{}') WHERE id=:id`
+	_, err = tx.NamedExecContext(ctx, query, book)
+	if err != nil {
+		return fmt.Errorf("error updating book: %w", err)
+	}
+	if err := setBookRelations(ctx, tx, book.ID, book.Authors, book.Genres); err != nil {
+		return fmt.Errorf("error updating book: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error updating book: %w", err)
+	}
+	return nil
+}
+
+// SetBookCover sets the cover image URL of a book.
+func (r *bookRepository) SetBookCover(ctx context.Context, id, coverImageURL string) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE books SET cover_image_url=$1 WHERE id=$2", coverImageURL, id)
+	if err != nil {
+		return fmt.Errorf("error setting book cover: %w", err)
+	}
+	return nil
+}
+
+// SetBookISBN replaces a book's ISBN, leaving the rest of the book unchanged.
+func (r *bookRepository) SetBookISBN(ctx context.Context, id, isbn string) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE books SET isbn=$1 WHERE id=$2", isbn, id)
+	if err != nil {
+		return fmt.Errorf("error setting book ISBN: %w", err)
+	}
+	return nil
+}
+
+// UpsertResult is the outcome of upserting one book.
+type UpsertResult struct {
+	BookID  string
+	Created bool  // false when an existing book with the same ISBN was updated
+	Err     error // set when this book alone could not be saved
+}
+
+// UpsertBooksByISBN creates or updates books in a single transaction, matching
+// existing books on ISBN and keeping their IDs and works. Each book's author
+// and genre links are replaced when set. A book that fails is rolled back on
+// its own and reported in its result, without affecting the others; the
+// returned error is only set if the batch as a whole failed.
+func (r *bookRepository) UpsertBooksByISBN(ctx context.Context, books []models.Book) ([]UpsertResult, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error upserting books: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO books (id, title, author, isbn, description, cover_image_url, genre, publication_year, series, series_position, language, page_count, publisher, format, edition, subjects, work_id)
+		VALUES (:id, :title, :author, :isbn, :description, :cover_image_url, :genre, :publication_year, :series, :series_position, :language, :page_count, :publisher, :format, :edition, COALESCE(:subjects, '{}'), :work_id)
+		ON CONFLICT (isbn) DO UPDATE SET title=EXCLUDED.title, author=EXCLUDED.author, description=EXCLUDED.description,
+			cover_image_url=EXCLUDED.cover_image_url, genre=EXCLUDED.genre, publication_year=EXCLUDED.publication_year,
+			series=EXCLUDED.series, series_position=EXCLUDED.series_position, language=EXCLUDED.language,
+			page_count=EXCLUDED.page_count, publisher=EXCLUDED.publisher, format=EXCLUDED.format, edition=EXCLUDED.edition,
+			subjects=EXCLUDED.subjects
+		RETURNING id, work_id, (xmax = 0) AS created`
+	results := make([]UpsertResult, len(books))
+	for i := range books {
+		book := &books[i]
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT upsert_book"); err != nil {
+			return nil, fmt.Errorf("error upserting books: %w", err)
+		}
+		err := upsertBook(ctx, tx, query, book, &results[i])
+		if err != nil {
+			results[i].Err = err
+			if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT upsert_book"); err != nil {
+				return nil, fmt.Errorf("error upserting books: %w", err)
+			}
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT upsert_book"); err != nil {
+			return nil, fmt.Errorf("error upserting books: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error upserting books: %w", err)
+	}
+	return results, nil
+}
+
+func upsertBook(ctx context.Context, tx *sqlx.Tx, query string, book *models.Book, result *UpsertResult) error {
+	if book.WorkID == "" {
+		book.WorkID = book.ID
+	}
+	rows, err := sqlx.NamedQueryContext(ctx, tx, query, book)
+	if err != nil {
+		return fmt.Errorf("error upserting book: %w", err)
+	}
+	if rows.Next() {
+		err = rows.Scan(&result.BookID, &book.WorkID, &result.Created)
+	} else {
+		err = rows.Err()
+	}
+	rows.Close()
+	if err != nil {
+		return fmt.Errorf("error upserting book: %w", err)
+	}
+	book.ID = result.BookID
+	if result.Created {
+		if err := ensureWork(ctx, tx, book); err != nil {
+			return err
+		}
+	}
+	return setBookRelations(ctx, tx, book.ID, book.Authors, book.Genres)
+}
+
+// ensureWork creates the book's work within tx, named after the book, unless
+// it already exists.
+func ensureWork(ctx context.Context, tx *sqlx.Tx, book *models.Book) error {
+	_, err := tx.ExecContext(ctx, "INSERT INTO works (id, title, author) VALUES ($1, $2, $3) ON CONFLICT (id) DO NOTHING", book.WorkID, book.Title, book.Author)
+	if err != nil {
+		return fmt.Errorf("error creating work %s: %w", book.WorkID, err)
+	}
 	return nil
 }
 
-// DeleteBook deletes a book by its ID.
-func (r *bookRepository) DeleteBook(ctx context.Context, id string) error {
-	_, err := r.db.ExecContext(ctx, "DELETE FROM books WHERE id=$1", id)
+// redundantOpenHold matches the open holds, on any of a set of books, of
+// readers with a better open hold on one of them: a ready hold ranks before
+// a waiting one, then the oldest first. Its arguments are given by
+// redundantOpenHoldArgs.
+const redundantOpenHold = `h.book_id IN (?) AND h.status IN (?, ?) AND EXISTS (
+	SELECT 1 FROM holds k WHERE k.user_id = h.user_id AND k.book_id IN (?) AND k.status IN (?, ?) AND k.id <> h.id
+		AND ((k.status = ? AND h.status = ?) OR (k.status = h.status AND (k.created_at, k.id) < (h.created_at, h.id))))`
+
+func redundantOpenHoldArgs(bookIDs []string) []any {
+	return []any{
+		bookIDs, models.HoldStatusWaiting, models.HoldStatusReady,
+		bookIDs, models.HoldStatusWaiting, models.HoldStatusReady,
+		models.HoldStatusReady, models.HoldStatusWaiting,
+	}
+}
+
+// MergeBooks merges duplicate books into a surviving one in a single
+// transaction: their interactions, recommendations, holdings, loans and holds
+// are moved to the survivor, the other editions of their works join the
+// survivor's work, and the duplicates are deleted. Each user keeps one
+// recommendation: one of the survivor if they have it, otherwise their
+// best-scoring one of the duplicates. Each reader keeps one open hold, the
+// first of theirs to be ready or else the oldest; the others are cancelled,
+// and copies on the hold shelf for them are made available again.
+func (r *bookRepository) MergeBooks(ctx context.Context, survivorID string, duplicateIDs []string) (*models.BookMergeResult, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error merging books: %w", err)
+	}
+	defer tx.Rollback()
+
+	var survivorWorkID string
+	if err := tx.GetContext(ctx, &survivorWorkID, "SELECT work_id FROM books WHERE id=$1 FOR UPDATE", survivorID); err != nil {
+		return nil, fmt.Errorf("error merging books: %w", err)
+	}
+	query, args, err := sqlx.In("SELECT DISTINCT work_id FROM books WHERE id IN (?) AND work_id <> ?", duplicateIDs, survivorWorkID)
+	if err != nil {
+		return nil, fmt.Errorf("error building duplicate works query: %w", err)
+	}
+	var workIDs []string
+	if err := tx.SelectContext(ctx, &workIDs, tx.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("error merging books: %w", err)
+	}
+
+	result := &models.BookMergeResult{SurvivorID: survivorID, MergedIDs: duplicateIDs}
+	bookIDs := append([]string{survivorID}, duplicateIDs...)
+	statements := []struct {
+		query string
+		args  []any
+		moved *int64
+	}{
+		{"DELETE FROM recommendations d WHERE d.book_id IN (?) AND EXISTS (SELECT 1 FROM recommendations s WHERE s.user_id = d.user_id AND s.book_id = ?)", []any{duplicateIDs, survivorID}, nil},
+		{"DELETE FROM recommendations d WHERE d.book_id IN (?) AND EXISTS (SELECT 1 FROM recommendations o WHERE o.user_id = d.user_id AND o.book_id IN (?) AND (o.score > d.score OR (o.score = d.score AND o.id < d.id)))", []any{duplicateIDs, duplicateIDs}, nil},
+		{"UPDATE recommendations SET book_id = ? WHERE book_id IN (?)", []any{survivorID, duplicateIDs}, &result.RecommendationsMoved},
+		{"UPDATE user_interactions SET book_id = ? WHERE book_id IN (?)", []any{survivorID, duplicateIDs}, &result.InteractionsMoved},
+		{"UPDATE holdings SET book_id = ? WHERE book_id IN (?)", []any{survivorID, duplicateIDs}, &result.HoldingsMoved},
+		{"UPDATE loans SET book_id = ? WHERE book_id IN (?)", []any{survivorID, duplicateIDs}, &result.LoansMoved},
+		{"UPDATE holdings SET status = ? WHERE status = ? AND id IN (SELECT h.holding_id FROM holds h WHERE " + redundantOpenHold + ")",
+			append([]any{models.HoldingStatusAvailable, models.HoldingStatusOnHold}, redundantOpenHoldArgs(bookIDs)...), nil},
+		{"UPDATE holds h SET status = ?, closed_at = ? WHERE " + redundantOpenHold,
+			append([]any{models.HoldStatusCancelled, time.Now()}, redundantOpenHoldArgs(bookIDs)...), &result.HoldsCancelled},
+		{"UPDATE holds SET book_id = ? WHERE book_id IN (?)", []any{survivorID, duplicateIDs}, &result.HoldsMoved},
+		{"DELETE FROM books WHERE id IN (?)", []any{duplicateIDs}, nil},
+	}
+	for _, st := range statements {
+		query, args, err := sqlx.In(st.query, st.args...)
+		if err != nil {
+			return nil, fmt.Errorf("error building merge query: %w", err)
+		}
+		res, err := tx.ExecContext(ctx, tx.Rebind(query), args...)
+		if err != nil {
+			return nil, fmt.Errorf("error merging books: %w", err)
+		}
+		if st.moved != nil {
+			if *st.moved, err = res.RowsAffected(); err != nil {
+				return nil, fmt.Errorf("error merging books: %w", err)
+			}
+		}
+	}
+	if len(workIDs) > 0 {
+		query, args, err = sqlx.In("UPDATE books SET work_id = ? WHERE work_id IN (?)", survivorWorkID, workIDs)
+		if err != nil {
+			return nil, fmt.Errorf("error building merge works query: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, tx.Rebind(query), args...); err != nil {
+			return nil, fmt.Errorf("error merging books: %w", err)
+		}
+		if err := deleteEmptyWorks(ctx, tx, workIDs); err != nil {
+			return nil, fmt.Errorf("error merging books: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error merging books: %w", err)
+	}
+	return result, nil
+}
+
+// SetBookRelations replaces a book's author and genre links. A nil slice
+// leaves that kind of link unchanged.
+func (r *bookRepository) SetBookRelations(ctx context.Context, bookID string, authors []models.BookAuthor, genres []models.BookGenre) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("error deleting book: %w", err)
+		return fmt.Errorf("error setting book relations: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := setBookRelations(ctx, tx, bookID, authors, genres); err != nil {
+		return fmt.Errorf("error setting book relations: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error setting book relations: %w", err)
 	}
 	return nil
 }
+
+// setBookRelations replaces a book's author and genre links within tx.
+func setBookRelations(ctx context.Context, tx *sqlx.Tx, bookID string, authors []models.BookAuthor, genres []models.BookGenre) error {
+	if authors != nil {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM book_authors WHERE book_id=$1", bookID); err != nil {
+			return fmt.Errorf("error clearing book authors: %w", err)
+		}
+		for i, a := range authors {
+			role := a.Role
+			if role == "" {
+				role = models.AuthorRoleAuthor
+			}
+			_, err := tx.ExecContext(ctx, "INSERT INTO book_authors (book_id, author_id, role, position) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING", bookID, a.ID, role, i)
+			if err != nil {
+				return fmt.Errorf("error linking author %s: %w", a.ID, err)
+			}
+		}
+	}
+	if genres != nil {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM book_genres WHERE book_id=$1", bookID); err != nil {
+			return fmt.Errorf("error clearing book genres: %w", err)
+		}
+		for _, g := range genres {
+			_, err := tx.ExecContext(ctx, "INSERT INTO book_genres (book_id, genre_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", bookID, g.ID)
+			if err != nil {
+				return fmt.Errorf("error linking genre %s: %w", g.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// GetBookAuthors retrieves the authors linked to the given books, in each book's display order.
+func (r *bookRepository) GetBookAuthors(ctx context.Context, bookIDs []string) ([]models.BookAuthor, error) {
+	if len(bookIDs) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`SELECT ba.book_id, a.id, a.name, a.biography, ba.role
+		FROM book_authors ba JOIN authors a ON a.id = ba.author_id
+		WHERE ba.book_id IN (?) ORDER BY ba.book_id, ba.position`, bookIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error building book authors query: %w", err)
+	}
+	var authors []models.BookAuthor
+	err = r.db.SelectContext(ctx, &authors, r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting book authors: %w", err)
+	}
+	return authors, nil
+}
+
+// GetBookGenres retrieves the genres linked to the given books.
+func (r *bookRepository) GetBookGenres(ctx context.Context, bookIDs []string) ([]models.BookGenre, error) {
+	if len(bookIDs) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`SELECT bg.book_id, g.id, g.name
+		FROM book_genres bg JOIN genres g ON g.id = bg.genre_id
+		WHERE bg.book_id IN (?) ORDER BY bg.book_id, g.name`, bookIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error building book genres query: %w", err)
+	}
+	var genres []models.BookGenre
+	err = r.db.SelectContext(ctx, &genres, r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting book genres: %w", err)
+	}
+	return genres, nil
+}
+
+// DeleteBook deletes a book by its ID, and its work if it was the work's
+// last edition. It reports false, deleting nothing, if the book has loans or
+// holds, which are kept as circulation records.
+func (r *bookRepository) DeleteBook(ctx context.Context, id string) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("error deleting book: %w", err)
+	}
+	defer tx.Rollback()
+
+	var circulated bool
+	// A copy moved to another book since keeps its loans, so the copies are
+	// checked as well as the loans of the book.
+	err = tx.GetContext(ctx, &circulated, `SELECT EXISTS (SELECT 1 FROM loans WHERE book_id = $1)
+		OR EXISTS (SELECT 1 FROM loans l JOIN holdings h ON h.id = l.holding_id WHERE h.book_id = $1)
+		OR EXISTS (SELECT 1 FROM holds WHERE book_id = $1)`, id)
+	if err != nil {
+		return false, fmt.Errorf("error checking book circulation: %w", err)
+	}
+	if circulated {
+		return false, nil
+	}
+	var workIDs []string
+	err = tx.SelectContext(ctx, &workIDs, "DELETE FROM books WHERE id=$1 RETURNING work_id", id)
+	if err != nil {
+		return false, fmt.Errorf("error deleting book: %w", err)
+	}
+	if err := deleteEmptyWorks(ctx, tx, workIDs); err != nil {
+		return false, fmt.Errorf("error deleting book: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("error deleting book: %w", err)
+	}
+	return true, nil
+}
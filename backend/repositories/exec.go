@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// execChanged runs a statement, on its own or in a transaction, and reports
+// whether it changed any rows, for conditional updates that guard against
+// concurrent changes.
+func execChanged(ctx context.Context, db sqlx.ExecerContext, query string, args ...any) (bool, error) {
+	res, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
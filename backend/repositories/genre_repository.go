@@ -8,10 +8,27 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+// genreColumns is the column list selected for models.Genre.
+const genreColumns = "id, name, COALESCE(parent_id, '') AS parent_id, ARRAY(SELECT a.name FROM genre_aliases a WHERE a.genre_id = genres.id ORDER BY a.name) AS aliases"
+
+// genreSubtreeQuery selects the IDs of the genres matching a condition and of
+// all their descendants.
+const genreSubtreeQuery = `WITH RECURSIVE subtree AS (
+		SELECT id FROM genres WHERE %s
+		UNION
+		SELECT g.id FROM genres g JOIN subtree s ON g.parent_id = s.id
+	) SELECT id FROM subtree`
+
+// maxGenreDepth bounds how far up the taxonomy ancestors are followed, in
+// case a cycle was ever stored.
+const maxGenreDepth = 100
+
 // GenreRepository defines the interface for genre data operations.
 type GenreRepository interface {
 	GetGenreByID(ctx context.Context, id string) (*models.Genre, error)
-	GetAllGenres(ctx context.Context) ([]models.Genre, error)
+	GetAllGenres(ctx context.Context, page models.PageRequest) (*models.Page[models.Genre], error)
+	GetGenreByName(ctx context.Context, name string) (*models.Genre, error)
+	GetGenreAncestorIDs(ctx context.Context, id string) ([]string, error)
 	CreateGenre(ctx context.Context, genre *models.Genre) error
 	UpdateGenre(ctx context.Context, genre *models.Genre) error
 	DeleteGenre(ctx context.Context, id string) error
@@ -30,48 +47,132 @@ func NewGenreRepository(db *sqlx.DB) GenreRepository {
 // GetGenreByID retrieves a genre by its ID.
 func (r *genreRepository) GetGenreByID(ctx context.Context, id string) (*models.Genre, error) {
 	var genre models.Genre
-	err := r.db.GetContext(ctx, &genre, "SELECT id, name FROM genres WHERE id=$1", id)
+	err := r.db.GetContext(ctx, &genre, "SELECT "+genreColumns+" FROM genres WHERE id=$1", id)
 	if err != nil {
 		return nil, fmt.Errorf("error getting genre by ID: %w", err)
 	}
 	return &genre, nil
 }
 
-// GetAllGenres retrieves all genres.
-func (r *genreRepository) GetAllGenres(ctx context.Context) ([]models.Genre, error) {
-	var genres []models.Genre
-	err := r.db.SelectContext(ctx, &genres, "SELECT id, name FROM genres")
+// genreKeyset orders genres by name for pagination.
+var genreKeyset = keyset[models.Genre]{
+	name:    "genres",
+	columns: []string{"name", "id"},
+	values:  func(g models.Genre) []any { return []any{g.Name, g.ID} },
+}
+
+// GetAllGenres retrieves a page of all genres.
+func (r *genreRepository) GetAllGenres(ctx context.Context, page models.PageRequest) (*models.Page[models.Genre], error) {
+	genres, err := selectPage(ctx, r.db, "SELECT "+genreColumns+" FROM genres", nil, genreKeyset, page)
 	if err != nil {
 		return nil, fmt.Errorf("error getting all genres: %w", err)
 	}
 	return genres, nil
 }
 
-// CreateGenre creates a new genre.
+// GetGenreByName retrieves the genre with a name or alias, ignoring case.
+func (r *genreRepository) GetGenreByName(ctx context.Context, name string) (*models.Genre, error) {
+	var genre models.Genre
+	err := r.db.GetContext(ctx, &genre, "SELECT "+genreColumns+` FROM genres
+		WHERE lower(name) = lower($1) OR id IN (SELECT genre_id FROM genre_aliases WHERE lower(name) = lower($1))
+		LIMIT 1`, name)
+	if err != nil {
+		return nil, fmt.Errorf("error getting genre by name: %w", err)
+	}
+	return &genre, nil
+}
+
+// GetGenreAncestorIDs retrieves the IDs of a genre's parent, its parent's
+// parent and so on up to the top of the taxonomy, nearest first.
+func (r *genreRepository) GetGenreAncestorIDs(ctx context.Context, id string) ([]string, error) {
+	var ids []string
+	err := r.db.SelectContext(ctx, &ids, `WITH RECURSIVE ancestors AS (
+			SELECT parent_id AS id, 1 AS depth FROM genres WHERE id = $1 AND parent_id IS NOT NULL
+			UNION
+			SELECT g.parent_id, a.depth + 1 FROM genres g JOIN ancestors a ON g.id = a.id
+			WHERE g.parent_id IS NOT NULL AND a.depth < $2
+		)
+		SELECT id FROM ancestors ORDER BY depth`, id, maxGenreDepth)
+	if err != nil {
+		return nil, fmt.Errorf("error getting genre ancestors: %w", err)
+	}
+	return ids, nil
+}
+
+// CreateGenre creates a new genre with its aliases.
 func (r *genreRepository) CreateGenre(ctx context.Context, genre *models.Genre) error {
-	query := `INSERT INTO genres (id, name) VALUES (:id, :name)`
-	_, err := r.db.NamedExecContext(ctx, query, genre)
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("error creating genre: %w", err)
 	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO genres (id, name, parent_id) VALUES (:id, :name, NULLIF(:parent_id, ''))`
+	_, err = tx.NamedExecContext(ctx, query, genre)
+	if err != nil {
+		return fmt.Errorf("error creating genre: %w", err)
+	}
+	if err := insertGenreAliases(ctx, tx, genre); err != nil {
+		return fmt.Errorf("error creating genre: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error creating genre: %w", err)
+	}
 	return nil
 }
 
-// UpdateGenre updates an existing genre.
+// UpdateGenre updates an existing genre, replacing its aliases.
 func (r *genreRepository) UpdateGenre(ctx context.Context, genre *models.Genre) error {
-	query := `UPDATE genres SET name=:name WHERE id=:id`
-	_, err := r.db.NamedExecContext(ctx, query, genre)
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("error updating genre: %w", err)
 	}
+	defer tx.Rollback()
+
+	query := `UPDATE genres SET name=:name, parent_id=NULLIF(:parent_id, '') WHERE id=:id`
+	_, err = tx.NamedExecContext(ctx, query, genre)
+	if err != nil {
+		return fmt.Errorf("error updating genre: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM genre_aliases WHERE genre_id=$1", genre.ID); err != nil {
+		return fmt.Errorf("error updating genre: %w", err)
+	}
+	if err := insertGenreAliases(ctx, tx, genre); err != nil {
+		return fmt.Errorf("error updating genre: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error updating genre: %w", err)
+	}
+	return nil
+}
+
+// insertGenreAliases inserts a genre's aliases within tx.
+func insertGenreAliases(ctx context.Context, tx *sqlx.Tx, genre *models.Genre) error {
+	for _, alias := range genre.Aliases {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO genre_aliases (genre_id, name) VALUES ($1, $2)", genre.ID, alias); err != nil {
+			return fmt.Errorf("error adding alias %q: %w", alias, err)
+		}
+	}
 	return nil
 }
 
-// DeleteGenre deletes a genre by its ID.
+// DeleteGenre deletes a genre by its ID. Its subgenres move up to its parent.
 func (r *genreRepository) DeleteGenre(ctx context.Context, id string) error {
-	_, err := r.db.ExecContext(ctx, "DELETE FROM genres WHERE id=$1", id)
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("error deleting genre: %w", err)
 	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "UPDATE genres SET parent_id = (SELECT parent_id FROM genres WHERE id=$1) WHERE parent_id=$1", id)
+	if err != nil {
+		return fmt.Errorf("error deleting genre: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM genres WHERE id=$1", id); err != nil {
+		return fmt.Errorf("error deleting genre: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error deleting genre: %w", err)
+	}
 	return nil
 }
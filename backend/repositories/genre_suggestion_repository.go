@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"book-recommendation-system/backend/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// genreSuggestionColumns is the column list selected for models.GenreSuggestion.
+const genreSuggestionColumns = `id, book_id, genre_id, confidence, status, created_at, reviewed_at,
+	(SELECT b.title FROM books b WHERE b.id = genre_suggestions.book_id) AS book_title,
+	(SELECT g.name FROM genres g WHERE g.id = genre_suggestions.genre_id) AS genre_name`
+
+// GenreSuggestionRepository defines the interface for genre suggestion data operations.
+type GenreSuggestionRepository interface {
+	GetGenreSuggestionByID(ctx context.Context, id string) (*models.GenreSuggestion, error)
+	GetGenreSuggestions(ctx context.Context, status string, page models.PageRequest) (*models.Page[models.GenreSuggestion], error)
+	ReplacePendingSuggestions(ctx context.Context, suggestions []models.GenreSuggestion) (int, error)
+	ReviewGenreSuggestion(ctx context.Context, id, status string) (bool, error)
+}
+
+// genreSuggestionRepository implements GenreSuggestionRepository using sqlx.
+type genreSuggestionRepository struct {
+	db *sqlx.DB
+}
+
+// NewGenreSuggestionRepository creates a new GenreSuggestionRepository.
+func NewGenreSuggestionRepository(db *sqlx.DB) GenreSuggestionRepository {
+	return &genreSuggestionRepository{db: db}
+}
+
+// GetGenreSuggestionByID retrieves a genre suggestion by its ID, returning nil
+// if there is none.
+func (r *genreSuggestionRepository) GetGenreSuggestionByID(ctx context.Context, id string) (*models.GenreSuggestion, error) {
+	var suggestion models.GenreSuggestion
+	err := r.db.GetContext(ctx, &suggestion, "SELECT "+genreSuggestionColumns+" FROM genre_suggestions WHERE id=$1", id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting genre suggestion by ID: %w", err)
+	}
+	return &suggestion, nil
+}
+
+// genreSuggestionKeyset orders suggestions most confident first for pagination.
+var genreSuggestionKeyset = keyset[models.GenreSuggestion]{
+	name:    "genre_suggestions",
+	columns: []string{"confidence", "id"},
+	desc:    true,
+	values:  func(s models.GenreSuggestion) []any { return []any{s.Confidence, s.ID} },
+}
+
+// GetGenreSuggestions retrieves a page of the suggestions with a status, most
+// confident first.
+func (r *genreSuggestionRepository) GetGenreSuggestions(ctx context.Context, status string, page models.PageRequest) (*models.Page[models.GenreSuggestion], error) {
+	b := &queryBuilder{}
+	b.where("status", "status = ?", status)
+	suggestions, err := selectPage(ctx, r.db, "SELECT "+genreSuggestionColumns+" FROM genre_suggestions", b, genreSuggestionKeyset, page)
+	if err != nil {
+		return nil, fmt.Errorf("error getting genre suggestions: %w", err)
+	}
+	return suggestions, nil
+}
+
+// ReplacePendingSuggestions replaces every pending suggestion with the given
+// ones, in one transaction, and returns how many were stored. Suggestions
+// already reviewed are kept and not made again.
+func (r *genreSuggestionRepository) ReplacePendingSuggestions(ctx context.Context, suggestions []models.GenreSuggestion) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error replacing genre suggestions: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM genre_suggestions WHERE status = $1", models.GenreSuggestionPending); err != nil {
+		return 0, fmt.Errorf("error clearing pending genre suggestions: %w", err)
+	}
+	stored := 0
+	for _, s := range suggestions {
+		res, err := tx.NamedExecContext(ctx, `INSERT INTO genre_suggestions (id, book_id, genre_id, confidence, status, created_at)
+			VALUES (:id, :book_id, :genre_id, :confidence, :status, :created_at)
+			ON CONFLICT (book_id, genre_id) DO NOTHING`, s)
+		if err != nil {
+			return 0, fmt.Errorf("error storing genre suggestion: %w", err)
+		}
+		if n, err := res.RowsAffected(); err == nil {
+			stored += int(n)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error replacing genre suggestions: %w", err)
+	}
+	return stored, nil
+}
+
+// ReviewGenreSuggestion records the review of a pending suggestion. Accepting
+// it links the book to the genre and adds the genre's name to the book's
+// genre text, in the same transaction. It reports false, changing nothing, if
+// the suggestion is no longer pending.
+func (r *genreSuggestionRepository) ReviewGenreSuggestion(ctx context.Context, id, status string) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("error reviewing genre suggestion: %w", err)
+	}
+	defer tx.Rollback()
+
+	var s struct {
+		BookID  string `db:"book_id"`
+		GenreID string `db:"genre_id"`
+	}
+	err = tx.GetContext(ctx, &s, `UPDATE genre_suggestions SET status = $1, reviewed_at = $2
+		WHERE id = $3 AND status = $4
+		RETURNING book_id, genre_id`, status, time.Now(), id, models.GenreSuggestionPending)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error reviewing genre suggestion: %w", err)
+	}
+	if status == models.GenreSuggestionAccepted {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO book_genres (book_id, genre_id) VALUES ($1, $2) ON CONFLICT DO NOTHING", s.BookID, s.GenreID); err != nil {
+			return false, fmt.Errorf("error linking suggested genre: %w", err)
+		}
+		// The genre text is only extended while it fits its column.
+		_, err := tx.ExecContext(ctx, `UPDATE books SET genre = CASE
+				WHEN COALESCE(books.genre, '') = '' THEN g.name
+				WHEN length(books.genre) + length(g.name) + 2 <= 255 THEN books.genre || ', ' || g.name
+				ELSE books.genre END
+			FROM genres g WHERE books.id = $1 AND g.id = $2`, s.BookID, s.GenreID)
+		if err != nil {
+			return false, fmt.Errorf("error setting suggested genre: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("error reviewing genre suggestion: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	earthRadiusKm = 6371.0
+	kmPerDegree   = 111.045
+)
+
+// haversineSQL returns a SQL expression for the great-circle distance in
+// kilometres between the given latitude/longitude columns and a point. It
+// takes three bind parameters, in order: latitude, latitude, longitude. The
+// ASIN argument is clamped to 1, which rounding can exceed for points nearly
+// opposite each other.
+func haversineSQL(latColumn, lngColumn string) string {
+	return fmt.Sprintf(
+		"%[3]g * 2 * ASIN(LEAST(1, SQRT(POWER(SIN(RADIANS(%[1]s - ?) / 2), 2) + COS(RADIANS(?)) * COS(RADIANS(%[1]s)) * POWER(SIN(RADIANS(%[2]s - ?) / 2), 2))))",
+		latColumn, lngColumn, earthRadiusKm)
+}
+
+// boundingBox returns a latitude/longitude box that contains every point
+// within radiusKm of (lat, lng), used as an index-friendly prefilter before
+// computing exact distances. The longitude span is that of the circle's
+// tangent meridians, asin(sin(r/R) / cos(lat)); near the poles or the
+// antimeridian it widens to the full circle.
+func boundingBox(lat, lng, radiusKm float64) (minLat, maxLat, minLng, maxLng float64) {
+	deltaLat := radiusKm / kmPerDegree
+	minLat, maxLat = math.Max(lat-deltaLat, -90), math.Min(lat+deltaLat, 90)
+
+	sinSpan := math.Sin(radiusKm/earthRadiusKm) / math.Cos(lat*math.Pi/180)
+	if maxLat >= 90 || minLat <= -90 || sinSpan >= 1 {
+		return minLat, maxLat, -180, 180
+	}
+	deltaLng := math.Asin(sinSpan) * 180 / math.Pi
+	if lng-deltaLng < -180 || lng+deltaLng > 180 {
+		return minLat, maxLat, -180, 180
+	}
+	return minLat, maxLat, lng - deltaLng, lng + deltaLng
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"math"
+	"testing"
+)
+
+// destination returns the point distanceKm from (lat, lng) along an initial
+// bearing, in degrees.
+func destination(lat, lng, bearing, distanceKm float64) (float64, float64) {
+	rad := math.Pi / 180
+	d := distanceKm / earthRadiusKm
+	lat1, lng1, b := lat*rad, lng*rad, bearing*rad
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(d) + math.Cos(lat1)*math.Sin(d)*math.Cos(b))
+	lng2 := lng1 + math.Atan2(math.Sin(b)*math.Sin(d)*math.Cos(lat1), math.Cos(d)-math.Sin(lat1)*math.Sin(lat2))
+	return lat2 / rad, lng2 / rad
+}
+
+func TestBoundingBoxContainsCircle(t *testing.T) {
+	tests := []struct {
+		lat, lng, radiusKm float64
+	}{
+		{0, 0, 10},
+		{51.5, -0.12, 25},
+		{-33.9, 151.2, 100},
+		{60, 10, 500},
+		{80, 20, 500},
+		{-75, -60, 300},
+	}
+	for _, tt := range tests {
+		minLat, maxLat, minLng, maxLng := boundingBox(tt.lat, tt.lng, tt.radiusKm)
+		for bearing := 0.0; bearing < 360; bearing += 1 {
+			lat, lng := destination(tt.lat, tt.lng, bearing, tt.radiusKm*0.999)
+			if lat < minLat || lat > maxLat || lng < minLng || lng > maxLng {
+				t.Errorf("boundingBox(%g, %g, %g) = [%g, %g] x [%g, %g], missing (%g, %g) at bearing %g",
+					tt.lat, tt.lng, tt.radiusKm, minLat, maxLat, minLng, maxLng, lat, lng, bearing)
+				break
+			}
+		}
+	}
+}
+
+func TestBoundingBoxWidensNearPoles(t *testing.T) {
+	if _, _, minLng, maxLng := boundingBox(89.9, 0, 50); minLng != -180 || maxLng != 180 {
+		t.Errorf("boundingBox near the pole spans [%g, %g], want the full circle", minLng, maxLng)
+	}
+	if _, _, minLng, maxLng := boundingBox(0, 179.99, 10); minLng != -180 || maxLng != 180 {
+		t.Errorf("boundingBox across the antimeridian spans [%g, %g], want the full circle", minLng, maxLng)
+	}
+}
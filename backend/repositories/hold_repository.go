@@ -0,0 +1,215 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"book-recommendation-system/backend/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// holdColumns is the column list selected for models.Hold.
+const holdColumns = `id, book_id, user_id, COALESCE(library_id, '') AS library_id, pickup_library_id, status,
+	COALESCE(holding_id, '') AS holding_id, created_at, expires_at, ready_at, pickup_by, closed_at`
+
+// HoldRepository defines the interface for hold data operations.
+type HoldRepository interface {
+	GetHoldByID(ctx context.Context, id string) (*models.Hold, error)
+	GetHolds(ctx context.Context, filter models.HoldFilter, page models.PageRequest) (*models.Page[models.Hold], error)
+	GetOpenHold(ctx context.Context, bookID, userID string) (*models.Hold, error)
+	GetReadyHoldByHolding(ctx context.Context, holdingID string) (*models.Hold, error)
+	GetWaitingHolds(ctx context.Context, bookID string) ([]models.Hold, error)
+	GetLapsedHolds(ctx context.Context, now time.Time) ([]models.Hold, error)
+	CreateHold(ctx context.Context, hold *models.Hold) error
+	AssignHold(ctx context.Context, holdID, holdingID string, readyAt, pickupBy time.Time) (bool, error)
+	CloseHold(ctx context.Context, id, status string, closedAt time.Time) (*models.Hold, error)
+}
+
+// holdRepository implements HoldRepository using sqlx.
+type holdRepository struct {
+	db *sqlx.DB
+}
+
+// NewHoldRepository creates a new HoldRepository.
+func NewHoldRepository(db *sqlx.DB) HoldRepository {
+	return &holdRepository{db: db}
+}
+
+// GetHoldByID retrieves a hold by its ID.
+func (r *holdRepository) GetHoldByID(ctx context.Context, id string) (*models.Hold, error) {
+	var hold models.Hold
+	err := r.db.GetContext(ctx, &hold, "SELECT "+holdColumns+" FROM holds WHERE id=$1", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting hold by ID: %w", err)
+	}
+	return &hold, nil
+}
+
+// holdKeyset orders holds oldest first, the order queues are served in, for
+// pagination.
+var holdKeyset = keyset[models.Hold]{
+	name:    "holds",
+	columns: []string{"created_at", "id"},
+	values:  func(h models.Hold) []any { return []any{h.CreatedAt, h.ID} },
+}
+
+// GetHolds retrieves a page of the holds matching a filter, oldest first.
+func (r *holdRepository) GetHolds(ctx context.Context, filter models.HoldFilter, page models.PageRequest) (*models.Page[models.Hold], error) {
+	b := &queryBuilder{}
+	if filter.UserID != "" {
+		b.where("user", "user_id = ?", filter.UserID)
+	}
+	if filter.BookID != "" {
+		b.where("book", "book_id = ?", filter.BookID)
+	}
+	if filter.PickupLibraryID != "" {
+		b.where("pickup", "pickup_library_id = ?", filter.PickupLibraryID)
+	}
+	b.whereIn("status", "status", filter.Statuses)
+	holds, err := selectPage(ctx, r.db, "SELECT "+holdColumns+" FROM holds", b, holdKeyset, page)
+	if err != nil {
+		return nil, fmt.Errorf("error getting holds: %w", err)
+	}
+	return holds, nil
+}
+
+// getHold runs a query for at most one hold, returning nil if there is none.
+func (r *holdRepository) getHold(ctx context.Context, query string, args ...any) (*models.Hold, error) {
+	var hold models.Hold
+	err := r.db.GetContext(ctx, &hold, "SELECT "+holdColumns+" FROM holds WHERE "+query, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+// GetOpenHold retrieves a reader's waiting or ready hold on a book, returning
+// nil if they have none.
+func (r *holdRepository) GetOpenHold(ctx context.Context, bookID, userID string) (*models.Hold, error) {
+	hold, err := r.getHold(ctx, "book_id=$1 AND user_id=$2 AND status IN ($3, $4)", bookID, userID, models.HoldStatusWaiting, models.HoldStatusReady)
+	if err != nil {
+		return nil, fmt.Errorf("error getting open hold: %w", err)
+	}
+	return hold, nil
+}
+
+// GetReadyHoldByHolding retrieves the ready hold a copy is assigned to,
+// returning nil if it is assigned to none.
+func (r *holdRepository) GetReadyHoldByHolding(ctx context.Context, holdingID string) (*models.Hold, error) {
+	hold, err := r.getHold(ctx, "holding_id=$1 AND status=$2", holdingID, models.HoldStatusReady)
+	if err != nil {
+		return nil, fmt.Errorf("error getting ready hold by holding: %w", err)
+	}
+	return hold, nil
+}
+
+// GetWaitingHolds retrieves a book's queue: its waiting holds that have not
+// expired, oldest first.
+func (r *holdRepository) GetWaitingHolds(ctx context.Context, bookID string) ([]models.Hold, error) {
+	var holds []models.Hold
+	err := r.db.SelectContext(ctx, &holds, "SELECT "+holdColumns+` FROM holds
+		WHERE book_id = $1 AND status = $2 AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+		ORDER BY created_at, id`, bookID, models.HoldStatusWaiting)
+	if err != nil {
+		return nil, fmt.Errorf("error getting waiting holds: %w", err)
+	}
+	return holds, nil
+}
+
+// GetLapsedHolds retrieves the waiting holds past their expiry and the ready
+// holds past their pickup date.
+func (r *holdRepository) GetLapsedHolds(ctx context.Context, now time.Time) ([]models.Hold, error) {
+	var holds []models.Hold
+	err := r.db.SelectContext(ctx, &holds, "SELECT "+holdColumns+` FROM holds
+		WHERE (status = $1 AND expires_at <= $3) OR (status = $2 AND pickup_by <= $3)
+		ORDER BY created_at, id`, models.HoldStatusWaiting, models.HoldStatusReady, now)
+	if err != nil {
+		return nil, fmt.Errorf("error getting lapsed holds: %w", err)
+	}
+	return holds, nil
+}
+
+// CreateHold creates a new hold.
+func (r *holdRepository) CreateHold(ctx context.Context, hold *models.Hold) error {
+	query := `INSERT INTO holds (id, book_id, user_id, library_id, pickup_library_id, status, created_at, expires_at)
+		VALUES (:id, :book_id, :user_id, NULLIF(:library_id, ''), :pickup_library_id, :status, :created_at, :expires_at)`
+	_, err := r.db.NamedExecContext(ctx, query, hold)
+	if err != nil {
+		return fmt.Errorf("error creating hold: %w", err)
+	}
+	return nil
+}
+
+// AssignHold puts an available copy on the hold shelf for a waiting hold and
+// makes the hold ready, in one transaction. It reports false, changing
+// nothing, if the copy is no longer available or the hold no longer waiting.
+func (r *holdRepository) AssignHold(ctx context.Context, holdID, holdingID string, readyAt, pickupBy time.Time) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("error assigning copy to hold: %w", err)
+	}
+	defer tx.Rollback()
+
+	changed, err := execChanged(ctx, tx, "UPDATE holdings SET status = $1 WHERE id = $2 AND status = $3",
+		models.HoldingStatusOnHold, holdingID, models.HoldingStatusAvailable)
+	if err != nil {
+		return false, fmt.Errorf("error putting copy on hold: %w", err)
+	}
+	if !changed {
+		return false, nil
+	}
+	changed, err = execChanged(ctx, tx, "UPDATE holds SET status = $1, holding_id = $2, ready_at = $3, pickup_by = $4 WHERE id = $5 AND status = $6",
+		models.HoldStatusReady, holdingID, readyAt, pickupBy, holdID, models.HoldStatusWaiting)
+	if err != nil {
+		return false, fmt.Errorf("error making hold ready: %w", err)
+	}
+	if !changed {
+		return false, nil
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("error assigning copy to hold: %w", err)
+	}
+	return true, nil
+}
+
+// CloseHold closes a waiting or ready hold with a final status and, if a copy
+// was waiting on the hold shelf for it, makes the copy available again, in
+// one transaction. It returns the hold as it was before closing, or nil if
+// it was already closed.
+func (r *holdRepository) CloseHold(ctx context.Context, id, status string, closedAt time.Time) (*models.Hold, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error closing hold: %w", err)
+	}
+	defer tx.Rollback()
+
+	var hold models.Hold
+	err = tx.GetContext(ctx, &hold, "SELECT "+holdColumns+" FROM holds WHERE id = $1 AND status IN ($2, $3) FOR UPDATE",
+		id, models.HoldStatusWaiting, models.HoldStatusReady)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error closing hold: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE holds SET status = $1, closed_at = $2 WHERE id = $3", status, closedAt, id); err != nil {
+		return nil, fmt.Errorf("error closing hold: %w", err)
+	}
+	if hold.Status == models.HoldStatusReady && hold.HoldingID != "" {
+		_, err := tx.ExecContext(ctx, "UPDATE holdings SET status = $1 WHERE id = $2 AND status = $3",
+			models.HoldingStatusAvailable, hold.HoldingID, models.HoldingStatusOnHold)
+		if err != nil {
+			return nil, fmt.Errorf("error releasing held copy: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("error closing hold: %w", err)
+	}
+	return &hold, nil
+}
@@ -0,0 +1,252 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"book-recommendation-system/backend/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// holdingColumns is the column list selected for models.Holding.
+const holdingColumns = `id, library_id, book_id, COALESCE(barcode, '') AS barcode, shelf_location, status,
+	(SELECT l.name FROM libraries l WHERE l.id = holdings.library_id) AS library_name`
+
+// HoldingRepository defines the interface for library holding data operations.
+type HoldingRepository interface {
+	GetHoldingByID(ctx context.Context, id string) (*models.Holding, error)
+	GetHoldingByBarcode(ctx context.Context, barcode string) (*models.Holding, error)
+	GetHoldings(ctx context.Context, filter models.HoldingFilter, page models.PageRequest) (*models.Page[models.Holding], error)
+	GetBookHoldings(ctx context.Context, bookID string) ([]models.Holding, error)
+	GetLibraryHoldings(ctx context.Context, libraryID string, bookIDs []string) ([]models.Holding, error)
+	CreateHolding(ctx context.Context, holding *models.Holding) error
+	UpdateHolding(ctx context.Context, holding *models.Holding, status string) (bool, error)
+	DeleteHolding(ctx context.Context, id string) (bool, error)
+	GetAvailableBookLocationsNear(ctx context.Context, bookIDs []string, lat, lng, radiusKm float64) ([]models.BookLocation, error)
+	CountLibraryHoldings(ctx context.Context, libraryID string, bookIDs []string) (map[string]int, error)
+	CountAvailableCopies(ctx context.Context, bookIDs []string) (map[string]int, error)
+	CreateHoldings(ctx context.Context, holdings []models.Holding) error
+}
+
+// holdingRepository implements HoldingRepository using sqlx.
+type holdingRepository struct {
+	db *sqlx.DB
+}
+
+// NewHoldingRepository creates a new HoldingRepository.
+func NewHoldingRepository(db *sqlx.DB) HoldingRepository {
+	return &holdingRepository{db: db}
+}
+
+// GetHoldingByID retrieves a holding by its ID.
+func (r *holdingRepository) GetHoldingByID(ctx context.Context, id string) (*models.Holding, error) {
+	var holding models.Holding
+	err := r.db.GetContext(ctx, &holding, "SELECT "+holdingColumns+" FROM holdings WHERE id=$1", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting holding by ID: %w", err)
+	}
+	return &holding, nil
+}
+
+// GetHoldingByBarcode retrieves the copy with a barcode, returning nil if
+// there is none.
+func (r *holdingRepository) GetHoldingByBarcode(ctx context.Context, barcode string) (*models.Holding, error) {
+	var holding models.Holding
+	err := r.db.GetContext(ctx, &holding, "SELECT "+holdingColumns+" FROM holdings WHERE barcode=$1", barcode)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting holding by barcode: %w", err)
+	}
+	return &holding, nil
+}
+
+// holdingKeyset orders holdings by ID for pagination.
+var holdingKeyset = keyset[models.Holding]{
+	name:    "holdings",
+	columns: []string{"id"},
+	values:  func(h models.Holding) []any { return []any{h.ID} },
+}
+
+// GetHoldings retrieves a page of the holdings matching a filter.
+func (r *holdingRepository) GetHoldings(ctx context.Context, filter models.HoldingFilter, page models.PageRequest) (*models.Page[models.Holding], error) {
+	b := &queryBuilder{}
+	if filter.LibraryID != "" {
+		b.where("library", "library_id = ?", filter.LibraryID)
+	}
+	if filter.BookID != "" {
+		b.where("book", "book_id = ?", filter.BookID)
+	}
+	if filter.Barcode != "" {
+		b.where("barcode", "barcode = ?", filter.Barcode)
+	}
+	b.whereIn("status", "status", filter.Statuses)
+	holdings, err := selectPage(ctx, r.db, "SELECT "+holdingColumns+" FROM holdings", b, holdingKeyset, page)
+	if err != nil {
+		return nil, fmt.Errorf("error getting holdings: %w", err)
+	}
+	return holdings, nil
+}
+
+// GetBookHoldings retrieves every copy of a book, ordered by library name and
+// then by shelf location and barcode.
+func (r *holdingRepository) GetBookHoldings(ctx context.Context, bookID string) ([]models.Holding, error) {
+	var holdings []models.Holding
+	err := r.db.SelectContext(ctx, &holdings, "SELECT "+holdingColumns+` FROM holdings WHERE book_id=$1
+		ORDER BY library_name, library_id, shelf_location, barcode, id`, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting book holdings: %w", err)
+	}
+	return holdings, nil
+}
+
+// GetLibraryHoldings retrieves a library's copies of the given books, ordered
+// by book and then by shelf location and barcode.
+func (r *holdingRepository) GetLibraryHoldings(ctx context.Context, libraryID string, bookIDs []string) ([]models.Holding, error) {
+	if len(bookIDs) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In("SELECT "+holdingColumns+` FROM holdings WHERE library_id = ? AND book_id IN (?)
+		ORDER BY book_id, shelf_location, barcode, id`, libraryID, bookIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error building library holdings query: %w", err)
+	}
+	var holdings []models.Holding
+	err = r.db.SelectContext(ctx, &holdings, r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting library holdings: %w", err)
+	}
+	return holdings, nil
+}
+
+// CreateHolding creates a new holding. An empty barcode is stored as NULL so
+// that unlabelled copies do not collide.
+func (r *holdingRepository) CreateHolding(ctx context.Context, holding *models.Holding) error {
+	query := `INSERT INTO holdings (id, library_id, book_id, barcode, shelf_location, status)
+		VALUES (:id, :library_id, :book_id, NULLIF(:barcode, ''), :shelf_location, :status)`
+	_, err := r.db.NamedExecContext(ctx, query, holding)
+	if err != nil {
+		return fmt.Errorf("error creating holding: %w", err)
+	}
+	return nil
+}
+
+// UpdateHolding updates an existing holding whose status is still status.
+// It reports false, changing nothing, if the status has changed meanwhile,
+// such as by a checkout.
+func (r *holdingRepository) UpdateHolding(ctx context.Context, holding *models.Holding, status string) (bool, error) {
+	changed, err := execChanged(ctx, r.db, `UPDATE holdings SET library_id = $1, book_id = $2, barcode = NULLIF($3, ''),
+		shelf_location = $4, status = $5 WHERE id = $6 AND status = $7`,
+		holding.LibraryID, holding.BookID, holding.Barcode, holding.ShelfLocation, holding.Status, holding.ID, status)
+	if err != nil {
+		return false, fmt.Errorf("error updating holding: %w", err)
+	}
+	return changed, nil
+}
+
+// DeleteHolding deletes a holding by its ID unless it is on loan or on hold
+// or has ever been lent, as loans are kept as circulation records. It reports
+// false, deleting nothing, if the holding does not exist or cannot be deleted.
+func (r *holdingRepository) DeleteHolding(ctx context.Context, id string) (bool, error) {
+	changed, err := execChanged(ctx, r.db, `DELETE FROM holdings WHERE id = $1 AND status NOT IN ($2, $3)
+		AND NOT EXISTS (SELECT 1 FROM loans WHERE holding_id = $1)`,
+		id, models.HoldingStatusOnLoan, models.HoldingStatusOnHold)
+	if err != nil {
+		return false, fmt.Errorf("error deleting holding: %w", err)
+	}
+	return changed, nil
+}
+
+// GetAvailableBookLocationsNear retrieves, for each of the given books, the
+// libraries within radiusKm of (lat, lng) that have an available copy,
+// ordered by book and then by distance.
+func (r *holdingRepository) GetAvailableBookLocationsNear(ctx context.Context, bookIDs []string, lat, lng, radiusKm float64) ([]models.BookLocation, error) {
+	if len(bookIDs) == 0 {
+		return nil, nil
+	}
+	minLat, maxLat, minLng, maxLng := boundingBox(lat, lng, radiusKm)
+	query, args, err := sqlx.In(`SELECT book_id, library_id, library_name, available_copies, distance_km FROM (
+		SELECT h.book_id, l.id AS library_id, l.name AS library_name, COUNT(*) AS available_copies, `+haversineSQL("l.latitude", "l.longitude")+` AS distance_km
+		FROM holdings h JOIN libraries l ON l.id = h.library_id
+		WHERE h.status = ? AND h.book_id IN (?)
+			AND l.latitude BETWEEN ? AND ? AND l.longitude BETWEEN ? AND ?
+		GROUP BY h.book_id, l.id, l.name, l.latitude, l.longitude
+	) nearby WHERE distance_km <= ? ORDER BY book_id, distance_km`,
+		lat, lat, lng, models.HoldingStatusAvailable, bookIDs, minLat, maxLat, minLng, maxLng, radiusKm)
+	if err != nil {
+		return nil, fmt.Errorf("error building nearby book locations query: %w", err)
+	}
+	var locations []models.BookLocation
+	err = r.db.SelectContext(ctx, &locations, r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting nearby book locations: %w", err)
+	}
+	return locations, nil
+}
+
+// CountLibraryHoldings counts the copies of each of the given books held by a
+// library. Books it holds no copies of are absent from the result.
+func (r *holdingRepository) CountLibraryHoldings(ctx context.Context, libraryID string, bookIDs []string) (map[string]int, error) {
+	counts := make(map[string]int)
+	if len(bookIDs) == 0 {
+		return counts, nil
+	}
+	query, args, err := sqlx.In("SELECT book_id, COUNT(*) AS copies FROM holdings WHERE library_id = ? AND book_id IN (?) GROUP BY book_id", libraryID, bookIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error building library holdings count query: %w", err)
+	}
+	var rows []struct {
+		BookID string `db:"book_id"`
+		Copies int    `db:"copies"`
+	}
+	err = r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error counting library holdings: %w", err)
+	}
+	for _, row := range rows {
+		counts[row.BookID] = row.Copies
+	}
+	return counts, nil
+}
+
+// CountAvailableCopies counts the available copies of each of the given books
+// across all libraries. Books with no available copy are absent from the
+// result.
+func (r *holdingRepository) CountAvailableCopies(ctx context.Context, bookIDs []string) (map[string]int, error) {
+	counts := make(map[string]int)
+	if len(bookIDs) == 0 {
+		return counts, nil
+	}
+	query, args, err := sqlx.In("SELECT book_id, COUNT(*) AS copies FROM holdings WHERE status = ? AND book_id IN (?) GROUP BY book_id", models.HoldingStatusAvailable, bookIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error building available copies count query: %w", err)
+	}
+	var rows []struct {
+		BookID string `db:"book_id"`
+		Copies int    `db:"copies"`
+	}
+	err = r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error counting available copies: %w", err)
+	}
+	for _, row := range rows {
+		counts[row.BookID] = row.Copies
+	}
+	return counts, nil
+}
+
+// CreateHoldings creates holdings in a single statement.
+func (r *holdingRepository) CreateHoldings(ctx context.Context, holdings []models.Holding) error {
+	if len(holdings) == 0 {
+		return nil
+	}
+	query := `INSERT INTO holdings (id, library_id, book_id, status) VALUES (:id, :library_id, :book_id, :status)`
+	_, err := r.db.NamedExecContext(ctx, query, holdings)
+	if err != nil {
+		return fmt.Errorf("error creating holdings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"book-recommendation-system/backend/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// ImportJobRepository defines the interface for import job data operations.
+type ImportJobRepository interface {
+	GetImportJobByID(ctx context.Context, id string) (*models.ImportJob, error)
+	CreateImportJob(ctx context.Context, job *models.ImportJob) error
+	UpdateImportJob(ctx context.Context, job *models.ImportJob) error
+	ResumeImportJob(ctx context.Context, id string, updatedAt time.Time) (bool, error)
+}
+
+// importJobRepository implements ImportJobRepository using sqlx.
+type importJobRepository struct {
+	db *sqlx.DB
+}
+
+// NewImportJobRepository creates a new ImportJobRepository.
+func NewImportJobRepository(db *sqlx.DB) ImportJobRepository {
+	return &importJobRepository{db: db}
+}
+
+// GetImportJobByID retrieves an import job by its ID, returning nil if there is none.
+func (r *importJobRepository) GetImportJobByID(ctx context.Context, id string) (*models.ImportJob, error) {
+	var job models.ImportJob
+	err := r.db.GetContext(ctx, &job, "SELECT id, source, format, library_id, status, last_committed_row, created, updated, deleted, rejected, error, started_at, updated_at FROM import_jobs WHERE id=$1", id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting import job by ID: %w", err)
+	}
+	return &job, nil
+}
+
+// CreateImportJob creates a new import job.
+func (r *importJobRepository) CreateImportJob(ctx context.Context, job *models.ImportJob) error {
+	query := `INSERT INTO import_jobs (id, source, format, library_id, status, last_committed_row, created, updated, deleted, rejected, error, started_at, updated_at) VALUES (:id, :source, :format, :library_id, :status, :last_committed_row, :created, :updated, :deleted, :rejected, :error, :started_at, :updated_at)`
+	_, err := r.db.NamedExecContext(ctx, query, job)
+	if err != nil {
+		return fmt.Errorf("error creating import job: %w", err)
+	}
+	return nil
+}
+
+// UpdateImportJob updates an existing import job.
+func (r *importJobRepository) UpdateImportJob(ctx context.Context, job *models.ImportJob) error {
+	query := `UPDATE import_jobs SET status=:status, last_committed_row=:last_committed_row, created=:created, updated=:updated, deleted=:deleted, rejected=:rejected, error=:error, updated_at=:updated_at WHERE id=:id`
+	_, err := r.db.NamedExecContext(ctx, query, job)
+	if err != nil {
+		return fmt.Errorf("error updating import job: %w", err)
+	}
+	return nil
+}
+
+// ResumeImportJob marks a failed import job as running again and clears its
+// error. It reports false, changing nothing, when the job is not failed, so
+// two runs cannot resume the same job.
+func (r *importJobRepository) ResumeImportJob(ctx context.Context, id string, updatedAt time.Time) (bool, error) {
+	changed, err := execChanged(ctx, r.db, "UPDATE import_jobs SET status = $1, error = '', updated_at = $2 WHERE id = $3 AND status = $4",
+		models.ImportJobRunning, updatedAt, id, models.ImportJobFailed)
+	if err != nil {
+		return false, fmt.Errorf("error resuming import job: %w", err)
+	}
+	return changed, nil
+}
@@ -8,13 +8,17 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+// libraryColumns is the column list selected for models.Library.
+const libraryColumns = "id, name, COALESCE(address, '') AS address, latitude, longitude, loan_period_days, max_renewals, hold_pickup_days"
+
 // LibraryRepository defines the interface for library data operations.
 type LibraryRepository interface {
 	GetLibraryByID(ctx context.Context, id string) (*models.Library, error)
-	GetAllLibraries(ctx context.Context) ([]models.Library, error)
+	GetAllLibraries(ctx context.Context, page models.PageRequest) (*models.Page[models.Library], error)
+	GetLibrariesNear(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]models.NearbyLibrary, error)
 	CreateLibrary(ctx context.Context, library *models.Library) error
 	UpdateLibrary(ctx context.Context, library *models.Library) error
-	DeleteLibrary(ctx context.Context, id string) error
+	DeleteLibrary(ctx context.Context, id string) (bool, error)
 }
 
 // libraryRepository implements LibraryRepository using sqlx.
@@ -30,26 +34,51 @@ func NewLibraryRepository(db *sqlx.DB) LibraryRepository {
 // GetLibraryByID retrieves a library by its ID.
 func (r *libraryRepository) GetLibraryByID(ctx context.Context, id string) (*models.Library, error) {
 	var library models.Library
-	err := r.db.GetContext(ctx, &library, "SELECT id, name, address, latitude, longitude FROM libraries WHERE id=$1", id)
+	err := r.db.GetContext(ctx, &library, "SELECT "+libraryColumns+" FROM libraries WHERE id=$1", id)
 	if err != nil {
 		return nil, fmt.Errorf("error getting library by ID: %w", err)
 	}
 	return &library, nil
 }
 
-// GetAllLibraries retrieves all libraries.
-func (r *libraryRepository) GetAllLibraries(ctx context.Context) ([]models.Library, error) {
-	var libraries []models.Library
-	err := r.db.SelectContext(ctx, &libraries, "SELECT id, name, address, latitude, longitude FROM libraries")
+// libraryKeyset orders libraries by name for pagination.
+var libraryKeyset = keyset[models.Library]{
+	name:    "libraries",
+	columns: []string{"name", "id"},
+	values:  func(l models.Library) []any { return []any{l.Name, l.ID} },
+}
+
+// GetAllLibraries retrieves a page of all libraries.
+func (r *libraryRepository) GetAllLibraries(ctx context.Context, page models.PageRequest) (*models.Page[models.Library], error) {
+	libraries, err := selectPage(ctx, r.db, "SELECT "+libraryColumns+" FROM libraries", nil, libraryKeyset, page)
 	if err != nil {
 		return nil, fmt.Errorf("error getting all libraries: %w", err)
 	}
 	return libraries, nil
 }
 
+// GetLibrariesNear retrieves up to limit libraries within radiusKm of
+// (lat, lng), nearest first. Libraries without coordinates are never found.
+func (r *libraryRepository) GetLibrariesNear(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]models.NearbyLibrary, error) {
+	minLat, maxLat, minLng, maxLng := boundingBox(lat, lng, radiusKm)
+	query := `SELECT * FROM (
+		SELECT ` + libraryColumns + `, ` + haversineSQL("latitude", "longitude") + ` AS distance_km
+		FROM libraries
+		WHERE latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?
+	) nearby WHERE distance_km <= ? ORDER BY distance_km, id LIMIT ?`
+	var libraries []models.NearbyLibrary
+	err := r.db.SelectContext(ctx, &libraries, r.db.Rebind(query),
+		lat, lat, lng, minLat, maxLat, minLng, maxLng, radiusKm, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error getting nearby libraries: %w", err)
+	}
+	return libraries, nil
+}
+
 // CreateLibrary creates a new library.
 func (r *libraryRepository) CreateLibrary(ctx context.Context, library *models.Library) error {
-	query := `INSERT INTO libraries (id, name, address, latitude, longitude) VALUES (:id, :name, :address, :latitude, :longitude)`
+	query := `INSERT INTO libraries (id, name, address, latitude, longitude, loan_period_days, max_renewals, hold_pickup_days)
+		VALUES (:id, :name, :address, :latitude, :longitude, :loan_period_days, :max_renewals, :hold_pickup_days)`
 	_, err := r.db.NamedExecContext(ctx, query, library)
 	if err != nil {
 		return fmt.Errorf("error creating library: %w", err)
@@ -59,7 +88,8 @@ func (r *libraryRepository) CreateLibrary(ctx context.Context, library *models.L
 
 // UpdateLibrary updates an existing library.
 func (r *libraryRepository) UpdateLibrary(ctx context.Context, library *models.Library) error {
-	query := `UPDATE libraries SET name=:name, address=:address, latitude=:latitude, longitude=:longitude WHERE id=:id`
+	query := `UPDATE libraries SET name=:name, address=:address, latitude=:latitude, longitude=:longitude,
+		loan_period_days=:loan_period_days, max_renewals=:max_renewals, hold_pickup_days=:hold_pickup_days WHERE id=:id`
 	_, err := r.db.NamedExecContext(ctx, query, library)
 	if err != nil {
 		return fmt.Errorf("error updating library: %w", err)
@@ -67,11 +97,23 @@ func (r *libraryRepository) UpdateLibrary(ctx context.Context, library *models.L
 	return nil
 }
 
-// DeleteLibrary deletes a library by its ID.
-func (r *libraryRepository) DeleteLibrary(ctx context.Context, id string) error {
-	_, err := r.db.ExecContext(ctx, "DELETE FROM libraries WHERE id=$1", id)
+// DeleteLibrary deletes a library by its ID. It reports false, deleting
+// nothing, if the library has loans or holds, which are kept as circulation
+// records.
+func (r *libraryRepository) DeleteLibrary(ctx context.Context, id string) (bool, error) {
+	var circulated bool
+	err := r.db.GetContext(ctx, &circulated, `SELECT EXISTS (SELECT 1 FROM loans WHERE library_id = $1)
+		OR EXISTS (SELECT 1 FROM loans l JOIN holdings h ON h.id = l.holding_id WHERE h.library_id = $1)
+		OR EXISTS (SELECT 1 FROM holds WHERE library_id = $1 OR pickup_library_id = $1)`, id)
 	if err != nil {
-		return fmt.Errorf("error deleting library: %w", err)
+		return false, fmt.Errorf("error checking library circulation: %w", err)
 	}
-	return nil
+	if circulated {
+		return false, nil
+	}
+	_, err = r.db.ExecContext(ctx, "DELETE FROM libraries WHERE id=$1", id)
+	if err != nil {
+		return false, fmt.Errorf("error deleting library: %w", err)
+	}
+	return true, nil
 }
@@ -0,0 +1,185 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"book-recommendation-system/backend/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// loanColumns is the column list selected for models.Loan.
+const loanColumns = `id, holding_id, book_id, library_id, user_id, checked_out_at, due_at, renewals, returned_at,
+	(returned_at IS NULL AND due_at < CURRENT_TIMESTAMP) AS overdue`
+
+// LoanRepository defines the interface for loan data operations.
+type LoanRepository interface {
+	GetLoanByID(ctx context.Context, id string) (*models.Loan, error)
+	GetActiveLoanByHolding(ctx context.Context, holdingID string) (*models.Loan, error)
+	GetLoans(ctx context.Context, filter models.LoanFilter, page models.PageRequest) (*models.Page[models.Loan], error)
+	CheckOut(ctx context.Context, loan *models.Loan, borrow *models.UserInteraction, holdID string) (bool, error)
+	RenewLoan(ctx context.Context, id string, dueAt time.Time, maxRenewals int) (bool, error)
+	CheckIn(ctx context.Context, id string, returnedAt time.Time) (bool, error)
+}
+
+// loanRepository implements LoanRepository using sqlx.
+type loanRepository struct {
+	db *sqlx.DB
+}
+
+// NewLoanRepository creates a new LoanRepository.
+func NewLoanRepository(db *sqlx.DB) LoanRepository {
+	return &loanRepository{db: db}
+}
+
+// GetLoanByID retrieves a loan by its ID.
+func (r *loanRepository) GetLoanByID(ctx context.Context, id string) (*models.Loan, error) {
+	var loan models.Loan
+	err := r.db.GetContext(ctx, &loan, "SELECT "+loanColumns+" FROM loans WHERE id=$1", id)
+	if err != nil {
+		return nil, fmt.Errorf("error getting loan by ID: %w", err)
+	}
+	return &loan, nil
+}
+
+// GetActiveLoanByHolding retrieves the loan a copy is out on, returning nil
+// if it is not on loan.
+func (r *loanRepository) GetActiveLoanByHolding(ctx context.Context, holdingID string) (*models.Loan, error) {
+	var loan models.Loan
+	err := r.db.GetContext(ctx, &loan, "SELECT "+loanColumns+" FROM loans WHERE holding_id=$1 AND returned_at IS NULL", holdingID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting active loan by holding: %w", err)
+	}
+	return &loan, nil
+}
+
+// loanKeyset orders loans by due date, soonest (or most overdue) first, for
+// pagination.
+var loanKeyset = keyset[models.Loan]{
+	name:    "loans",
+	columns: []string{"due_at", "id"},
+	values:  func(l models.Loan) []any { return []any{l.DueAt, l.ID} },
+}
+
+// GetLoans retrieves a page of the loans matching a filter.
+func (r *loanRepository) GetLoans(ctx context.Context, filter models.LoanFilter, page models.PageRequest) (*models.Page[models.Loan], error) {
+	b := &queryBuilder{}
+	if filter.UserID != "" {
+		b.where("user", "user_id = ?", filter.UserID)
+	}
+	if filter.LibraryID != "" {
+		b.where("library", "library_id = ?", filter.LibraryID)
+	}
+	if filter.BookID != "" {
+		b.where("book", "book_id = ?", filter.BookID)
+	}
+	if filter.HoldingID != "" {
+		b.where("holding", "holding_id = ?", filter.HoldingID)
+	}
+	switch filter.Status {
+	case models.LoanStatusActive:
+		b.where("status", "returned_at IS NULL")
+	case models.LoanStatusOverdue:
+		b.where("status", "returned_at IS NULL AND due_at < CURRENT_TIMESTAMP")
+	case models.LoanStatusReturned:
+		b.where("status", "returned_at IS NOT NULL")
+	}
+	loans, err := selectPage(ctx, r.db, "SELECT "+loanColumns+" FROM loans", b, loanKeyset, page)
+	if err != nil {
+		return nil, fmt.Errorf("error getting loans: %w", err)
+	}
+	return loans, nil
+}
+
+// CheckOut records a loan and the borrow interaction it implies, and marks
+// the copy on loan, in one transaction. With a hold ID, the copy must be on
+// the hold shelf for that ready hold, which the loan fulfils; otherwise it
+// must be available. It reports false, storing nothing, if it is not.
+func (r *loanRepository) CheckOut(ctx context.Context, loan *models.Loan, borrow *models.UserInteraction, holdID string) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("error checking out copy: %w", err)
+	}
+	defer tx.Rollback()
+
+	copyStatus := models.HoldingStatusAvailable
+	if holdID != "" {
+		copyStatus = models.HoldingStatusOnHold
+	}
+	changed, err := execChanged(ctx, tx, "UPDATE holdings SET status = $1 WHERE id = $2 AND status = $3",
+		models.HoldingStatusOnLoan, loan.HoldingID, copyStatus)
+	if err != nil {
+		return false, fmt.Errorf("error marking copy on loan: %w", err)
+	}
+	if !changed {
+		return false, nil
+	}
+	if holdID != "" {
+		changed, err := execChanged(ctx, tx, "UPDATE holds SET status = $1, closed_at = $2 WHERE id = $3 AND status = $4 AND holding_id = $5",
+			models.HoldStatusFulfilled, loan.CheckedOutAt, holdID, models.HoldStatusReady, loan.HoldingID)
+		if err != nil {
+			return false, fmt.Errorf("error fulfilling hold: %w", err)
+		}
+		if !changed {
+			return false, nil
+		}
+	}
+	_, err = tx.NamedExecContext(ctx, `INSERT INTO loans (id, holding_id, book_id, library_id, user_id, checked_out_at, due_at, renewals)
+		VALUES (:id, :holding_id, :book_id, :library_id, :user_id, :checked_out_at, :due_at, :renewals)`, loan)
+	if err != nil {
+		return false, fmt.Errorf("error creating loan: %w", err)
+	}
+	_, err = tx.NamedExecContext(ctx, `INSERT INTO user_interactions (id, user_id, book_id, interaction_type, timestamp, device, locale, location, client_time_zone, rating)
+		VALUES (:id, :user_id, :book_id, :interaction_type, :timestamp, :device, :locale, :location, :client_time_zone, :rating)`, borrow)
+	if err != nil {
+		return false, fmt.Errorf("error recording borrow interaction: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("error checking out copy: %w", err)
+	}
+	return true, nil
+}
+
+// RenewLoan moves an active loan's due date and counts the renewal. It
+// reports false, changing nothing, if the loan was returned or has already
+// been renewed maxRenewals times.
+func (r *loanRepository) RenewLoan(ctx context.Context, id string, dueAt time.Time, maxRenewals int) (bool, error) {
+	changed, err := execChanged(ctx, r.db, "UPDATE loans SET due_at = $1, renewals = renewals + 1 WHERE id = $2 AND returned_at IS NULL AND renewals < $3",
+		dueAt, id, maxRenewals)
+	if err != nil {
+		return false, fmt.Errorf("error renewing loan: %w", err)
+	}
+	return changed, nil
+}
+
+// CheckIn closes an active loan and makes its copy available again, in one
+// transaction. It reports false if the loan was already returned.
+func (r *loanRepository) CheckIn(ctx context.Context, id string, returnedAt time.Time) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("error checking in loan: %w", err)
+	}
+	defer tx.Rollback()
+
+	var holdingID string
+	err = tx.GetContext(ctx, &holdingID, "UPDATE loans SET returned_at = $1 WHERE id = $2 AND returned_at IS NULL RETURNING holding_id", returnedAt, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error closing loan: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE holdings SET status = $1 WHERE id = $2", models.HoldingStatusAvailable, holdingID); err != nil {
+		return false, fmt.Errorf("error marking copy available: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("error checking in loan: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"book-recommendation-system/backend/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// keyset describes the order of a paginated listing. The columns must
+// identify a row uniquely (end with the primary key) and values must return
+// an item's values for them, in the same order.
+type keyset[T any] struct {
+	name    string // identifies the listing and order, so cursors cannot be reused across them
+	columns []string
+	desc    bool
+	values  func(T) []any
+}
+
+// cursor is the decoded form of an opaque pagination cursor.
+type cursor struct {
+	Name   string   `json:"n"`
+	Values []string `json:"v"`
+	Prev   bool     `json:"p,omitempty"`
+}
+
+func encodeCursor(c cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(raw, name string, columns int) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, models.ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Name != name || len(c.Values) != columns {
+		return nil, models.ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// cursorValues formats key values as text, which PostgreSQL converts back to
+// the column types when they are bound in the keyset comparison.
+func cursorValues(values []any) []string {
+	formatted := make([]string, len(values))
+	for i, v := range values {
+		switch v := v.(type) {
+		case string:
+			formatted[i] = v
+		case time.Time:
+			formatted[i] = v.Format(time.RFC3339Nano)
+		default:
+			formatted[i] = fmt.Sprint(v)
+		}
+	}
+	return formatted
+}
+
+// pageLimit applies the default and maximum page sizes.
+func pageLimit(limit int) int {
+	if limit <= 0 {
+		return models.DefaultPageLimit
+	}
+	return min(limit, models.MaxPageLimit)
+}
+
+// selectPage runs base (a SELECT ... FROM ... without WHERE or ORDER BY) with
+// the builder's conditions and returns the page of rows after, or before, the
+// requested cursor in keyset order.
+func selectPage[T any](ctx context.Context, db *sqlx.DB, base string, b *queryBuilder, ks keyset[T], req models.PageRequest) (*models.Page[T], error) {
+	if b == nil {
+		b = &queryBuilder{}
+	}
+	limit := pageLimit(req.Limit)
+
+	var after *cursor
+	if req.Cursor != "" {
+		var err error
+		after, err = decodeCursor(req.Cursor, ks.name, len(ks.columns))
+		if err != nil {
+			return nil, err
+		}
+	}
+	backwards := after != nil && after.Prev
+
+	// Walking backwards flips both the comparison and the order, and the
+	// rows are reversed again below.
+	desc := ks.desc != backwards
+	op, dir := ">", "ASC"
+	if desc {
+		op, dir = "<", "DESC"
+	}
+	if after != nil {
+		args := make([]any, len(after.Values))
+		for i, v := range after.Values {
+			args[i] = v
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(args)), ", ")
+		b.where("cursor", "("+strings.Join(ks.columns, ", ")+") "+op+" ("+placeholders+")", args...)
+	}
+	orderBy := make([]string, len(ks.columns))
+	for i, c := range ks.columns {
+		orderBy[i] = c + " " + dir
+	}
+
+	where, args := b.whereClause()
+	query := base + where + " ORDER BY " + strings.Join(orderBy, ", ") + " LIMIT ?"
+	args = append(args, limit+1)
+
+	var items []T
+	if err := db.SelectContext(ctx, &items, db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("error selecting page: %w", err)
+	}
+
+	more := len(items) > limit
+	if more {
+		items = items[:limit]
+	}
+	if backwards {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	page := &models.Page[T]{Items: items}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	if len(items) == 0 {
+		return page, nil
+	}
+	first, last := items[0], items[len(items)-1]
+	// Going forwards there is a next page only if more rows were found;
+	// going backwards we came from the next page, so it always exists.
+	if more || backwards {
+		page.NextCursor = encodeCursor(cursor{Name: ks.name, Values: cursorValues(ks.values(last))})
+	}
+	if (backwards && more) || (!backwards && after != nil) {
+		page.PrevCursor = encodeCursor(cursor{Name: ks.name, Values: cursorValues(ks.values(first)), Prev: true})
+	}
+	return page, nil
+}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"slices"
+	"strings"
+)
+
+// queryBuilder collects WHERE conditions for a statement. Conditions are
+// fixed SQL fragments written in code with ? placeholders, and every value
+// is passed as a bound argument, so caller input never becomes SQL text.
+// The assembled statement must be passed through sqlx.DB.Rebind before use.
+type queryBuilder struct {
+	conditions []queryCondition
+}
+
+// queryCondition is one WHERE condition. Its dimension names the filter it
+// implements, so facet counts can leave out the filter for their own facet.
+type queryCondition struct {
+	dimension string
+	sql       string
+	args      []any
+}
+
+// where adds a condition with one ? placeholder per arg, in order. The tests
+// check that the conditions built for every filter keep to this.
+func (b *queryBuilder) where(dimension, sql string, args ...any) {
+	b.conditions = append(b.conditions, queryCondition{dimension: dimension, sql: sql, args: args})
+}
+
+// whereIn adds an "expr IN (...)" condition with one placeholder per value.
+// It does nothing when values is empty.
+func (b *queryBuilder) whereIn(dimension, expr string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	args := make([]any, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	b.where(dimension, expr+" IN ("+placeholders(len(values))+")", args...)
+}
+
+// placeholders returns n comma-separated ? placeholders.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// whereClause returns the conditions as a WHERE clause, or "" when there
+// are none, and their arguments in order. Conditions for the excluded
+// dimensions are left out.
+func (b *queryBuilder) whereClause(exclude ...string) (string, []any) {
+	var parts []string
+	var args []any
+	for _, c := range b.conditions {
+		if slices.Contains(exclude, c.dimension) {
+			continue
+		}
+		parts = append(parts, "("+c.sql+")")
+		args = append(args, c.args...)
+	}
+	if len(parts) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(parts, " AND "), args
+}
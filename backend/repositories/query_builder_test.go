@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"strings"
+	"testing"
+
+	"book-recommendation-system/backend/models"
+)
+
+// checkPlaceholders fails the test if a condition's placeholders do not match
+// its arguments one for one.
+func checkPlaceholders(t *testing.T, b *queryBuilder) {
+	t.Helper()
+	for _, c := range b.conditions {
+		if n := strings.Count(c.sql, "?"); n != len(c.args) {
+			t.Errorf("%s condition %q has %d placeholders for %d args", c.dimension, c.sql, n, len(c.args))
+		}
+	}
+}
+
+func TestWhereClause(t *testing.T) {
+	b := &queryBuilder{}
+	if where, args := b.whereClause(); where != "" || args != nil {
+		t.Errorf("empty builder = %q, %v, want no clause", where, args)
+	}
+
+	b.where("year", "publication_year >= ?", 1990)
+	b.whereIn("format", "format", nil)
+	b.whereIn("author", "lower(author)", []string{"le guin", "tolkien"})
+	checkPlaceholders(t, b)
+
+	where, args := b.whereClause()
+	if want := " WHERE (publication_year >= ?) AND (lower(author) IN (?, ?))"; where != want {
+		t.Errorf("whereClause() = %q, want %q", where, want)
+	}
+	if len(args) != 3 || args[0] != 1990 || args[1] != "le guin" || args[2] != "tolkien" {
+		t.Errorf("whereClause() args = %v", args)
+	}
+
+	where, args = b.whereClause("author")
+	if want := " WHERE (publication_year >= ?)"; where != want || len(args) != 1 {
+		t.Errorf("whereClause(author) = %q, %v, want %q", where, args, want)
+	}
+}
+
+func TestBookFilterConditionsPlaceholders(t *testing.T) {
+	yes, no := true, false
+	filters := map[string]models.BookFilter{
+		"empty": {},
+		"every filter": {
+			Genres:     []string{"Fantasy", "Science Fiction"},
+			GenreIDs:   []string{"g1", "g2", "g3"},
+			Authors:    []string{"Ursula K. Le Guin"},
+			YearFrom:   1960,
+			YearTo:     1999,
+			Language:   "en",
+			Formats:    []string{"paperback", "ebook"},
+			Publishers: []string{"Ace", "Tor"},
+			Subjects:   []string{"wizards"},
+			PagesMin:   100,
+			PagesMax:   400,
+			LibraryID:  "lib-1",
+			Available:  &yes,
+		},
+		"unavailable anywhere":   {Available: &no},
+		"available at a library": {LibraryID: "lib-1", Available: &yes},
+		"one genre":              {Genres: []string{"Horror"}},
+	}
+	for name, filter := range filters {
+		t.Run(name, func(t *testing.T) {
+			b := bookFilterConditions(filter)
+			checkPlaceholders(t, b)
+			for _, exclude := range []string{"", "genre", "author", "year", "format"} {
+				where, args := b.whereClause(exclude)
+				if n := strings.Count(where, "?"); n != len(args) {
+					t.Errorf("whereClause(%q) has %d placeholders for %d args", exclude, n, len(args))
+				}
+			}
+		})
+	}
+}
@@ -10,9 +10,10 @@ import (
 
 // RecommendationRepository defines the interface for recommendation data operations.
 type RecommendationRepository interface {
-	GetAllRecommendations(ctx context.Context) ([]models.Recommendation, error)
-	GetRecommendationsByUserID(ctx context.Context, userID string) ([]models.Recommendation, error)
+	GetRecommendationByID(ctx context.Context, id string) (*models.Recommendation, error)
+	GetAllRecommendations(ctx context.Context, page models.PageRequest) (*models.Page[models.Recommendation], error)
 	GetRecommendationsByUserID(ctx context.Context, userID string) ([]models.Recommendation, error)
+	GetRecommendationsByUserIDs(ctx context.Context, userIDs []string) ([]models.Recommendation, error)
 	CreateRecommendation(ctx context.Context, recommendation *models.Recommendation) error
 	UpdateRecommendation(ctx context.Context, recommendation *models.Recommendation) error
 	DeleteRecommendation(ctx context.Context, id string) error
@@ -32,20 +33,27 @@ func NewRecommendationRepository(db *sqlx.DB) RecommendationRepository {
 func (r *recommendationRepository) GetRecommendationByID(ctx context.Context, id string) (*models.Recommendation, error) {
 	var recommendation models.Recommendation
 	err := r.db.GetContext(ctx, &recommendation, "SELECT id, user_id, book_id, score, generated_at FROM recommendations WHERE id=$1", id)
-// GetAllRecommendations retrieves all recommendations.
-func (r *recommendationRepository) GetAllRecommendations(ctx context.Context) ([]models.Recommendation, error) {
-	var recommendations []models.Recommendation
-	err := r.db.SelectContext(ctx, &recommendations, "SELECT id, user_id, book_id, score, generated_at FROM recommendations")
 	if err != nil {
-		return nil, fmt.Errorf("error getting all recommendations: %w", err)
+		return nil, fmt.Errorf("error getting recommendation by ID: %w", err)
 	}
-	return recommendations, nil
+	return &recommendation, nil
 }
 
-// GetRecommendationsByUserID retrieves recommendations for a specific user.
-		return nil, fmt.Errorf("error getting recommendation by ID: %w", err)
+// recommendationKeyset orders recommendations newest first for pagination.
+var recommendationKeyset = keyset[models.Recommendation]{
+	name:    "recommendations",
+	columns: []string{"generated_at", "id"},
+	desc:    true,
+	values:  func(rec models.Recommendation) []any { return []any{rec.GeneratedAt, rec.ID} },
+}
+
+// GetAllRecommendations retrieves a page of all recommendations, newest first.
+func (r *recommendationRepository) GetAllRecommendations(ctx context.Context, page models.PageRequest) (*models.Page[models.Recommendation], error) {
+	recommendations, err := selectPage(ctx, r.db, "SELECT id, user_id, book_id, score, generated_at FROM recommendations", nil, recommendationKeyset, page)
+	if err != nil {
+		return nil, fmt.Errorf("error getting all recommendations: %w", err)
 	}
-	return &recommendation, nil
+	return recommendations, nil
 }
 
 // GetRecommendationsByUserID retrieves recommendations for a specific user.
@@ -58,6 +66,23 @@ func (r *recommendationRepository) GetRecommendationsByUserID(ctx context.Contex
 	return recommendations, nil
 }
 
+// GetRecommendationsByUserIDs retrieves recommendations for any of the given users.
+func (r *recommendationRepository) GetRecommendationsByUserIDs(ctx context.Context, userIDs []string) ([]models.Recommendation, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In("SELECT id, user_id, book_id, score, generated_at FROM recommendations WHERE user_id IN (?)", userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error building recommendations by user IDs query: %w", err)
+	}
+	var recommendations []models.Recommendation
+	err = r.db.SelectContext(ctx, &recommendations, r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting recommendations by user IDs: %w", err)
+	}
+	return recommendations, nil
+}
+
 // CreateRecommendation creates a new recommendation.
 func (r *recommendationRepository) CreateRecommendation(ctx context.Context, recommendation *models.Recommendation) error {
 	query := `INSERT INTO recommendations (id, user_id, book_id, score, generated_at) VALUES (:id, :user_id, :book_id, :score, :generated_at)`
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"strings"
+	"unicode"
+)
+
+// buildTSQuery converts a user search string into to_tsquery syntax. Words
+// are ANDed together; "quoted phrases" must match as adjacent words and a
+// trailing * makes a word a prefix match (e.g. tolk* matches Tolkien).
+// Punctuation splits words the way to_tsvector does, so "o'brien" matches
+// as the phrase o <-> brien, and the result is always a valid tsquery. It
+// returns "" when the search contains no words.
+func buildTSQuery(q string) string {
+	var clauses []string
+	for i, part := range strings.Split(q, `"`) {
+		// Odd-numbered parts sit between a pair of quotes.
+		inPhrase := i%2 == 1
+		var phrase []string
+		for _, word := range strings.Fields(part) {
+			terms := tsQueryTerms(word)
+			if len(terms) == 0 {
+				continue
+			}
+			if inPhrase {
+				phrase = append(phrase, terms...)
+			} else if len(terms) == 1 {
+				clauses = append(clauses, terms[0])
+			} else {
+				clauses = append(clauses, "("+strings.Join(terms, " <-> ")+")")
+			}
+		}
+		if len(phrase) > 0 {
+			clauses = append(clauses, "("+strings.Join(phrase, " <-> ")+")")
+		}
+	}
+	return strings.Join(clauses, " & ")
+}
+
+// tsQueryTerms splits a search word into lowercase letter/digit runs, marking
+// the last one as a prefix match if the word ended in *.
+func tsQueryTerms(word string) []string {
+	terms := strings.FieldsFunc(strings.ToLower(word), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(terms) > 0 && strings.HasSuffix(word, "*") {
+		terms[len(terms)-1] += ":*"
+	}
+	return terms
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"book-recommendation-system/backend/models"
 	"github.com/jmoiron/sqlx"
@@ -10,9 +11,13 @@ import (
 
 // UserInteractionRepository defines the interface for user interaction data operations.
 type UserInteractionRepository interface {
-	GetAllUserInteractions(ctx context.Context) ([]models.UserInteraction, error)
-	GetUserInteractionsByUserID(ctx context.Context, userID string) ([]models.UserInteraction, error)
+	GetUserInteractionByID(ctx context.Context, id string) (*models.UserInteraction, error)
+	GetAllUserInteractions(ctx context.Context, page models.PageRequest) (*models.Page[models.UserInteraction], error)
 	GetUserInteractionsByUserID(ctx context.Context, userID string) ([]models.UserInteraction, error)
+	GetUserInteractionsByUserIDs(ctx context.Context, userIDs []string) ([]models.UserInteraction, error)
+	GetUserInteractionsByBookIDs(ctx context.Context, bookIDs []string) ([]models.UserInteraction, error)
+	GetRecentInteractionsByBookIDs(ctx context.Context, bookIDs []string, perBook int) ([]models.UserInteraction, error)
+	GetPopularBooks(ctx context.Context, since time.Time, limit int) ([]models.BookPopularity, error)
 	CreateUserInteraction(ctx context.Context, userInteraction *models.UserInteraction) error
 	UpdateUserInteraction(ctx context.Context, userInteraction *models.UserInteraction) error
 	DeleteUserInteraction(ctx context.Context, id string) error
@@ -31,36 +36,116 @@ func NewUserInteractionRepository(db *sqlx.DB) UserInteractionRepository {
 // GetUserInteractionByID retrieves a user interaction by its ID.
 func (r *userInteractionRepository) GetUserInteractionByID(ctx context.Context, id string) (*models.UserInteraction, error) {
 	var userInteraction models.UserInteraction
-	err := r.db.GetContext(ctx, &userInteraction, "SELECT id, user_id, book_id, interaction_type, timestamp FROM user_interactions WHERE id=$1", id)
-// GetAllUserInteractions retrieves all user interactions.
-func (r *userInteractionRepository) GetAllUserInteractions(ctx context.Context) ([]models.UserInteraction, error) {
-	var userInteractions []models.UserInteraction
-	err := r.db.SelectContext(ctx, &userInteractions, "SELECT id, user_id, book_id, interaction_type, timestamp FROM user_interactions")
+	err := r.db.GetContext(ctx, &userInteraction, "SELECT id, user_id, book_id, interaction_type, timestamp, device, locale, location, client_time_zone, rating FROM user_interactions WHERE id=$1", id)
 	if err != nil {
-		return nil, fmt.Errorf("error getting all user interactions: %w", err)
+		return nil, fmt.Errorf("error getting user interaction by ID: %w", err)
 	}
-	return userInteractions, nil
+	return &userInteraction, nil
 }
 
-// GetUserInteractionsByUserID retrieves user interactions for a specific user.
-		return nil, fmt.Errorf("error getting user interaction by ID: %w", err)
+// userInteractionKeyset orders user interactions newest first for pagination.
+var userInteractionKeyset = keyset[models.UserInteraction]{
+	name:    "user_interactions",
+	columns: []string{"timestamp", "id"},
+	desc:    true,
+	values:  func(ui models.UserInteraction) []any { return []any{ui.Timestamp, ui.ID} },
+}
+
+// GetAllUserInteractions retrieves a page of all user interactions, newest first.
+func (r *userInteractionRepository) GetAllUserInteractions(ctx context.Context, page models.PageRequest) (*models.Page[models.UserInteraction], error) {
+	userInteractions, err := selectPage(ctx, r.db, "SELECT id, user_id, book_id, interaction_type, timestamp, device, locale, location, client_time_zone, rating FROM user_interactions", nil, userInteractionKeyset, page)
+	if err != nil {
+		return nil, fmt.Errorf("error getting all user interactions: %w", err)
 	}
-	return &userInteraction, nil
+	return userInteractions, nil
 }
 
 // GetUserInteractionsByUserID retrieves user interactions for a specific user.
 func (r *userInteractionRepository) GetUserInteractionsByUserID(ctx context.Context, userID string) ([]models.UserInteraction, error) {
 	var userInteractions []models.UserInteraction
-	err := r.db.SelectContext(ctx, &userInteractions, "SELECT id, user_id, book_id, interaction_type, timestamp FROM user_interactions WHERE user_id=$1", userID)
+	err := r.db.SelectContext(ctx, &userInteractions, "SELECT id, user_id, book_id, interaction_type, timestamp, device, locale, location, client_time_zone, rating FROM user_interactions WHERE user_id=$1", userID)
 	if err != nil {
 		return nil, fmt.Errorf("error getting user interactions by user ID: %w", err)
 	}
 	return userInteractions, nil
 }
 
+// GetUserInteractionsByUserIDs retrieves user interactions for any of the given users.
+func (r *userInteractionRepository) GetUserInteractionsByUserIDs(ctx context.Context, userIDs []string) ([]models.UserInteraction, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In("SELECT id, user_id, book_id, interaction_type, timestamp, device, locale, location, client_time_zone, rating FROM user_interactions WHERE user_id IN (?)", userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error building user interactions by user IDs query: %w", err)
+	}
+	var userInteractions []models.UserInteraction
+	err = r.db.SelectContext(ctx, &userInteractions, r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting user interactions by user IDs: %w", err)
+	}
+	return userInteractions, nil
+}
+
+// GetUserInteractionsByBookIDs retrieves user interactions for any of the given books.
+func (r *userInteractionRepository) GetUserInteractionsByBookIDs(ctx context.Context, bookIDs []string) ([]models.UserInteraction, error) {
+	if len(bookIDs) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In("SELECT id, user_id, book_id, interaction_type, timestamp, device, locale, location, client_time_zone, rating FROM user_interactions WHERE book_id IN (?)", bookIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error building user interactions by book IDs query: %w", err)
+	}
+	var userInteractions []models.UserInteraction
+	err = r.db.SelectContext(ctx, &userInteractions, r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting user interactions by book IDs: %w", err)
+	}
+	return userInteractions, nil
+}
+
+// GetRecentInteractionsByBookIDs retrieves the most recent perBook user
+// interactions with each of the given books.
+func (r *userInteractionRepository) GetRecentInteractionsByBookIDs(ctx context.Context, bookIDs []string, perBook int) ([]models.UserInteraction, error) {
+	if len(bookIDs) == 0 {
+		return nil, nil
+	}
+	query, args, err := sqlx.In(`SELECT id, user_id, book_id, interaction_type, timestamp, device, locale, location, client_time_zone, rating FROM (
+		SELECT *, ROW_NUMBER() OVER (PARTITION BY book_id ORDER BY timestamp DESC, id) AS recency FROM user_interactions WHERE book_id IN (?)
+	) ranked WHERE recency <= ?`, bookIDs, perBook)
+	if err != nil {
+		return nil, fmt.Errorf("error building recent user interactions by book IDs query: %w", err)
+	}
+	var userInteractions []models.UserInteraction
+	err = r.db.SelectContext(ctx, &userInteractions, r.db.Rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting recent user interactions by book IDs: %w", err)
+	}
+	return userInteractions, nil
+}
+
+// GetPopularBooks retrieves the works with the most interactions with any of
+// their editions since the given time. Each work is represented by its most
+// interacted-with edition.
+func (r *userInteractionRepository) GetPopularBooks(ctx context.Context, since time.Time, limit int) ([]models.BookPopularity, error) {
+	var popular []models.BookPopularity
+	err := r.db.SelectContext(ctx, &popular, `SELECT (array_agg(book_id ORDER BY edition_count DESC, book_id))[1] AS book_id, SUM(edition_count) AS interaction_count
+		FROM (
+			SELECT ui.book_id, b.work_id, COUNT(*) AS edition_count
+			FROM user_interactions ui JOIN books b ON b.id = ui.book_id
+			WHERE ui.timestamp >= $1
+			GROUP BY ui.book_id, b.work_id
+		) editions
+		GROUP BY work_id ORDER BY interaction_count DESC, book_id LIMIT $2`, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error getting popular books: %w", err)
+	}
+	return popular, nil
+}
+
 // CreateUserInteraction creates a new user interaction.
 func (r *userInteractionRepository) CreateUserInteraction(ctx context.Context, userInteraction *models.UserInteraction) error {
-	query := `INSERT INTO user_interactions (id, user_id, book_id, interaction_type, timestamp) VALUES (:id, :user_id, :book_id, :interaction_type, :timestamp)`
+	query := `INSERT INTO user_interactions (id, user_id, book_id, interaction_type, timestamp, device, locale, location, client_time_zone, rating) VALUES (:id, :user_id, :book_id, :interaction_type, :timestamp, :device, :locale, :location, :client_time_zone, :rating)`
 	_, err := r.db.NamedExecContext(ctx, query, userInteraction)
 	if err != nil {
 		return fmt.Errorf("error creating user interaction: %w", err)
@@ -70,7 +155,7 @@ func (r *userInteractionRepository) CreateUserInteraction(ctx context.Context, u
 
 // UpdateUserInteraction updates an existing user interaction.
 func (r *userInteractionRepository) UpdateUserInteraction(ctx context.Context, userInteraction *models.UserInteraction) error {
-	query := `UPDATE user_interactions SET user_id=:user_id, book_id=:book_id, interaction_type=:interaction_type, timestamp=:timestamp WHERE id=:id`
+	query := `UPDATE user_interactions SET user_id=:user_id, book_id=:book_id, interaction_type=:interaction_type, timestamp=:timestamp, device=:device, locale=:locale, location=:location, client_time_zone=:client_time_zone, rating=:rating WHERE id=:id`
 	_, err := r.db.NamedExecContext(ctx, query, userInteraction)
 	if err != nil {
 		return fmt.Errorf("error updating user interaction: %w", err)
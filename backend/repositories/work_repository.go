@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"book-recommendation-system/backend/models"
+	"github.com/jmoiron/sqlx"
+)
+
+// WorkRepository defines the interface for work data operations.
+type WorkRepository interface {
+	GetWorkByID(ctx context.Context, id string) (*models.Work, error)
+	MoveEditions(ctx context.Context, workID string, bookIDs []string) error
+	SplitEdition(ctx context.Context, bookID string, work *models.Work) error
+}
+
+// workRepository implements WorkRepository using sqlx.
+type workRepository struct {
+	db *sqlx.DB
+}
+
+// NewWorkRepository creates a new WorkRepository.
+func NewWorkRepository(db *sqlx.DB) WorkRepository {
+	return &workRepository{db: db}
+}
+
+// GetWorkByID retrieves a work by its ID, without its editions, returning nil
+// if there is none.
+func (r *workRepository) GetWorkByID(ctx context.Context, id string) (*models.Work, error) {
+	var work models.Work
+	err := r.db.GetContext(ctx, &work, "SELECT id, title, author FROM works WHERE id=$1", id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting work by ID: %w", err)
+	}
+	return &work, nil
+}
+
+// MoveEditions makes the given books editions of a work, deleting the works
+// they leave without editions.
+func (r *workRepository) MoveEditions(ctx context.Context, workID string, bookIDs []string) error {
+	if len(bookIDs) == 0 {
+		return nil
+	}
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error moving editions: %w", err)
+	}
+	defer tx.Rollback()
+
+	query, args, err := sqlx.In(`UPDATE books b SET work_id = ? FROM books old
+		WHERE b.id = old.id AND b.id IN (?) AND b.work_id <> ?
+		RETURNING old.work_id`, workID, bookIDs, workID)
+	if err != nil {
+		return fmt.Errorf("error building move editions query: %w", err)
+	}
+	var oldWorkIDs []string
+	if err := tx.SelectContext(ctx, &oldWorkIDs, tx.Rebind(query), args...); err != nil {
+		return fmt.Errorf("error moving editions: %w", err)
+	}
+	if err := deleteEmptyWorks(ctx, tx, oldWorkIDs); err != nil {
+		return fmt.Errorf("error moving editions: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error moving editions: %w", err)
+	}
+	return nil
+}
+
+// SplitEdition creates a new work and moves a book to it as its only edition,
+// deleting the book's old work if it is left without editions.
+func (r *workRepository) SplitEdition(ctx context.Context, bookID string, work *models.Work) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error splitting edition: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.NamedExecContext(ctx, "INSERT INTO works (id, title, author) VALUES (:id, :title, :author)", work)
+	if err != nil {
+		return fmt.Errorf("error splitting edition: %w", err)
+	}
+	var oldWorkIDs []string
+	err = tx.SelectContext(ctx, &oldWorkIDs, `UPDATE books b SET work_id = $1 FROM books old
+		WHERE b.id = old.id AND b.id = $2
+		RETURNING old.work_id`, work.ID, bookID)
+	if err != nil {
+		return fmt.Errorf("error splitting edition: %w", err)
+	}
+	if err := deleteEmptyWorks(ctx, tx, oldWorkIDs); err != nil {
+		return fmt.Errorf("error splitting edition: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error splitting edition: %w", err)
+	}
+	return nil
+}
+
+// deleteEmptyWorks deletes those of the given works that have no editions
+// left, within tx.
+func deleteEmptyWorks(ctx context.Context, tx *sqlx.Tx, workIDs []string) error {
+	if len(workIDs) == 0 {
+		return nil
+	}
+	query, args, err := sqlx.In("DELETE FROM works w WHERE w.id IN (?) AND NOT EXISTS (SELECT 1 FROM books b WHERE b.work_id = w.id)", workIDs)
+	if err != nil {
+		return fmt.Errorf("error building delete empty works query: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, tx.Rebind(query), args...); err != nil {
+		return fmt.Errorf("error deleting empty works: %w", err)
+	}
+	return nil
+}
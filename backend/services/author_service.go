@@ -2,6 +2,8 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 
 	"book-recommendation-system/backend/models"
@@ -11,12 +13,23 @@ import (
 // AuthorService defines the interface for author-related business logic.
 type AuthorService interface {
 	GetAuthorByID(ctx context.Context, id string) (*models.Author, error)
-	GetAllAuthors(ctx context.Context) ([]models.Author, error)
+	GetAllAuthors(ctx context.Context, page models.PageRequest) (*models.Page[models.Author], error)
 	CreateAuthor(ctx context.Context, author *models.Author) error
 	UpdateAuthor(ctx context.Context, author *models.Author) error
 	DeleteAuthor(ctx context.Context, id string) error
+	GetAuthorBibliography(ctx context.Context, id string) (*models.AuthorBibliography, error)
+	GetRelatedAuthors(ctx context.Context, id string, limit int) ([]models.RelatedAuthor, error)
 }
 
+const (
+	// authorTopGenres is how many of an author's genres their stats list.
+	authorTopGenres = 5
+	// defaultRelatedAuthors and maxRelatedAuthors bound how many related
+	// authors are returned.
+	defaultRelatedAuthors = 10
+	maxRelatedAuthors     = 50
+)
+
 // authorService implements AuthorService.
 type authorService struct {
 	repo repositories.AuthorRepository
@@ -36,9 +49,9 @@ func (s *authorService) GetAuthorByID(ctx context.Context, id string) (*models.A
 	return author, nil
 }
 
-// GetAllAuthors retrieves all authors using the repository.
-func (s *authorService) GetAllAuthors(ctx context.Context) ([]models.Author, error) {
-	authors, err := s.repo.GetAllAuthors(ctx)
+// GetAllAuthors retrieves a page of all authors using the repository.
+func (s *authorService) GetAllAuthors(ctx context.Context, page models.PageRequest) (*models.Page[models.Author], error) {
+	authors, err := s.repo.GetAllAuthors(ctx, page)
 	if err != nil {
 		return nil, fmt.Errorf("service: failed to get all authors: %w", err)
 	}
@@ -74,3 +87,69 @@ func (s *authorService) DeleteAuthor(ctx context.Context, id string) error {
 	}
 	return nil
 }
+
+// findAuthor retrieves an author by ID, returning nil if there is none.
+func (s *authorService) findAuthor(ctx context.Context, id string) (*models.Author, error) {
+	author, err := s.repo.GetAuthorByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get author by ID: %w", err)
+	}
+	return author, nil
+}
+
+// readerInteractionTypes returns the interaction types that make a user a
+// reader of a book.
+func readerInteractionTypes() []string {
+	types := make([]string, 0, len(consumedInteractionTypes))
+	for t := range consumedInteractionTypes {
+		types = append(types, t)
+	}
+	return types
+}
+
+// GetAuthorBibliography retrieves an author with every book they contributed
+// to and statistics over the books they wrote: how many users read them, how
+// they were rated and the genres they fall in. It returns nil if the author
+// does not exist.
+func (s *authorService) GetAuthorBibliography(ctx context.Context, id string) (*models.AuthorBibliography, error) {
+	author, err := s.findAuthor(ctx, id)
+	if author == nil || err != nil {
+		return nil, err
+	}
+	books, err := s.repo.GetAuthorBooks(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get author books: %w", err)
+	}
+	stats, err := s.repo.GetAuthorStats(ctx, id, readerInteractionTypes(), authorTopGenres)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get author stats: %w", err)
+	}
+	return &models.AuthorBibliography{Author: *author, Stats: *stats, Books: books}, nil
+}
+
+// GetRelatedAuthors retrieves the authors most read by readers of an
+// author's books, relative to how widely read they are, so that merely
+// popular authors do not crowd out closer matches. It returns nil if the
+// author does not exist.
+func (s *authorService) GetRelatedAuthors(ctx context.Context, id string, limit int) ([]models.RelatedAuthor, error) {
+	if limit < 0 {
+		return nil, fmt.Errorf("service: limit must not be negative, got %d: %w", limit, ErrInvalidInput)
+	}
+	if limit == 0 {
+		limit = defaultRelatedAuthors
+	}
+	limit = min(limit, maxRelatedAuthors)
+
+	author, err := s.findAuthor(ctx, id)
+	if author == nil || err != nil {
+		return nil, err
+	}
+	related, err := s.repo.GetRelatedAuthors(ctx, id, readerInteractionTypes(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get related authors: %w", err)
+	}
+	return related, nil
+}
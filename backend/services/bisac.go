@@ -0,0 +1,124 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+)
+
+// bisacGenres maps BISAC subject code prefixes to genre names. The longest
+// matching prefix wins, so specific codes can refine their section's genre.
+var bisacGenres = map[string]string{
+	"ANT":    "Antiques & Collectibles",
+	"ARC":    "Architecture",
+	"ART":    "Art",
+	"BIO":    "Biography",
+	"BUS":    "Business",
+	"CGN":    "Comics & Graphic Novels",
+	"CKB":    "Cooking",
+	"COM":    "Computers",
+	"CRA":    "Crafts & Hobbies",
+	"DRA":    "Drama",
+	"EDU":    "Education",
+	"FAM":    "Family & Relationships",
+	"FIC":    "Fiction",
+	"FIC002": "Action & Adventure",
+	"FIC004": "Classics",
+	"FIC009": "Fantasy",
+	"FIC010": "Fairy Tales & Folklore",
+	"FIC012": "Ghost Stories",
+	"FIC014": "Historical Fiction",
+	"FIC015": "Horror",
+	"FIC016": "Humour",
+	"FIC019": "Literary Fiction",
+	"FIC022": "Mystery",
+	"FIC027": "Romance",
+	"FIC028": "Science Fiction",
+	"FIC030": "Thriller",
+	"FIC031": "Thriller",
+	"FIC036": "Thriller",
+	"FIC037": "Political Fiction",
+	"FIC042": "Christian Fiction",
+	"FIC044": "Contemporary Women's Fiction",
+	"FIC045": "Family Saga",
+	"FIC050": "Crime",
+	"FIC061": "Magical Realism",
+	"FOR":    "Foreign Language Study",
+	"GAM":    "Games & Activities",
+	"GAR":    "Gardening",
+	"HEA":    "Health & Fitness",
+	"HIS":    "History",
+	"HOM":    "House & Home",
+	"HUM":    "Humour",
+	"JNF":    "Children's Nonfiction",
+	"JUV":    "Children's Fiction",
+	"LAN":    "Language Arts",
+	"LAW":    "Law",
+	"LCO":    "Literary Collections",
+	"LIT":    "Literary Criticism",
+	"MAT":    "Mathematics",
+	"MED":    "Medical",
+	"MUS":    "Music",
+	"NAT":    "Nature",
+	"PER":    "Performing Arts",
+	"PET":    "Pets",
+	"PHI":    "Philosophy",
+	"PHO":    "Photography",
+	"POE":    "Poetry",
+	"POL":    "Political Science",
+	"PSY":    "Psychology",
+	"REF":    "Reference",
+	"REL":    "Religion",
+	"SCI":    "Science",
+	"SEL":    "Self-Help",
+	"SOC":    "Social Science",
+	"SPO":    "Sports & Recreation",
+	"STU":    "Study Aids",
+	"TEC":    "Technology & Engineering",
+	"TRA":    "Transportation",
+	"TRU":    "True Crime",
+	"TRV":    "Travel",
+	"YAF":    "Young Adult Fiction",
+	"YAN":    "Young Adult Nonfiction",
+}
+
+// bisacGenre returns the genre for a BISAC subject, given by code and
+// optionally by heading text such as "FICTION / Fantasy / Epic". A mapped
+// subdivision code is used first, then the heading's second level, then the
+// code's section and finally the heading's first level. It returns "" when
+// none of them gives a genre.
+func bisacGenre(code, heading string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	for n := min(len(code), 6); n > 3; n-- {
+		if genre, ok := bisacGenres[code[:n]]; ok {
+			return genre
+		}
+	}
+
+	levels := strings.Split(heading, "/")
+	for i := range levels {
+		levels[i] = strings.TrimSpace(levels[i])
+	}
+	if len(levels) >= 2 && levels[1] != "" && levels[1] != "General" {
+		return levels[1]
+	}
+	if len(code) >= 3 {
+		if genre, ok := bisacGenres[code[:3]]; ok {
+			return genre
+		}
+	}
+	if levels[0] != "" {
+		return titleCase(levels[0])
+	}
+	return ""
+}
+
+// titleCase turns an upper-case BISAC section name such as "TRUE CRIME" into "True Crime".
+func titleCase(s string) string {
+	words := strings.Fields(strings.ToLower(s))
+	for i, w := range words {
+		r := []rune(w)
+		r[0] = unicode.ToUpper(r[0])
+		words[i] = string(r)
+	}
+	return strings.Join(words, " ")
+}
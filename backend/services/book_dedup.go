@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"book-recommendation-system/backend/isbn"
+	"book-recommendation-system/backend/models"
+	"book-recommendation-system/backend/repositories"
+)
+
+const (
+	// duplicateTitleThreshold is the minimum similarity of normalised titles
+	// for two books to be reported as duplicates.
+	duplicateTitleThreshold = 0.9
+	// duplicateAuthorThreshold is the minimum similarity of normalised first
+	// authors, when both books have one.
+	duplicateAuthorThreshold = 0.85
+	// maxDuplicateBlockSize skips blocks of books sharing a key too common to
+	// tell anything, bounding the pairwise comparisons.
+	maxDuplicateBlockSize = 1000
+	// defaultDuplicateLimit and maxDuplicateLimit bound how many candidates
+	// are reported.
+	defaultDuplicateLimit = 100
+	maxDuplicateLimit     = 1000
+)
+
+// leadingArticles are dropped from the start of titles before comparing them.
+var leadingArticles = map[string]bool{"the": true, "a": true, "an": true}
+
+// DuplicateService defines the interface for finding and merging duplicate books.
+type DuplicateService interface {
+	FindDuplicates(ctx context.Context, limit int) ([]models.DuplicateCandidate, error)
+	MergeBooks(ctx context.Context, req models.BookMergeRequest) (*models.BookMergeResult, error)
+}
+
+// duplicateService implements DuplicateService.
+type duplicateService struct {
+	books    repositories.BookRepository
+	holdings repositories.HoldingRepository
+	assigner *holdAssigner
+}
+
+// NewDuplicateService creates a new DuplicateService.
+func NewDuplicateService(books repositories.BookRepository, holdings repositories.HoldingRepository, libraries repositories.LibraryRepository, holds repositories.HoldRepository) DuplicateService {
+	return &duplicateService{
+		books:    books,
+		holdings: holdings,
+		assigner: &holdAssigner{holds: holds, holdings: holdings, libraries: libraries},
+	}
+}
+
+// dedupBook is a book with the keys it is compared by.
+type dedupBook struct {
+	book   models.Book
+	isbn   string // normalised ISBN-13, or "" if the book has no valid ISBN
+	title  string // normalised title
+	author string // normalised first author
+}
+
+// FindDuplicates scans the catalogue for pairs of books that are likely the
+// same record: books whose ISBNs are equal once normalised, and books with
+// near-identical titles and authors where at most one has a valid ISBN. Two
+// books with different valid ISBNs are distinct editions, which are grouped
+// into works instead. Candidates are ordered most similar first.
+func (s *duplicateService) FindDuplicates(ctx context.Context, limit int) ([]models.DuplicateCandidate, error) {
+	if limit < 0 {
+		return nil, fmt.Errorf("service: limit must not be negative, got %d: %w", limit, ErrInvalidInput)
+	}
+	if limit == 0 {
+		limit = defaultDuplicateLimit
+	}
+	limit = min(limit, maxDuplicateLimit)
+
+	var books []dedupBook
+	page := models.PageRequest{Limit: models.MaxPageLimit}
+	for {
+		result, err := s.books.GetAllBooks(ctx, page)
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to list books: %w", err)
+		}
+		for _, b := range result.Items {
+			books = append(books, newDedupBook(b))
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		page.Cursor = result.NextCursor
+	}
+
+	type pair struct{ a, b int }
+	seen := make(map[pair]bool)
+	candidates := []models.DuplicateCandidate{}
+	compare := func(block []int) {
+		if len(block) > maxDuplicateBlockSize {
+			return
+		}
+		for x, i := range block {
+			for _, j := range block[x+1:] {
+				p := pair{min(i, j), max(i, j)}
+				if seen[p] {
+					continue
+				}
+				seen[p] = true
+				if reason, similarity, ok := duplicateMatch(books[p.a], books[p.b]); ok {
+					survivor, duplicate := books[p.a].book, books[p.b].book
+					if betterSurvivor(duplicate, survivor) {
+						survivor, duplicate = duplicate, survivor
+					}
+					candidates = append(candidates, models.DuplicateCandidate{Book: survivor, Duplicate: duplicate, Reason: reason, Similarity: similarity})
+				}
+			}
+		}
+	}
+
+	// Only books sharing an ISBN, a first title word or an author's surname
+	// are compared.
+	blocks := make(map[string][]int)
+	for i, b := range books {
+		if b.isbn != "" {
+			blocks["isbn:"+b.isbn] = append(blocks["isbn:"+b.isbn], i)
+		}
+		if words := strings.Fields(b.title); len(words) > 0 {
+			blocks["title:"+words[0]] = append(blocks["title:"+words[0]], i)
+		}
+		if words := strings.Fields(b.author); len(words) > 0 {
+			surname := words[len(words)-1]
+			blocks["author:"+surname] = append(blocks["author:"+surname], i)
+		}
+	}
+	keys := make([]string, 0, len(blocks))
+	for key := range blocks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		compare(blocks[key])
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Similarity != b.Similarity {
+			return a.Similarity > b.Similarity
+		}
+		if a.Book.ID != b.Book.ID {
+			return a.Book.ID < b.Book.ID
+		}
+		return a.Duplicate.ID < b.Duplicate.ID
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates, nil
+}
+
+func newDedupBook(b models.Book) dedupBook {
+	d := dedupBook{book: b, title: normalizeTitle(b.Title)}
+	d.isbn, _ = isbn.Normalize(b.ISBN)
+	if names := splitNames(b.Author); len(names) > 0 {
+		d.author = normalizeName(names[0])
+	}
+	return d
+}
+
+// normalizeTitle normalises a title like a name, also dropping a leading
+// article, so that "The Hobbit", "Hobbit, The" and "hobbit" compare equal.
+func normalizeTitle(title string) string {
+	words := strings.Fields(normalizeName(title))
+	if len(words) > 1 && leadingArticles[words[0]] {
+		words = words[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// duplicateMatch reports whether two books look like the same record, why,
+// and how similar they are.
+func duplicateMatch(a, b dedupBook) (string, float64, bool) {
+	if a.isbn != "" && a.isbn == b.isbn {
+		return models.DuplicateReasonISBN, 1, true
+	}
+	if a.isbn != "" && b.isbn != "" {
+		return "", 0, false
+	}
+	if a.title == "" || b.title == "" || titleNumbers(a.title) != titleNumbers(b.title) {
+		return "", 0, false
+	}
+	if a.book.SeriesPosition > 0 && b.book.SeriesPosition > 0 && a.book.SeriesPosition != b.book.SeriesPosition {
+		return "", 0, false
+	}
+
+	titleSim := nameSimilarity(a.title, b.title)
+	if titleSim < duplicateTitleThreshold {
+		return "", 0, false
+	}
+	if a.author == "" || b.author == "" {
+		return models.DuplicateReasonTitleAuthor, titleSim, true
+	}
+	authorSim := nameSimilarity(a.author, b.author)
+	if authorSim < duplicateAuthorThreshold {
+		return "", 0, false
+	}
+	return models.DuplicateReasonTitleAuthor, (2*titleSim + authorSim) / 3, true
+}
+
+// titleNumbers returns the numbers in a title, which must agree for two titles
+// to be duplicates, so that "Volume 1" and "Volume 2" are kept apart.
+func titleNumbers(title string) string {
+	return strings.Join(strings.FieldsFunc(title, func(r rune) bool { return !unicode.IsDigit(r) }), " ")
+}
+
+// betterSurvivor reports whether book a should survive a merge with b: it has
+// a valid ISBN, then more of its details filled in, then the lower ID.
+func betterSurvivor(a, b models.Book) bool {
+	aValid, bValid := isbn.Validate(a.ISBN) == nil, isbn.Validate(b.ISBN) == nil
+	if aValid != bValid {
+		return aValid
+	}
+	if ca, cb := bookCompleteness(a), bookCompleteness(b); ca != cb {
+		return ca > cb
+	}
+	return a.ID < b.ID
+}
+
+// bookCompleteness counts the optional details a book has filled in.
+func bookCompleteness(b models.Book) int {
+	n := 0
+	for _, set := range []bool{b.Author != "", b.Description != "", b.CoverImageURL != "", b.Genre != "", b.PublicationYear != 0, b.Series != "", b.Language != "", b.Publisher != "", b.Format != "", b.PageCount != 0} {
+		if set {
+			n++
+		}
+	}
+	return n
+}
+
+// MergeBooks merges duplicate books into a surviving one, moving their
+// interactions, recommendations, holdings, loans and holds to it, in one
+// transaction. The merged queue of holds is then offered the survivor's
+// available copies, which may include copies released from cancelled holds.
+func (s *duplicateService) MergeBooks(ctx context.Context, req models.BookMergeRequest) (*models.BookMergeResult, error) {
+	survivorID := strings.TrimSpace(req.SurvivorID)
+	if survivorID == "" {
+		return nil, fmt.Errorf("service: survivor_id is required: %w", ErrInvalidInput)
+	}
+	seen := map[string]bool{survivorID: true}
+	duplicateIDs := make([]string, 0, len(req.DuplicateIDs))
+	for _, id := range req.DuplicateIDs {
+		id = strings.TrimSpace(id)
+		if id == survivorID {
+			return nil, fmt.Errorf("service: book %s cannot be merged into itself: %w", id, ErrInvalidInput)
+		}
+		if id != "" && !seen[id] {
+			seen[id] = true
+			duplicateIDs = append(duplicateIDs, id)
+		}
+	}
+	if len(duplicateIDs) == 0 {
+		return nil, fmt.Errorf("service: at least one duplicate ID is required: %w", ErrInvalidInput)
+	}
+
+	books, err := s.books.GetBooksByIDs(ctx, append([]string{survivorID}, duplicateIDs...))
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get books to merge: %w", err)
+	}
+	found := make(map[string]bool, len(books))
+	for _, b := range books {
+		found[b.ID] = true
+	}
+	for id := range seen {
+		if !found[id] {
+			return nil, fmt.Errorf("service: book %s does not exist: %w", id, ErrInvalidInput)
+		}
+	}
+
+	result, err := s.books.MergeBooks(ctx, survivorID, duplicateIDs)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to merge books: %w", err)
+	}
+	copies, err := s.holdings.GetBookHoldings(ctx, survivorID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get book holdings: %w", err)
+	}
+	for _, c := range copies {
+		if c.Status != models.HoldingStatusAvailable {
+			continue
+		}
+		if _, err := s.assigner.assignCopy(ctx, c.ID); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
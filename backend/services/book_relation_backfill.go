@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"book-recommendation-system/backend/models"
+	"book-recommendation-system/backend/repositories"
+)
+
+// fuzzyNameThreshold is the minimum nameSimilarity for a free-text name to
+// be linked to an existing author or genre with a different spelling and the
+// same last word.
+const fuzzyNameThreshold = 0.85
+
+// BackfillReport summarises a run of BookRelationBackfiller.
+type BackfillReport struct {
+	BooksLinked    int          `json:"books_linked"`
+	BooksSkipped   int          `json:"books_skipped"` // already linked
+	BooksEmpty     int          `json:"books_empty"`   // no author or genre text
+	AuthorsCreated int          `json:"authors_created"`
+	GenresCreated  int          `json:"genres_created"`
+	FuzzyMatches   []FuzzyMatch `json:"fuzzy_matches"`
+}
+
+// FuzzyMatch records a free-text name linked to a differently spelled record,
+// for review.
+type FuzzyMatch struct {
+	BookID     string  `json:"book_id"`
+	Text       string  `json:"text"`
+	MatchedID  string  `json:"matched_id"`
+	Matched    string  `json:"matched"`
+	Similarity float64 `json:"similarity"`
+}
+
+// BookRelationBackfiller links books to authors and genres records from their
+// free-text author and genre columns.
+type BookRelationBackfiller struct {
+	books   repositories.BookRepository
+	authors repositories.AuthorRepository
+	genres  repositories.GenreRepository
+}
+
+// NewBookRelationBackfiller creates a new BookRelationBackfiller.
+func NewBookRelationBackfiller(books repositories.BookRepository, authors repositories.AuthorRepository, genres repositories.GenreRepository) *BookRelationBackfiller {
+	return &BookRelationBackfiller{books: books, authors: authors, genres: genres}
+}
+
+// nameIndex finds records by normalised name, exactly or fuzzily.
+type nameIndex struct {
+	ids   map[string]string // normalised name -> ID
+	names map[string]string // ID -> display name
+}
+
+func newNameIndex() *nameIndex {
+	return &nameIndex{ids: make(map[string]string), names: make(map[string]string)}
+}
+
+func (idx *nameIndex) add(id, name string) {
+	idx.names[id] = name
+	idx.addAlias(id, name)
+}
+
+// addAlias indexes another name for a record already added.
+func (idx *nameIndex) addAlias(id, alias string) {
+	if key := normalizeName(alias); key != "" {
+		if _, exists := idx.ids[key]; !exists {
+			idx.ids[key] = id
+		}
+	}
+}
+
+// find returns the ID of the record best matching name and how similar it
+// is, or "" when nothing reaches fuzzyNameThreshold. Spellings are only
+// compared fuzzily when their last words, usually the surname, are equal, so
+// two people whose long names differ by a letter are not merged.
+func (idx *nameIndex) find(name string) (string, float64) {
+	key := normalizeName(name)
+	if id, ok := idx.ids[key]; ok {
+		return id, 1
+	}
+	bestID, best := "", 0.0
+	for candidate, id := range idx.ids {
+		if lastWord(candidate) != lastWord(key) {
+			continue
+		}
+		if sim := nameSimilarity(key, candidate); sim > best || (sim == best && id < bestID) {
+			bestID, best = id, sim
+		}
+	}
+	if best < fuzzyNameThreshold {
+		return "", 0
+	}
+	return bestID, best
+}
+
+// Run links every book that has no author or genre links yet. Names without
+// a matching record get a new author or genre. Books with no author or genre
+// text are left alone and counted as empty. Running it again only processes
+// books that are still unlinked.
+func (b *BookRelationBackfiller) Run(ctx context.Context) (*BackfillReport, error) {
+	authors, err := loadAuthorIndex(ctx, b.authors)
+	if err != nil {
+		return nil, err
+	}
+	genres, err := loadGenreIndex(ctx, b.genres)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &BackfillReport{FuzzyMatches: []FuzzyMatch{}}
+	page := models.PageRequest{Limit: models.MaxPageLimit}
+	for {
+		books, err := b.books.GetAllBooks(ctx, page)
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to list books: %w", err)
+		}
+		if err := b.linkBooks(ctx, books.Items, authors, genres, report); err != nil {
+			return nil, err
+		}
+		if books.NextCursor == "" {
+			return report, nil
+		}
+		page.Cursor = books.NextCursor
+	}
+}
+
+func (b *BookRelationBackfiller) linkBooks(ctx context.Context, books []models.Book, authors, genres *nameIndex, report *BackfillReport) error {
+	ids := make([]string, len(books))
+	for i, book := range books {
+		ids[i] = book.ID
+	}
+	linkedAuthors, err := b.books.GetBookAuthors(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("service: failed to get book authors: %w", err)
+	}
+	linkedGenres, err := b.books.GetBookGenres(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("service: failed to get book genres: %w", err)
+	}
+	linked := make(map[string]bool)
+	for _, a := range linkedAuthors {
+		linked[a.BookID] = true
+	}
+	for _, g := range linkedGenres {
+		linked[g.BookID] = true
+	}
+
+	for _, book := range books {
+		if linked[book.ID] {
+			report.BooksSkipped++
+			continue
+		}
+		names, genreNames := splitNames(book.Author), splitGenres(book.Genre)
+		if len(names) == 0 && len(genreNames) == 0 {
+			report.BooksEmpty++
+			continue
+		}
+
+		bookAuthors := []models.BookAuthor{}
+		for _, name := range names {
+			id, err := b.resolve(ctx, book.ID, name, authors, report, b.createAuthor)
+			if err != nil {
+				return err
+			}
+			bookAuthors = append(bookAuthors, models.BookAuthor{Author: models.Author{ID: id}, Role: models.AuthorRoleAuthor})
+		}
+		bookGenres := []models.BookGenre{}
+		for _, name := range genreNames {
+			id, err := b.resolve(ctx, book.ID, name, genres, report, b.createGenre)
+			if err != nil {
+				return err
+			}
+			bookGenres = append(bookGenres, models.BookGenre{Genre: models.Genre{ID: id}})
+		}
+
+		if err := b.books.SetBookRelations(ctx, book.ID, bookAuthors, bookGenres); err != nil {
+			return fmt.Errorf("service: failed to link book %s: %w", book.ID, err)
+		}
+		report.BooksLinked++
+	}
+	return nil
+}
+
+// resolve returns the ID of the record matching name, creating one with
+// create when there is no match.
+func (b *BookRelationBackfiller) resolve(ctx context.Context, bookID, name string, idx *nameIndex, report *BackfillReport, create func(context.Context, string, *BackfillReport) (string, error)) (string, error) {
+	id, similarity := idx.find(name)
+	if id == "" {
+		var err error
+		if id, err = create(ctx, name, report); err != nil {
+			return "", err
+		}
+		idx.add(id, name)
+		return id, nil
+	}
+	if similarity < 1 {
+		report.FuzzyMatches = append(report.FuzzyMatches, FuzzyMatch{BookID: bookID, Text: name, MatchedID: id, Matched: idx.names[id], Similarity: similarity})
+	}
+	return id, nil
+}
+
+func (b *BookRelationBackfiller) createAuthor(ctx context.Context, name string, report *BackfillReport) (string, error) {
+	author := &models.Author{ID: newID(), Name: name}
+	if err := b.authors.CreateAuthor(ctx, author); err != nil {
+		return "", fmt.Errorf("service: failed to create author %q: %w", name, err)
+	}
+	report.AuthorsCreated++
+	return author.ID, nil
+}
+
+func (b *BookRelationBackfiller) createGenre(ctx context.Context, name string, report *BackfillReport) (string, error) {
+	genre := &models.Genre{ID: newID(), Name: name}
+	if err := b.genres.CreateGenre(ctx, genre); err != nil {
+		return "", fmt.Errorf("service: failed to create genre %q: %w", name, err)
+	}
+	report.GenresCreated++
+	return genre.ID, nil
+}
+
+// loadAuthorIndex indexes every author by name.
+func loadAuthorIndex(ctx context.Context, repo repositories.AuthorRepository) (*nameIndex, error) {
+	idx := newNameIndex()
+	page := models.PageRequest{Limit: models.MaxPageLimit}
+	for {
+		authors, err := repo.GetAllAuthors(ctx, page)
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to list authors: %w", err)
+		}
+		for _, a := range authors.Items {
+			idx.add(a.ID, a.Name)
+		}
+		if authors.NextCursor == "" {
+			return idx, nil
+		}
+		page.Cursor = authors.NextCursor
+	}
+}
+
+// loadGenreIndex indexes every genre by name and alias. Names are indexed
+// first, so an alias never shadows another genre's name.
+func loadGenreIndex(ctx context.Context, repo repositories.GenreRepository) (*nameIndex, error) {
+	genres, err := listAllGenres(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	idx := newNameIndex()
+	for _, g := range genres {
+		idx.add(g.ID, g.Name)
+	}
+	for _, g := range genres {
+		for _, alias := range g.Aliases {
+			idx.addAlias(g.ID, alias)
+		}
+	}
+	return idx, nil
+}
@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	"book-recommendation-system/backend/isbn"
 	"book-recommendation-system/backend/models"
 	"book-recommendation-system/backend/repositories"
 )
@@ -11,7 +13,10 @@ import (
 // BookService defines the interface for book-related business logic.
 type BookService interface {
 	GetBookByID(ctx context.Context, id string) (*models.Book, error)
-	GetAllBooks(ctx context.Context) ([]models.Book, error)
+	GetBookByISBN(ctx context.Context, isbn string) (*models.Book, error)
+	GetAllBooks(ctx context.Context, page models.PageRequest) (*models.Page[models.Book], error)
+	SearchBooks(ctx context.Context, q string, limit int) ([]models.BookSearchResult, error)
+	QueryBooks(ctx context.Context, filter models.BookFilter, page models.PageRequest) (*models.BookQueryResult, error)
 	CreateBook(ctx context.Context, book *models.Book) error
 	UpdateBook(ctx context.Context, book *models.Book) error
 	DeleteBook(ctx context.Context, id string) error
@@ -33,24 +38,304 @@ func (s *bookService) GetBookByID(ctx context.Context, id string) (*models.Book,
 	if err != nil {
 		return nil, fmt.Errorf("service: failed to get book by ID: %w", err)
 	}
-	return book, nil
+	books := []models.Book{*book}
+	if err := s.attachRelations(ctx, books); err != nil {
+		return nil, err
+	}
+	return &books[0], nil
+}
+
+// GetBookByISBN retrieves a book by its ISBN-10 or ISBN-13, with or without hyphens.
+func (s *bookService) GetBookByISBN(ctx context.Context, rawISBN string) (*models.Book, error) {
+	normalized, err := isbn.Normalize(rawISBN)
+	if err != nil {
+		return nil, fmt.Errorf("service: invalid ISBN %q: %w: %w", rawISBN, err, ErrInvalidInput)
+	}
+	book, err := s.repo.GetBookByISBN(ctx, normalized)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get book by ISBN: %w", err)
+	}
+	if book == nil {
+		return nil, nil
+	}
+	books := []models.Book{*book}
+	if err := s.attachRelations(ctx, books); err != nil {
+		return nil, err
+	}
+	return &books[0], nil
+}
+
+// normalizeBookISBN validates a book's ISBN and stores it as an ISBN-13
+// without separators, so different spellings of one ISBN cannot coexist.
+func normalizeBookISBN(book *models.Book) error {
+	if book.ISBN == "" {
+		return fmt.Errorf("service: isbn is required: %w", ErrInvalidInput)
+	}
+	normalized, err := isbn.Normalize(book.ISBN)
+	if err != nil {
+		return fmt.Errorf("service: invalid ISBN %q: %w: %w", book.ISBN, err, ErrInvalidInput)
+	}
+	book.ISBN = normalized
+	return nil
 }
 
-// GetAllBooks retrieves all books using the repository.
-func (s *bookService) GetAllBooks(ctx context.Context) ([]models.Book, error) {
-	books, err := s.repo.GetAllBooks(ctx)
+// attachRelations fills in the linked authors and genres of books.
+func (s *bookService) attachRelations(ctx context.Context, books []models.Book) error {
+	if len(books) == 0 {
+		return nil
+	}
+	ids := make([]string, len(books))
+	for i, b := range books {
+		ids[i] = b.ID
+	}
+	authors, err := s.repo.GetBookAuthors(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("service: failed to get book authors: %w", err)
+	}
+	genres, err := s.repo.GetBookGenres(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("service: failed to get book genres: %w", err)
+	}
+
+	authorsByBook := make(map[string][]models.BookAuthor)
+	for _, a := range authors {
+		authorsByBook[a.BookID] = append(authorsByBook[a.BookID], a)
+	}
+	genresByBook := make(map[string][]models.BookGenre)
+	for _, g := range genres {
+		genresByBook[g.BookID] = append(genresByBook[g.BookID], g)
+	}
+	for i := range books {
+		books[i].Authors = authorsByBook[books[i].ID]
+		books[i].Genres = genresByBook[books[i].ID]
+	}
+	return nil
+}
+
+// validateBookRelations checks the author and genre links given on a book.
+func validateBookRelations(book *models.Book) error {
+	for _, a := range book.Authors {
+		if a.ID == "" {
+			return fmt.Errorf("service: author links need an author id: %w", ErrInvalidInput)
+		}
+		switch a.Role {
+		case "", models.AuthorRoleAuthor, models.AuthorRoleTranslator, models.AuthorRoleIllustrator:
+		default:
+			return fmt.Errorf("service: unknown author role %q: %w", a.Role, ErrInvalidInput)
+		}
+	}
+	for _, g := range book.Genres {
+		if g.ID == "" {
+			return fmt.Errorf("service: genre links need a genre id: %w", ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// Limits on book metadata.
+const (
+	maxBookSubjects      = 50
+	maxBookSubjectLength = 100
+	maxPublisherLength   = 255
+	maxEditionLength     = 100
+)
+
+// bookFormats maps accepted spellings of a book format to the stored format.
+var bookFormats = map[string]string{
+	models.BookFormatHardcover: models.BookFormatHardcover,
+	"hardback":                 models.BookFormatHardcover,
+	models.BookFormatPaperback: models.BookFormatPaperback,
+	"softcover":                models.BookFormatPaperback,
+	models.BookFormatEbook:     models.BookFormatEbook,
+	"e-book":                   models.BookFormatEbook,
+	models.BookFormatAudio:     models.BookFormatAudio,
+	"audiobook":                models.BookFormatAudio,
+}
+
+// normalizeBookFormat returns the stored format for a spelling of one, or ""
+// if it is not a known format.
+func normalizeBookFormat(format string) string {
+	return bookFormats[strings.ToLower(strings.TrimSpace(format))]
+}
+
+// normalizeLanguage reduces a language code or tag such as "EN" or "en-GB"
+// to a lower-case ISO 639 code, reporting whether it is one.
+func normalizeLanguage(language string) (string, bool) {
+	lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(language)), "-")
+	lang, _, _ = strings.Cut(lang, "_")
+	if len(lang) < 2 || len(lang) > 3 {
+		return "", false
+	}
+	for _, r := range lang {
+		if r < 'a' || r > 'z' {
+			return "", false
+		}
+	}
+	return lang, true
+}
+
+// normalizeBookMetadata validates a book's descriptive metadata and stores it
+// in canonical form: ISO 639 language codes, known formats and lower-cased,
+// de-duplicated subject tags.
+func normalizeBookMetadata(book *models.Book) error {
+	if book.Language != "" {
+		lang, ok := normalizeLanguage(book.Language)
+		if !ok {
+			return fmt.Errorf("service: invalid language %q, expected an ISO 639 code such as \"en\": %w", book.Language, ErrInvalidInput)
+		}
+		book.Language = lang
+	}
+	if book.PageCount < 0 {
+		return fmt.Errorf("service: invalid page_count %d: %w", book.PageCount, ErrInvalidInput)
+	}
+	if book.Format != "" {
+		format := normalizeBookFormat(book.Format)
+		if format == "" {
+			return fmt.Errorf("service: unknown format %q, expected hardcover, paperback, ebook or audio: %w", book.Format, ErrInvalidInput)
+		}
+		book.Format = format
+	}
+	book.Publisher = strings.TrimSpace(book.Publisher)
+	if len(book.Publisher) > maxPublisherLength {
+		return fmt.Errorf("service: publisher is longer than %d characters: %w", maxPublisherLength, ErrInvalidInput)
+	}
+	book.Edition = strings.TrimSpace(book.Edition)
+	if len(book.Edition) > maxEditionLength {
+		return fmt.Errorf("service: edition is longer than %d characters: %w", maxEditionLength, ErrInvalidInput)
+	}
+
+	seen := make(map[string]bool, len(book.Subjects))
+	subjects := make([]string, 0, len(book.Subjects))
+	for _, subject := range book.Subjects {
+		subject = strings.Join(strings.Fields(strings.ToLower(subject)), " ")
+		if subject == "" || seen[subject] {
+			continue
+		}
+		if len(subject) > maxBookSubjectLength {
+			return fmt.Errorf("service: subject %q is longer than %d characters: %w", subject, maxBookSubjectLength, ErrInvalidInput)
+		}
+		seen[subject] = true
+		subjects = append(subjects, subject)
+	}
+	if len(subjects) > maxBookSubjects {
+		return fmt.Errorf("service: book has %d subjects, at most %d allowed: %w", len(subjects), maxBookSubjects, ErrInvalidInput)
+	}
+	book.Subjects = subjects
+	return nil
+}
+
+// GetAllBooks retrieves a page of all books using the repository.
+func (s *bookService) GetAllBooks(ctx context.Context, page models.PageRequest) (*models.Page[models.Book], error) {
+	books, err := s.repo.GetAllBooks(ctx, page)
 	if err != nil {
 		return nil, fmt.Errorf("service: failed to get all books: %w", err)
 	}
+	if err := s.attachRelations(ctx, books.Items); err != nil {
+		return nil, err
+	}
 	return books, nil
 }
 
+// Search result limits.
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// SearchBooks runs a full-text search over the catalogue using the repository.
+// A limit of zero uses the default; larger limits are capped.
+func (s *bookService) SearchBooks(ctx context.Context, q string, limit int) ([]models.BookSearchResult, error) {
+	if strings.TrimSpace(q) == "" {
+		return nil, fmt.Errorf("service: search query is required: %w", ErrInvalidInput)
+	}
+	if limit < 0 {
+		return nil, fmt.Errorf("service: limit must not be negative, got %d: %w", limit, ErrInvalidInput)
+	}
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+	results, err := s.repo.SearchBooks(ctx, q, min(limit, maxSearchLimit))
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to search books: %w", err)
+	}
+	books := make([]models.Book, len(results))
+	for i, r := range results {
+		books[i] = r.Book
+	}
+	if err := s.attachRelations(ctx, books); err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Book = books[i]
+	}
+	return results, nil
+}
+
+// normalizeBookFilter checks a catalogue filter and normalises its language
+// and formats.
+func normalizeBookFilter(filter *models.BookFilter) error {
+	if filter.Sort != "" && !repositories.IsValidBookSort(filter.Sort) {
+		return fmt.Errorf("service: unknown sort order %q: %w", filter.Sort, ErrInvalidInput)
+	}
+	if filter.YearFrom != 0 && filter.YearTo != 0 && filter.YearFrom > filter.YearTo {
+		return fmt.Errorf("service: year_from %d is after year_to %d: %w", filter.YearFrom, filter.YearTo, ErrInvalidInput)
+	}
+	if filter.PagesMin < 0 || filter.PagesMax < 0 {
+		return fmt.Errorf("service: page counts must not be negative: %w", ErrInvalidInput)
+	}
+	if filter.PagesMin != 0 && filter.PagesMax != 0 && filter.PagesMin > filter.PagesMax {
+		return fmt.Errorf("service: pages_min %d is more than pages_max %d: %w", filter.PagesMin, filter.PagesMax, ErrInvalidInput)
+	}
+	if filter.Language != "" {
+		lang, ok := normalizeLanguage(filter.Language)
+		if !ok {
+			return fmt.Errorf("service: invalid language %q: %w", filter.Language, ErrInvalidInput)
+		}
+		filter.Language = lang
+	}
+	for i, f := range filter.Formats {
+		format := normalizeBookFormat(f)
+		if format == "" {
+			return fmt.Errorf("service: unknown format %q: %w", f, ErrInvalidInput)
+		}
+		filter.Formats[i] = format
+	}
+	return nil
+}
+
+// QueryBooks retrieves a page of the books matching a filter, with facet counts, using the repository.
+func (s *bookService) QueryBooks(ctx context.Context, filter models.BookFilter, page models.PageRequest) (*models.BookQueryResult, error) {
+	if err := normalizeBookFilter(&filter); err != nil {
+		return nil, err
+	}
+
+	books, err := s.repo.QueryBooks(ctx, filter, page)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to query books: %w", err)
+	}
+	if err := s.attachRelations(ctx, books.Items); err != nil {
+		return nil, err
+	}
+	facets, err := s.repo.GetBookFacets(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get book facets: %w", err)
+	}
+	return &models.BookQueryResult{Page: *books, Facets: *facets}, nil
+}
+
 // CreateBook creates a new book using the repository.
 func (s *bookService) CreateBook(ctx context.Context, book *models.Book) error {
-	// Add any business logic/validation before creating a book
+	if err := normalizeBookISBN(book); err != nil {
+		return err
+	}
+	if err := normalizeBookMetadata(book); err != nil {
+		return err
+	}
+	if err := validateBookRelations(book); err != nil {
+		return err
+	}
 	if book.ID == "" {
-		// Example: Generate a new ID if not provided
-		// book.ID = uuid.New().String()
+		book.ID = newID()
 	}
 
 	err := s.repo.CreateBook(ctx, book)
@@ -62,7 +347,16 @@ func (s *bookService) CreateBook(ctx context.Context, book *models.Book) error {
 
 // UpdateBook updates an existing book using the repository.
 func (s *bookService) UpdateBook(ctx context.Context, book *models.Book) error {
-	// Add any business logic/validation before updating a book
+	if err := normalizeBookISBN(book); err != nil {
+		return err
+	}
+	if err := normalizeBookMetadata(book); err != nil {
+		return err
+	}
+	if err := validateBookRelations(book); err != nil {
+		return err
+	}
+
 	err := s.repo.UpdateBook(ctx, book)
 	if err != nil {
 		return fmt.Errorf("service: failed to update book: %w", err)
@@ -70,12 +364,15 @@ func (s *bookService) UpdateBook(ctx context.Context, book *models.Book) error {
 	return nil
 }
 
-// DeleteBook deletes a book by its ID using the repository.
+// DeleteBook deletes a book by its ID using the repository, unless it has
+// loans or holds.
 func (s *bookService) DeleteBook(ctx context.Context, id string) error {
-	// Add any business logic/validation before deleting a book
-	err := s.repo.DeleteBook(ctx, id)
+	deleted, err := s.repo.DeleteBook(ctx, id)
 	if err != nil {
 		return fmt.Errorf("service: failed to delete book: %w", err)
 	}
+	if !deleted {
+		return fmt.Errorf("service: book %s has loans or holds and cannot be deleted: %w", id, ErrInvalidInput)
+	}
 	return nil
 }
@@ -0,0 +1,526 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"book-recommendation-system/backend/models"
+	"book-recommendation-system/backend/repositories"
+)
+
+// importBatchSize is how many valid rows are upserted per transaction. The
+// import job is checkpointed after every batch.
+const importBatchSize = 500
+
+// ImportOptions describes an import run.
+type ImportOptions struct {
+	Source string // file name or other label for the source
+	Format string // one of the ImportFormat constants
+	// LibraryID, when set, names the library whose catalogue is imported: it
+	// is recorded as holding the copies given by each record.
+	LibraryID string
+	// JobID, when set, resumes an earlier, failed import of the same source,
+	// skipping the rows it already committed.
+	JobID string
+	// RejectedRowsOnly, when set, leaves created, updated and deleted rows out
+	// of the report, which counts them in its job instead. Set it for sources
+	// too large to report row by row.
+	RejectedRowsOnly bool
+}
+
+// CatalogImportService defines the interface for bulk catalogue imports.
+type CatalogImportService interface {
+	ImportCatalog(ctx context.Context, src RecordReader, opts ImportOptions) (*models.ImportReport, error)
+	GetImportJob(ctx context.Context, id string) (*models.ImportJob, error)
+}
+
+// catalogImportService implements CatalogImportService.
+type catalogImportService struct {
+	books     repositories.BookRepository
+	authors   repositories.AuthorRepository
+	genres    repositories.GenreRepository
+	libraries repositories.LibraryRepository
+	holdings  repositories.HoldingRepository
+	jobs      repositories.ImportJobRepository
+}
+
+// NewCatalogImportService creates a new CatalogImportService.
+func NewCatalogImportService(books repositories.BookRepository, authors repositories.AuthorRepository, genres repositories.GenreRepository, libraries repositories.LibraryRepository, holdings repositories.HoldingRepository, jobs repositories.ImportJobRepository) CatalogImportService {
+	return &catalogImportService{books: books, authors: authors, genres: genres, libraries: libraries, holdings: holdings, jobs: jobs}
+}
+
+// GetImportJob retrieves an import job by its ID using the repository.
+func (s *catalogImportService) GetImportJob(ctx context.Context, id string) (*models.ImportJob, error) {
+	job, err := s.jobs.GetImportJobByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get import job: %w", err)
+	}
+	return job, nil
+}
+
+// catalogImport is the state of one import run.
+type catalogImport struct {
+	*catalogImportService
+	job         *models.ImportJob
+	report      *models.ImportReport
+	authorIndex *nameIndex
+	genreIndex  *nameIndex
+	batch       []models.ImportRecord
+	pending     map[string]bool // ISBNs in batch
+	// lastRow is the last source row handled; once the batch is flushed,
+	// everything up to it is committed.
+	lastRow int
+	// rejected counts the rows rejected since the last checkpoint. They are
+	// added to the job's count only when checkpointed, as a resumed run
+	// rejects them again.
+	rejected         int
+	rejectedRowsOnly bool
+}
+
+// ImportCatalog validates each record, links its contributors and genres by name,
+// creating any that do not exist, and upserts the books by ISBN in batches, in
+// source order with any deletes. Rows are reported as created, updated, deleted
+// or rejected with a reason. Importing
+// the same source twice updates the books in place rather than duplicating them,
+// and tops up a library's holdings rather than adding the copies again.
+func (s *catalogImportService) ImportCatalog(ctx context.Context, src RecordReader, opts ImportOptions) (*models.ImportReport, error) {
+	job, err := s.startJob(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	authors, err := loadAuthorIndex(ctx, s.authors)
+	if err != nil {
+		return nil, err
+	}
+	genres, err := loadGenreIndex(ctx, s.genres)
+	if err != nil {
+		return nil, err
+	}
+
+	imp := &catalogImport{
+		catalogImportService: s,
+		job:                  job,
+		report:               &models.ImportReport{Rows: []models.ImportRowResult{}},
+		authorIndex:          authors,
+		genreIndex:           genres,
+		pending:              make(map[string]bool),
+		rejectedRowsOnly:     opts.RejectedRowsOnly,
+	}
+	if err := imp.run(ctx, src); err != nil {
+		// The job's counts cover only checkpointed rows, which is where a
+		// resumed run picks up.
+		job.Status = models.ImportJobFailed
+		job.Error = err.Error()
+		job.UpdatedAt = time.Now()
+		if uerr := s.jobs.UpdateImportJob(context.WithoutCancel(ctx), job); uerr != nil {
+			return nil, fmt.Errorf("service: failed to record import failure: %w (import error: %v)", uerr, err)
+		}
+		return nil, fmt.Errorf("service: import job %s failed, resume it with its job ID: %w", job.ID, err)
+	}
+	sort.Slice(imp.report.Rows, func(i, j int) bool {
+		return imp.report.Rows[i].Row < imp.report.Rows[j].Row
+	})
+	imp.report.Job = *job
+	return imp.report, nil
+}
+
+// startJob resumes the job named in opts, or creates a new job when there is
+// none. Only a failed job of the same source, format and library can be
+// resumed, and only by one run at a time.
+func (s *catalogImportService) startJob(ctx context.Context, opts ImportOptions) (*models.ImportJob, error) {
+	if opts.LibraryID != "" {
+		if _, err := s.libraries.GetLibraryByID(ctx, opts.LibraryID); errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("service: unknown library %q: %w", opts.LibraryID, ErrInvalidInput)
+		} else if err != nil {
+			return nil, fmt.Errorf("service: failed to get library: %w", err)
+		}
+	}
+
+	now := time.Now()
+	jobID := opts.JobID
+	if jobID == "" {
+		job := &models.ImportJob{ID: newID(), Source: opts.Source, Format: opts.Format, LibraryID: opts.LibraryID, Status: models.ImportJobRunning, StartedAt: now, UpdatedAt: now}
+		if err := s.jobs.CreateImportJob(ctx, job); err != nil {
+			return nil, fmt.Errorf("service: failed to create import job: %w", err)
+		}
+		return job, nil
+	}
+
+	job, err := s.jobs.GetImportJobByID(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get import job: %w", err)
+	}
+	if job == nil {
+		return nil, fmt.Errorf("service: unknown import job %q: %w", jobID, ErrInvalidInput)
+	}
+	if job.Format != opts.Format {
+		return nil, fmt.Errorf("service: import job %q is a %s import, not %s: %w", jobID, job.Format, opts.Format, ErrInvalidInput)
+	}
+	if job.LibraryID != opts.LibraryID {
+		return nil, fmt.Errorf("service: import job %q is for library %q, not %q: %w", jobID, job.LibraryID, opts.LibraryID, ErrInvalidInput)
+	}
+	if job.Source != opts.Source {
+		return nil, fmt.Errorf("service: import job %q imported %q, not %q: %w", jobID, job.Source, opts.Source, ErrInvalidInput)
+	}
+	if job.Status != models.ImportJobFailed {
+		return nil, fmt.Errorf("service: import job %q is %s; only a failed job can be resumed: %w", jobID, job.Status, ErrInvalidInput)
+	}
+	resumed, err := s.jobs.ResumeImportJob(ctx, jobID, now)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to resume import job: %w", err)
+	}
+	if !resumed {
+		return nil, fmt.Errorf("service: import job %q was resumed by another run: %w", jobID, ErrInvalidInput)
+	}
+	job.Status = models.ImportJobRunning
+	job.Error = ""
+	job.UpdatedAt = now
+	return job, nil
+}
+
+func (imp *catalogImport) run(ctx context.Context, src RecordReader) error {
+	imp.lastRow = imp.job.LastCommittedRow
+	for {
+		rec, err := src.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var rowErr *RowError
+		if errors.As(err, &rowErr) {
+			if rowErr.Row > imp.job.LastCommittedRow {
+				imp.reject(rowErr.Row, "", rowErr.Err.Error())
+				imp.lastRow = rowErr.Row
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if rec.Row <= imp.job.LastCommittedRow {
+			continue // committed by an earlier run of this job
+		}
+		if err := imp.handle(ctx, &rec); err != nil {
+			return err
+		}
+	}
+
+	if err := imp.flush(ctx); err != nil {
+		return err
+	}
+	imp.job.Status = models.ImportJobCompleted
+	imp.job.UpdatedAt = time.Now()
+	if err := imp.jobs.UpdateImportJob(ctx, imp.job); err != nil {
+		return fmt.Errorf("service: failed to update import job: %w", err)
+	}
+	return nil
+}
+
+// handle deletes the record's book or queues it for the next batch.
+func (imp *catalogImport) handle(ctx context.Context, rec *models.ImportRecord) error {
+	if rec.Delete {
+		if err := imp.delete(ctx, rec); err != nil {
+			return err
+		}
+		imp.lastRow = rec.Row
+		return nil
+	}
+
+	reason, err := imp.prepare(ctx, rec)
+	if err != nil {
+		return err
+	}
+	imp.lastRow = rec.Row
+	if reason != "" {
+		imp.reject(rec.Row, rec.Book.ISBN, reason)
+		return nil
+	}
+	imp.batch = append(imp.batch, *rec)
+	imp.pending[rec.Book.ISBN] = true
+	if len(imp.batch) == importBatchSize {
+		return imp.flush(ctx)
+	}
+	return nil
+}
+
+// prepare validates a record and resolves its contributor and genre names. It
+// returns why the row is rejected, or "" if it can be imported.
+func (imp *catalogImport) prepare(ctx context.Context, rec *models.ImportRecord) (string, error) {
+	book := &rec.Book
+	if err := normalizeBookISBN(book); err != nil {
+		return strings.TrimPrefix(err.Error(), "service: "), nil
+	}
+	if rec.Partial {
+		existing, err := imp.currentBook(ctx, book.ISBN)
+		if err != nil {
+			return "", err
+		}
+		if existing == nil {
+			return "update for a book that is not in the catalogue", nil
+		}
+		mergePartialBook(rec, existing)
+	}
+	if book.Title == "" {
+		return "title is required", nil
+	}
+	if book.PublicationYear < 0 {
+		return fmt.Sprintf("invalid publication_year %d", book.PublicationYear), nil
+	}
+	if book.SeriesPosition < 0 {
+		return fmt.Sprintf("invalid series_position %g", book.SeriesPosition), nil
+	}
+	if book.SeriesPosition > 0 && book.Series == "" {
+		return "series_position given without series", nil
+	}
+	if err := normalizeBookMetadata(book); err != nil {
+		return strings.TrimPrefix(err.Error(), "service: "), nil
+	}
+
+	book.ID = newID() // kept only when the ISBN is new
+	if !rec.Partial || len(rec.Contributors) > 0 {
+		var authorNames []string
+		book.Authors = make([]models.BookAuthor, 0, len(rec.Contributors))
+		for _, c := range rec.Contributors {
+			id, err := imp.resolve(ctx, c.Name, imp.authorIndex, imp.createAuthor)
+			if err != nil {
+				return "", err
+			}
+			book.Authors = append(book.Authors, models.BookAuthor{Author: models.Author{ID: id}, Role: c.Role})
+			if c.Role == models.AuthorRoleAuthor {
+				authorNames = append(authorNames, c.Name)
+			}
+		}
+		book.Author = strings.Join(authorNames, "; ")
+	}
+	if !rec.Partial || len(rec.GenreNames) > 0 {
+		book.Genres = make([]models.BookGenre, 0, len(rec.GenreNames))
+		for _, name := range rec.GenreNames {
+			id, err := imp.resolve(ctx, name, imp.genreIndex, imp.createGenre)
+			if err != nil {
+				return "", err
+			}
+			book.Genres = append(book.Genres, models.BookGenre{Genre: models.Genre{ID: id}})
+		}
+		book.Genre = strings.Join(rec.GenreNames, ", ")
+	}
+	return "", nil
+}
+
+// currentBook returns the catalogue's book with the given ISBN, first
+// committing the pending batch if it touches that book.
+func (imp *catalogImport) currentBook(ctx context.Context, isbn string) (*models.Book, error) {
+	if imp.pending[isbn] {
+		if err := imp.flush(ctx); err != nil {
+			return nil, err
+		}
+	}
+	book, err := imp.books.GetBookByISBN(ctx, isbn)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get book by ISBN: %w", err)
+	}
+	return book, nil
+}
+
+// mergePartialBook fills the details a partial update leaves out from the
+// existing book. Author and genre links are left nil, meaning unchanged,
+// when the update names no contributors or genres.
+func mergePartialBook(rec *models.ImportRecord, existing *models.Book) {
+	book := &rec.Book
+	if book.Title == "" {
+		book.Title = existing.Title
+	}
+	if book.Description == "" {
+		book.Description = existing.Description
+	}
+	if book.CoverImageURL == "" {
+		book.CoverImageURL = existing.CoverImageURL
+	}
+	if book.PublicationYear == 0 {
+		book.PublicationYear = existing.PublicationYear
+	}
+	if book.Series == "" {
+		book.Series, book.SeriesPosition = existing.Series, existing.SeriesPosition
+	}
+	if book.Language == "" {
+		book.Language = existing.Language
+	}
+	if book.PageCount == 0 {
+		book.PageCount = existing.PageCount
+	}
+	if book.Publisher == "" {
+		book.Publisher = existing.Publisher
+	}
+	if book.Format == "" {
+		book.Format = existing.Format
+	}
+	if book.Edition == "" {
+		book.Edition = existing.Edition
+	}
+	if len(book.Subjects) == 0 {
+		book.Subjects = existing.Subjects
+	}
+	if len(rec.Contributors) == 0 {
+		book.Author = existing.Author
+	}
+	if len(rec.GenreNames) == 0 {
+		book.Genre = existing.Genre
+	}
+}
+
+// delete removes the book with the record's ISBN. Deleting a book that is
+// not in the catalogue succeeds, so replaying a feed is harmless.
+func (imp *catalogImport) delete(ctx context.Context, rec *models.ImportRecord) error {
+	if err := normalizeBookISBN(&rec.Book); err != nil {
+		imp.reject(rec.Row, rec.Book.ISBN, strings.TrimPrefix(err.Error(), "service: "))
+		return nil
+	}
+	book, err := imp.currentBook(ctx, rec.Book.ISBN)
+	if err != nil {
+		return err
+	}
+	result := models.ImportRowResult{Row: rec.Row, ISBN: rec.Book.ISBN, Status: models.ImportStatusDeleted}
+	if book != nil {
+		deleted, err := imp.books.DeleteBook(ctx, book.ID)
+		if err != nil {
+			return fmt.Errorf("service: failed to delete book %s: %w", book.ID, err)
+		}
+		if !deleted {
+			imp.reject(rec.Row, rec.Book.ISBN, "book has loans or holds and cannot be deleted")
+			return nil
+		}
+		result.BookID = book.ID
+		imp.job.Deleted++
+	}
+	imp.addRow(result)
+	return nil
+}
+
+// resolve returns the ID of the record whose normalised name equals name,
+// creating one when there is none. Imports only link exact matches so that a
+// bulk load never attaches a book to a merely similar author.
+func (imp *catalogImport) resolve(ctx context.Context, name string, idx *nameIndex, create func(context.Context, string) (string, error)) (string, error) {
+	if id, ok := idx.ids[normalizeName(name)]; ok {
+		return id, nil
+	}
+	id, err := create(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	idx.add(id, name)
+	return id, nil
+}
+
+func (imp *catalogImport) createAuthor(ctx context.Context, name string) (string, error) {
+	author := &models.Author{ID: newID(), Name: name}
+	if err := imp.authors.CreateAuthor(ctx, author); err != nil {
+		return "", fmt.Errorf("service: failed to create author %q: %w", name, err)
+	}
+	return author.ID, nil
+}
+
+func (imp *catalogImport) createGenre(ctx context.Context, name string) (string, error) {
+	genre := &models.Genre{ID: newID(), Name: name}
+	if err := imp.genres.CreateGenre(ctx, genre); err != nil {
+		return "", fmt.Errorf("service: failed to create genre %q: %w", name, err)
+	}
+	return genre.ID, nil
+}
+
+// flush upserts the pending batch and checkpoints the job at the last row handled.
+func (imp *catalogImport) flush(ctx context.Context) error {
+	if len(imp.batch) > 0 {
+		books := make([]models.Book, len(imp.batch))
+		for i, rec := range imp.batch {
+			books[i] = rec.Book
+		}
+		results, err := imp.books.UpsertBooksByISBN(ctx, books)
+		if err != nil {
+			return fmt.Errorf("service: failed to import books: %w", err)
+		}
+		for i, res := range results {
+			row := models.ImportRowResult{Row: imp.batch[i].Row, ISBN: books[i].ISBN}
+			switch {
+			case res.Err != nil:
+				imp.reject(row.Row, row.ISBN, res.Err.Error())
+				continue
+			case res.Created:
+				row.Status = models.ImportStatusCreated
+				imp.job.Created++
+			default:
+				row.Status = models.ImportStatusUpdated
+				imp.job.Updated++
+			}
+			row.BookID = res.BookID
+			imp.addRow(row)
+		}
+		if err := imp.addHoldings(ctx, results); err != nil {
+			return err
+		}
+		imp.batch = imp.batch[:0]
+		clear(imp.pending)
+	}
+
+	if imp.lastRow == imp.job.LastCommittedRow {
+		return nil
+	}
+	imp.job.LastCommittedRow = imp.lastRow
+	imp.job.Rejected += imp.rejected
+	imp.rejected = 0
+	imp.job.UpdatedAt = time.Now()
+	if err := imp.jobs.UpdateImportJob(ctx, imp.job); err != nil {
+		return fmt.Errorf("service: failed to checkpoint import job: %w", err)
+	}
+	return nil
+}
+
+// addHoldings records the import library as holding the copies given by the
+// batch's records, adding only the copies it does not hold yet.
+func (imp *catalogImport) addHoldings(ctx context.Context, results []repositories.UpsertResult) error {
+	if imp.job.LibraryID == "" {
+		return nil
+	}
+	wanted := make(map[string]int)
+	for i, res := range results {
+		if res.Err == nil {
+			wanted[res.BookID] = max(wanted[res.BookID], imp.batch[i].Copies)
+		}
+	}
+	bookIDs := make([]string, 0, len(wanted))
+	for id := range wanted {
+		bookIDs = append(bookIDs, id)
+	}
+	held, err := imp.holdings.CountLibraryHoldings(ctx, imp.job.LibraryID, bookIDs)
+	if err != nil {
+		return fmt.Errorf("service: failed to count library holdings: %w", err)
+	}
+
+	var holdings []models.Holding
+	for _, id := range bookIDs {
+		for n := held[id]; n < wanted[id]; n++ {
+			holdings = append(holdings, models.Holding{ID: newID(), LibraryID: imp.job.LibraryID, BookID: id, Status: models.HoldingStatusAvailable})
+		}
+	}
+	if err := imp.holdings.CreateHoldings(ctx, holdings); err != nil {
+		return fmt.Errorf("service: failed to create library holdings: %w", err)
+	}
+	return nil
+}
+
+func (imp *catalogImport) reject(row int, isbn, reason string) {
+	imp.rejected++
+	imp.addRow(models.ImportRowResult{Row: row, ISBN: isbn, Status: models.ImportStatusRejected, Reason: reason})
+}
+
+// addRow adds a row's result to the report, unless only rejected rows are
+// reported.
+func (imp *catalogImport) addRow(row models.ImportRowResult) {
+	if imp.rejectedRowsOnly && row.Status != models.ImportStatusRejected {
+		return
+	}
+	imp.report.Rows = append(imp.report.Rows, row)
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"book-recommendation-system/backend/models"
+)
+
+// contentWeight is the share of a real-time score that comes from how well a
+// book's content matches what the user engaged with; the rest is the
+// collaborative score.
+const contentWeight = 0.3
+
+// Kinds of content feature and how much a match on each counts. Books share
+// language, format and page length far more often than authors or subjects,
+// so those say less about taste.
+var contentFeatureWeights = map[string]float64{
+	"genre":     1,
+	"author":    1,
+	"subject":   1,
+	"publisher": 0.5,
+	"language":  0.5,
+	"format":    0.5,
+	"pages":     0.25,
+}
+
+// contentFeatures returns a book's content features, keyed "kind:value",
+// with the weight of their kind. Genres also count, partly, as their
+// ancestors in the taxonomy (see genreTaxonomy.genreWeights).
+func contentFeatures(b models.Book, genres *genreTaxonomy) map[string]float64 {
+	features := make(map[string]float64)
+	add := func(kind, value string) {
+		if value != "" {
+			features[kind+":"+value] = contentFeatureWeights[kind]
+		}
+	}
+	for _, g := range splitGenres(b.Genre) {
+		for id, w := range genres.genreWeights(g) {
+			key := "genre:" + id
+			features[key] = max(features[key], w*contentFeatureWeights["genre"])
+		}
+	}
+	for _, a := range splitNames(b.Author) {
+		add("author", normalizeName(a))
+	}
+	for _, s := range b.Subjects {
+		add("subject", s)
+	}
+	add("publisher", normalizeName(b.Publisher))
+	add("language", b.Language)
+	add("format", b.Format)
+	add("pages", pageCountBucket(b.PageCount))
+	return features
+}
+
+// pageCountBucket groups page counts into rough lengths, or "" when the page
+// count is not known.
+func pageCountBucket(pages int) string {
+	switch {
+	case pages <= 0:
+		return ""
+	case pages < 150:
+		return "short"
+	case pages < 400:
+		return "medium"
+	default:
+		return "long"
+	}
+}
+
+// contentProfile sums the content features of the books a user engaged
+// with, each weighted by how strongly they engaged.
+type contentProfile map[string]float64
+
+func newContentProfile(interactions []models.UserInteraction, books map[string]models.Book, genres *genreTaxonomy) contentProfile {
+	profile := make(contentProfile)
+	for _, ui := range interactions {
+		b, ok := books[ui.BookID]
+		if !ok {
+			continue
+		}
+		weight := interactionWeight(ui.InteractionType)
+		for f, w := range contentFeatures(b, genres) {
+			profile[f] += weight * w
+		}
+	}
+	return profile
+}
+
+// similarity returns the cosine similarity, between 0 and 1, of a book's
+// content features and the profile.
+func (p contentProfile) similarity(b models.Book, genres *genreTaxonomy) float64 {
+	var dot, bookNorm, profileNorm float64
+	for f, w := range contentFeatures(b, genres) {
+		dot += w * p[f]
+		bookNorm += w * w
+	}
+	for _, w := range p {
+		profileNorm += w * w
+	}
+	if dot == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(bookNorm*profileNorm)
+}
+
+// blendContentScores mixes each recommendation's score, which must be
+// normalised, with the content similarity of its book to the books the user
+// engaged with, found among seeds, then re-sorts and re-normalises them.
+func (s *recommendationService) blendContentScores(ctx context.Context, own []models.UserInteraction, seeds []models.Book, recommendations []models.Recommendation) ([]models.Recommendation, error) {
+	if len(recommendations) == 0 {
+		return recommendations, nil
+	}
+	allGenres, err := listAllGenres(ctx, s.genreRepo)
+	if err != nil {
+		return nil, err
+	}
+	genres := newGenreTaxonomy(allGenres)
+	books := make(map[string]models.Book, len(seeds))
+	for _, b := range seeds {
+		books[b.ID] = b
+	}
+	profile := newContentProfile(own, books, genres)
+	if len(profile) == 0 {
+		return recommendations, nil
+	}
+
+	bookIDs := make([]string, len(recommendations))
+	for i, rec := range recommendations {
+		bookIDs[i] = rec.BookID
+	}
+	candidates, err := s.bookRepo.GetBooksByIDs(ctx, bookIDs)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get recommended books: %w", err)
+	}
+	for _, b := range candidates {
+		books[b.ID] = b
+	}
+
+	for i, rec := range recommendations {
+		var content float64
+		if b, ok := books[rec.BookID]; ok {
+			content = profile.similarity(b, genres)
+		}
+		recommendations[i].Score = (1-contentWeight)*rec.Score + contentWeight*content
+	}
+	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].BookID < recommendations[j].BookID
+	})
+	normalizeScores(recommendations)
+	return recommendations, nil
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"book-recommendation-system/backend/models"
+)
+
+const (
+	// contextPriorStrength is the number of pseudo-interactions used to pull a
+	// book's context distribution towards the pooled distribution, so books
+	// with little history are barely re-weighted.
+	contextPriorStrength = 5.0
+	// minContextWeight and maxContextWeight bound the multiplier applied to a
+	// precomputed score so that context nudges rankings rather than replacing them.
+	minContextWeight = 0.5
+	maxContextWeight = 2.0
+)
+
+// normalizeContextTokens lowercases, trims and de-duplicates request context
+// tokens such as "weekend" or "mobile", dropping empty entries.
+func normalizeContextTokens(tokens []string) []string {
+	seen := make(map[string]bool, len(tokens))
+	normalized := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		normalized = append(normalized, t)
+	}
+	return normalized
+}
+
+// interactionContextTokens derives the set of context tokens an interaction
+// matches: day type and time of day in the client's time zone, plus the
+// device, locale (and its language) and coarse location when recorded.
+func interactionContextTokens(ui models.UserInteraction) map[string]bool {
+	tokens := make(map[string]bool, 6)
+
+	ts := ui.Timestamp
+	if ui.ClientTimeZone != "" {
+		if loc, err := time.LoadLocation(ui.ClientTimeZone); err == nil {
+			ts = ts.In(loc)
+		}
+	}
+	if !ts.IsZero() {
+		switch ts.Weekday() {
+		case time.Saturday, time.Sunday:
+			tokens["weekend"] = true
+		default:
+			tokens["weekday"] = true
+		}
+		switch h := ts.Hour(); {
+		case h >= 5 && h < 12:
+			tokens["morning"] = true
+		case h >= 12 && h < 17:
+			tokens["afternoon"] = true
+		case h >= 17 && h < 22:
+			tokens["evening"] = true
+		default:
+			tokens["night"] = true
+		}
+	}
+
+	if ui.Device != "" {
+		tokens[strings.ToLower(ui.Device)] = true
+	}
+	if ui.Locale != "" {
+		locale := strings.ToLower(ui.Locale)
+		tokens[locale] = true
+		if lang, _, found := strings.Cut(locale, "-"); found {
+			tokens[lang] = true
+		}
+	}
+	if ui.Location != "" {
+		tokens[strings.ToLower(ui.Location)] = true
+	}
+	return tokens
+}
+
+// contextWeights learns a per-book multiplier for the requested context from
+// interaction history. For each token the weight is the smoothed lift of the
+// book's share of interactions in that context over the pooled share; lifts
+// for several tokens are multiplied and the result is clamped.
+func contextWeights(interactions []models.UserInteraction, contextTokens []string) map[string]float64 {
+	weights := make(map[string]float64)
+	if len(interactions) == 0 || len(contextTokens) == 0 {
+		return weights
+	}
+
+	bookTotals := make(map[string]float64)
+	bookMatches := make(map[string]map[string]float64)
+	pooledMatches := make(map[string]float64, len(contextTokens))
+	for _, ui := range interactions {
+		tokens := interactionContextTokens(ui)
+		bookTotals[ui.BookID]++
+		if bookMatches[ui.BookID] == nil {
+			bookMatches[ui.BookID] = make(map[string]float64, len(contextTokens))
+		}
+		for _, t := range contextTokens {
+			if tokens[t] {
+				bookMatches[ui.BookID][t]++
+				pooledMatches[t]++
+			}
+		}
+	}
+
+	total := float64(len(interactions))
+	for bookID, n := range bookTotals {
+		weight := 1.0
+		for _, t := range contextTokens {
+			if pooledMatches[t] == 0 {
+				// Nobody has interacted in this context yet; it carries no signal.
+				continue
+			}
+			pooledShare := pooledMatches[t] / total
+			bookShare := (bookMatches[bookID][t] + contextPriorStrength*pooledShare) / (n + contextPriorStrength)
+			weight *= bookShare / pooledShare
+		}
+		weights[bookID] = math.Max(minContextWeight, math.Min(maxContextWeight, weight))
+	}
+	return weights
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// flattenOnWhite copies an image into an opaque RGBA image, flattening
+// transparent areas onto white, since thumbnails are stored as JPEG.
+func flattenOnWhite(src image.Image) *image.RGBA {
+	b := src.Bounds()
+	flat := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), src, b.Min, draw.Over)
+	return flat
+}
+
+// resizeToFit scales a flattened image (see flattenOnWhite) down, keeping
+// its aspect ratio, to fit within maxWidth by maxHeight. Each output pixel
+// averages the source pixels it covers, which keeps downscaled covers free of
+// aliasing. Images that already fit are returned as they are, not enlarged.
+func resizeToFit(flat *image.RGBA, maxWidth, maxHeight int) *image.RGBA {
+	srcW, srcH := flat.Bounds().Dx(), flat.Bounds().Dy()
+	scale := min(float64(maxWidth)/float64(srcW), float64(maxHeight)/float64(srcH), 1)
+	dstW, dstH := max(int(float64(srcW)*scale+0.5), 1), max(int(float64(srcH)*scale+0.5), 1)
+	if dstW == srcW && dstH == srcH {
+		return flat
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		y0, y1 := y*srcH/dstH, max((y+1)*srcH/dstH, y*srcH/dstH+1)
+		for x := 0; x < dstW; x++ {
+			x0, x1 := x*srcW/dstW, max((x+1)*srcW/dstW, x*srcW/dstW+1)
+			var r, g, bl, n uint32
+			for sy := y0; sy < y1; sy++ {
+				row := flat.Pix[sy*flat.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+3]
+					r += uint32(p[0])
+					g += uint32(p[1])
+					bl += uint32(p[2])
+					n++
+				}
+			}
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(bl / n)
+			dst.Pix[i+3] = 0xff
+		}
+	}
+	return dst
+}
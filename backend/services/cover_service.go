@@ -0,0 +1,183 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // register decoders for uploaded covers
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"time"
+
+	"book-recommendation-system/backend/models"
+	"book-recommendation-system/backend/repositories"
+	"book-recommendation-system/backend/storage"
+)
+
+// Cover sizes. Thumbnails fit within a box of the standard 2:3 cover shape.
+const (
+	CoverSizeSmall    = "small"
+	CoverSizeMedium   = "medium"
+	CoverSizeLarge    = "large"
+	CoverSizeOriginal = "original"
+)
+
+const (
+	// MaxCoverBytes is the largest cover image that can be uploaded.
+	MaxCoverBytes = 10 << 20
+	// maxCoverPixels rejects images that would take too much memory to
+	// decode, however well they compress.
+	maxCoverPixels = 40_000_000
+	// coverJPEGQuality is the quality thumbnails are encoded at.
+	coverJPEGQuality = 85
+)
+
+// coverThumbnails are the thumbnail sizes generated for every cover, as the
+// box each must fit within.
+var coverThumbnails = []struct {
+	size          string
+	width, height int
+}{
+	{CoverSizeSmall, 80, 120},
+	{CoverSizeMedium, 200, 300},
+	{CoverSizeLarge, 400, 600},
+}
+
+// coverFormats maps the accepted cover content types to the extension their
+// original is stored under.
+var coverFormats = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// Cover is an opened cover image. The caller must close it.
+type Cover struct {
+	io.ReadSeekCloser
+	storage.BlobInfo
+}
+
+// CoverService defines the interface for uploading and serving book covers.
+type CoverService interface {
+	UploadCover(ctx context.Context, bookID string, r io.Reader) (*models.Book, error)
+	GetCover(ctx context.Context, bookID, size string) (*Cover, error)
+}
+
+// coverService implements CoverService.
+type coverService struct {
+	books repositories.BookRepository
+	blobs storage.BlobStore
+}
+
+// NewCoverService creates a new CoverService storing covers in blobs.
+func NewCoverService(books repositories.BookRepository, blobs storage.BlobStore) CoverService {
+	return &coverService{books: books, blobs: blobs}
+}
+
+// coverKey returns the blob key of one size of a book's cover.
+func coverKey(bookID, size, ext string) string {
+	return "covers/" + bookID + "/" + size + ext
+}
+
+// UploadCover validates an uploaded JPEG, PNG or GIF cover, stores it with
+// its thumbnails and points the book's cover URL at it. It returns the
+// updated book, or nil if the book does not exist.
+func (s *coverService) UploadCover(ctx context.Context, bookID string, r io.Reader) (*models.Book, error) {
+	if _, err := s.books.GetBookByID(ctx, bookID); errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("service: failed to get book by ID: %w", err)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, MaxCoverBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to read cover: %w", err)
+	}
+	if len(data) > MaxCoverBytes {
+		return nil, fmt.Errorf("service: cover is larger than %d MB: %w", MaxCoverBytes>>20, ErrInvalidInput)
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := coverFormats[contentType]
+	if !ok {
+		return nil, fmt.Errorf("service: unsupported cover type %s, upload a JPEG, PNG or GIF image: %w", contentType, ErrInvalidInput)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("service: invalid cover image: %w: %w", err, ErrInvalidInput)
+	}
+	if cfg.Width*cfg.Height > maxCoverPixels {
+		return nil, fmt.Errorf("service: cover is %dx%d pixels, too large to process: %w", cfg.Width, cfg.Height, ErrInvalidInput)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("service: invalid cover image: %w: %w", err, ErrInvalidInput)
+	}
+
+	flat := flattenOnWhite(img)
+	for _, t := range coverThumbnails {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, resizeToFit(flat, t.width, t.height), &jpeg.Options{Quality: coverJPEGQuality}); err != nil {
+			return nil, fmt.Errorf("service: failed to encode %s cover: %w", t.size, err)
+		}
+		if err := s.blobs.Put(ctx, coverKey(bookID, t.size, ".jpg"), &buf, "image/jpeg"); err != nil {
+			return nil, fmt.Errorf("service: failed to store %s cover: %w", t.size, err)
+		}
+	}
+	// An earlier original may have been in another format.
+	for _, other := range coverFormats {
+		if other == ext {
+			continue
+		}
+		if err := s.blobs.Delete(ctx, coverKey(bookID, CoverSizeOriginal, other)); err != nil {
+			return nil, fmt.Errorf("service: failed to delete old cover: %w", err)
+		}
+	}
+	if err := s.blobs.Put(ctx, coverKey(bookID, CoverSizeOriginal, ext), bytes.NewReader(data), contentType); err != nil {
+		return nil, fmt.Errorf("service: failed to store cover: %w", err)
+	}
+
+	// The version parameter changes with every upload, so clients and caches
+	// holding an old cover fetch the new one.
+	url := fmt.Sprintf("/books/%s/cover?v=%d", bookID, time.Now().Unix())
+	if err := s.books.SetBookCover(ctx, bookID, url); err != nil {
+		return nil, fmt.Errorf("service: failed to set book cover: %w", err)
+	}
+	book, err := s.books.GetBookByID(ctx, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get book by ID: %w", err)
+	}
+	return book, nil
+}
+
+// GetCover opens one size of a book's uploaded cover, the large thumbnail by
+// default. It returns nil if the book has no uploaded cover.
+func (s *coverService) GetCover(ctx context.Context, bookID, size string) (*Cover, error) {
+	if size == "" {
+		size = CoverSizeLarge
+	}
+	exts := []string{".jpg"}
+	switch size {
+	case CoverSizeSmall, CoverSizeMedium, CoverSizeLarge:
+	case CoverSizeOriginal:
+		exts = []string{".jpg", ".png", ".gif"}
+	default:
+		return nil, fmt.Errorf("service: unknown cover size %q: %w", size, ErrInvalidInput)
+	}
+
+	for _, ext := range exts {
+		content, info, err := s.blobs.Get(ctx, coverKey(bookID, size, ext))
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to get cover: %w", err)
+		}
+		return &Cover{ReadSeekCloser: content, BlobInfo: info}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"book-recommendation-system/backend/models"
+)
+
+// workKey returns the work a book is an edition of, or the book's own ID
+// when its work is not known, such as for a book deleted since it was
+// interacted with.
+func workKey(workOf map[string]string, bookID string) string {
+	if workID, ok := workOf[bookID]; ok {
+		return workID
+	}
+	return bookID
+}
+
+// interactionBookIDs returns the distinct books of the given interactions.
+func interactionBookIDs(interactions []models.UserInteraction) []string {
+	seen := make(map[string]bool)
+	var bookIDs []string
+	for _, ui := range interactions {
+		if !seen[ui.BookID] {
+			seen[ui.BookID] = true
+			bookIDs = append(bookIDs, ui.BookID)
+		}
+	}
+	return bookIDs
+}
+
+// addBookWorks looks up the works of the given books that are not in workOf
+// yet and adds them.
+func (s *recommendationService) addBookWorks(ctx context.Context, workOf map[string]string, bookIDs []string) error {
+	missing := make([]string, 0, len(bookIDs))
+	for _, id := range bookIDs {
+		if _, ok := workOf[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	books, err := s.bookRepo.GetBooksByIDs(ctx, missing)
+	if err != nil {
+		return fmt.Errorf("service: failed to get books: %w", err)
+	}
+	for _, b := range books {
+		workOf[b.ID] = b.WorkID
+	}
+	return nil
+}
+
+// preferredLanguages counts a user's interactions by the language of their
+// locale, such as "en" for "en-GB".
+func preferredLanguages(interactions []models.UserInteraction) map[string]int {
+	languages := make(map[string]int)
+	for _, ui := range interactions {
+		if ui.Locale == "" {
+			continue
+		}
+		lang, _, _ := strings.Cut(strings.ToLower(ui.Locale), "-")
+		languages[lang]++
+	}
+	return languages
+}
+
+// betterEdition reports whether edition a suits a user better than b: it is
+// in a language they use more, then it has more copies available to borrow,
+// then it is newer.
+func betterEdition(a, b models.Book, languages, available map[string]int) bool {
+	if la, lb := languages[strings.ToLower(a.Language)], languages[strings.ToLower(b.Language)]; la != lb {
+		return la > lb
+	}
+	if available[a.ID] != available[b.ID] {
+		return available[a.ID] > available[b.ID]
+	}
+	if a.PublicationYear != b.PublicationYear {
+		return a.PublicationYear > b.PublicationYear
+	}
+	return a.ID < b.ID
+}
+
+// applyWorkRule keeps one recommendation per work, with the best score of its
+// editions, and drops works the user has already read in any edition. Each
+// work is served as the edition that suits the user best (see betterEdition),
+// replacing the recommended edition where it differs.
+func (s *recommendationService) applyWorkRule(ctx context.Context, userID string, recommendations []models.Recommendation) ([]models.Recommendation, error) {
+	if len(recommendations) == 0 {
+		return recommendations, nil
+	}
+
+	interactions, err := s.interactionRepo.GetUserInteractionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get user interactions: %w", err)
+	}
+	readAt := readBooks(interactions)
+	bookIDs := make([]string, 0, len(recommendations)+len(readAt))
+	for _, rec := range recommendations {
+		bookIDs = append(bookIDs, rec.BookID)
+	}
+	for id := range readAt {
+		bookIDs = append(bookIDs, id)
+	}
+	workOf := make(map[string]string)
+	if err := s.addBookWorks(ctx, workOf, bookIDs); err != nil {
+		return nil, err
+	}
+	readWorks := make(map[string]bool)
+	for id := range readAt {
+		readWorks[workKey(workOf, id)] = true
+	}
+
+	var workIDs []string
+	for _, rec := range recommendations {
+		if workID, ok := workOf[rec.BookID]; ok {
+			workIDs = append(workIDs, workID)
+		}
+	}
+	editions, err := s.bookRepo.GetBooksByWorkIDs(ctx, workIDs)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get editions: %w", err)
+	}
+	editionIDs := make([]string, len(editions))
+	for i, b := range editions {
+		editionIDs[i] = b.ID
+	}
+	available, err := s.holdingRepo.CountAvailableCopies(ctx, editionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to count available copies: %w", err)
+	}
+	languages := preferredLanguages(interactions)
+	best := make(map[string]models.Book)
+	for _, b := range editions {
+		if current, ok := best[b.WorkID]; !ok || betterEdition(b, current, languages, available) {
+			best[b.WorkID] = b
+		}
+	}
+
+	byWork := make(map[string]int)
+	result := make([]models.Recommendation, 0, len(recommendations))
+	for _, rec := range recommendations {
+		workID, ok := workOf[rec.BookID]
+		if !ok {
+			result = append(result, rec)
+			continue
+		}
+		if readWorks[workID] {
+			continue
+		}
+		if i, seen := byWork[workID]; seen {
+			if rec.Score > result[i].Score {
+				result[i].ID = "" // no longer the stored recommendation
+				result[i].Score = rec.Score
+			}
+			continue
+		}
+		if edition, ok := best[workID]; ok && edition.ID != rec.BookID {
+			rec.ID = ""
+			rec.BookID = edition.ID
+		}
+		byWork[workID] = len(result)
+		result = append(result, rec)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Score > result[j].Score
+	})
+	return result, nil
+}
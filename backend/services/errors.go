@@ -0,0 +1,7 @@
+package services
+
+import "errors"
+
+// ErrInvalidInput is wrapped by service errors caused by invalid caller input,
+// so handlers can report them as client errors.
+var ErrInvalidInput = errors.New("invalid input")
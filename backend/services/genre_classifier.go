@@ -0,0 +1,201 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// classifierSmoothing is the additive smoothing applied to each term's
+	// weight per genre, so terms never seen with a genre do not rule it out.
+	classifierSmoothing = 0.1
+	// minTermDocuments drops terms from fewer training books than this, which
+	// say more about one book than about its genre.
+	minTermDocuments = 2
+	// minTokenLength drops tokens too short to carry meaning.
+	minTokenLength = 3
+)
+
+// classifierStopWords are common English words that say nothing about a
+// book's genre.
+var classifierStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true,
+	"you": true, "all": true, "any": true, "can": true, "her": true, "was": true,
+	"one": true, "our": true, "out": true, "his": true, "has": true, "had": true,
+	"who": true, "its": true, "how": true, "new": true, "now": true, "she": true,
+	"him": true, "they": true, "them": true, "their": true, "there": true,
+	"this": true, "that": true, "these": true, "those": true, "with": true,
+	"from": true, "into": true, "about": true, "after": true, "before": true,
+	"when": true, "where": true, "which": true, "while": true, "what": true,
+	"will": true, "would": true, "could": true, "should": true, "have": true,
+	"been": true, "being": true, "were": true, "than": true, "then": true,
+	"also": true, "only": true, "more": true, "most": true, "some": true,
+	"such": true, "each": true, "other": true, "over": true, "very": true,
+	"book": true, "books": true, "novel": true, "story": true, "edition": true,
+}
+
+// tokenize splits text into lower-cased words, dropping stop words and
+// tokens that are too short or purely numeric.
+func tokenize(text string) []string {
+	var tokens []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len([]rune(word)) < minTokenLength || classifierStopWords[word] || strings.IndexFunc(word, unicode.IsLetter) < 0 {
+			continue
+		}
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
+
+// labelledText is a training example: a book's text and its genres.
+type labelledText struct {
+	text   string
+	labels []string
+}
+
+// genreClassifier is a multinomial naive Bayes classifier over TF-IDF
+// weighted terms: each book's text is turned into sublinear term frequencies
+// times inverse document frequencies, normalised to unit length, and each
+// genre learns how much weight its books put on every term.
+type genreClassifier struct {
+	idf      map[string]float64
+	prior    map[string]float64            // log share of training books per genre
+	termLogs map[string]map[string]float64 // genre -> term -> log probability
+	unseen   map[string]float64            // genre -> log probability of a term never seen with it
+}
+
+// trainGenreClassifier learns a classifier from labelled texts. A book with
+// several genres counts as an example of each.
+func trainGenreClassifier(examples []labelledText) *genreClassifier {
+	docs := make([][]string, len(examples))
+	df := make(map[string]int)
+	for i, ex := range examples {
+		docs[i] = tokenize(ex.text)
+		seen := make(map[string]bool)
+		for _, t := range docs[i] {
+			if !seen[t] {
+				seen[t] = true
+				df[t]++
+			}
+		}
+	}
+	c := &genreClassifier{
+		idf:      make(map[string]float64),
+		prior:    make(map[string]float64),
+		termLogs: make(map[string]map[string]float64),
+		unseen:   make(map[string]float64),
+	}
+	n := float64(len(examples))
+	for t, count := range df {
+		if count >= minTermDocuments {
+			c.idf[t] = math.Log((1+n)/(1+float64(count))) + 1
+		}
+	}
+
+	counts := make(map[string]int)
+	weights := make(map[string]map[string]float64)
+	totals := make(map[string]float64)
+	for i, ex := range examples {
+		vector := c.vectorize(docs[i])
+		for _, genre := range ex.labels {
+			counts[genre]++
+			if weights[genre] == nil {
+				weights[genre] = make(map[string]float64)
+			}
+			for t, w := range vector {
+				weights[genre][t] += w
+				totals[genre] += w
+			}
+		}
+	}
+
+	var labelled int
+	for _, count := range counts {
+		labelled += count
+	}
+	vocabulary := float64(len(c.idf))
+	for genre, count := range counts {
+		c.prior[genre] = math.Log(float64(count) / float64(labelled))
+		denominator := totals[genre] + classifierSmoothing*vocabulary
+		logs := make(map[string]float64, len(weights[genre]))
+		for t, w := range weights[genre] {
+			logs[t] = math.Log((w + classifierSmoothing) / denominator)
+		}
+		c.termLogs[genre] = logs
+		c.unseen[genre] = math.Log(classifierSmoothing / denominator)
+	}
+	return c
+}
+
+// vectorize returns the unit-length TF-IDF vector of a tokenised text, over
+// the classifier's vocabulary.
+func (c *genreClassifier) vectorize(tokens []string) map[string]float64 {
+	tf := make(map[string]int)
+	for _, t := range tokens {
+		if _, ok := c.idf[t]; ok {
+			tf[t]++
+		}
+	}
+	vector := make(map[string]float64, len(tf))
+	var norm float64
+	for t, count := range tf {
+		w := (1 + math.Log(float64(count))) * c.idf[t]
+		vector[t] = w
+		norm += w * w
+	}
+	norm = math.Sqrt(norm)
+	for t := range vector {
+		vector[t] /= norm
+	}
+	return vector
+}
+
+// genreProbability is a genre with the classifier's probability that a text
+// belongs to it.
+type genreProbability struct {
+	genre       string
+	probability float64
+}
+
+// classify returns the probability of each genre for a text, most likely
+// first, or nil if none of its terms are in the vocabulary.
+func (c *genreClassifier) classify(text string) []genreProbability {
+	vector := c.vectorize(tokenize(text))
+	if len(vector) == 0 || len(c.prior) == 0 {
+		return nil
+	}
+	logs := make([]genreProbability, 0, len(c.prior))
+	best := math.Inf(-1)
+	for genre, prior := range c.prior {
+		score := prior
+		for t, w := range vector {
+			termLog, ok := c.termLogs[genre][t]
+			if !ok {
+				termLog = c.unseen[genre]
+			}
+			score += w * termLog
+		}
+		logs = append(logs, genreProbability{genre: genre, probability: score})
+		best = max(best, score)
+	}
+	// Softmax, shifted by the best score to keep the exponentials in range.
+	var sum float64
+	for i := range logs {
+		logs[i].probability = math.Exp(logs[i].probability - best)
+		sum += logs[i].probability
+	}
+	for i := range logs {
+		logs[i].probability /= sum
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		if logs[i].probability != logs[j].probability {
+			return logs[i].probability > logs[j].probability
+		}
+		return logs[i].genre < logs[j].genre
+	})
+	return logs
+}
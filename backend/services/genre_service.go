@@ -2,7 +2,10 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 
 	"book-recommendation-system/backend/models"
 	"book-recommendation-system/backend/repositories"
@@ -11,20 +14,24 @@ import (
 // GenreService defines the interface for genre-related business logic.
 type GenreService interface {
 	GetGenreByID(ctx context.Context, id string) (*models.Genre, error)
-	GetAllGenres(ctx context.Context) ([]models.Genre, error)
+	GetAllGenres(ctx context.Context, page models.PageRequest) (*models.Page[models.Genre], error)
 	CreateGenre(ctx context.Context, genre *models.Genre) error
 	UpdateGenre(ctx context.Context, genre *models.Genre) error
 	DeleteGenre(ctx context.Context, id string) error
+	GetGenreTree(ctx context.Context) ([]models.GenreNode, error)
+	GetGenreSubtree(ctx context.Context, id string) (*models.GenreNode, error)
+	GetGenreBooks(ctx context.Context, id string, page models.PageRequest) (*models.Page[models.Book], error)
 }
 
 // genreService implements GenreService.
 type genreService struct {
-	repo repositories.GenreRepository
+	repo  repositories.GenreRepository
+	books repositories.BookRepository
 }
 
 // NewGenreService creates a new GenreService.
-func NewGenreService(repo repositories.GenreRepository) GenreService {
-	return &genreService{repo: repo}
+func NewGenreService(repo repositories.GenreRepository, books repositories.BookRepository) GenreService {
+	return &genreService{repo: repo, books: books}
 }
 
 // GetGenreByID retrieves a genre by its ID using the repository.
@@ -36,9 +43,9 @@ func (s *genreService) GetGenreByID(ctx context.Context, id string) (*models.Gen
 	return genre, nil
 }
 
-// GetAllGenres retrieves all genres using the repository.
-func (s *genreService) GetAllGenres(ctx context.Context) ([]models.Genre, error) {
-	genres, err := s.repo.GetAllGenres(ctx)
+// GetAllGenres retrieves a page of all genres using the repository.
+func (s *genreService) GetAllGenres(ctx context.Context, page models.PageRequest) (*models.Page[models.Genre], error) {
+	genres, err := s.repo.GetAllGenres(ctx, page)
 	if err != nil {
 		return nil, fmt.Errorf("service: failed to get all genres: %w", err)
 	}
@@ -47,7 +54,9 @@ func (s *genreService) GetAllGenres(ctx context.Context) ([]models.Genre, error)
 
 // CreateGenre creates a new genre using the repository.
 func (s *genreService) CreateGenre(ctx context.Context, genre *models.Genre) error {
-	// Add any business logic/validation before creating a genre
+	if err := s.validateGenre(ctx, genre); err != nil {
+		return err
+	}
 	err := s.repo.CreateGenre(ctx, genre)
 	if err != nil {
 		return fmt.Errorf("service: failed to create genre: %w", err)
@@ -57,7 +66,9 @@ func (s *genreService) CreateGenre(ctx context.Context, genre *models.Genre) err
 
 // UpdateGenre updates an existing genre using the repository.
 func (s *genreService) UpdateGenre(ctx context.Context, genre *models.Genre) error {
-	// Add any business logic/validation before updating a genre
+	if err := s.validateGenre(ctx, genre); err != nil {
+		return err
+	}
 	err := s.repo.UpdateGenre(ctx, genre)
 	if err != nil {
 		return fmt.Errorf("service: failed to update genre: %w", err)
@@ -74,3 +85,108 @@ func (s *genreService) DeleteGenre(ctx context.Context, id string) error {
 	}
 	return nil
 }
+
+// validateGenre tidies a genre's name and aliases and checks that neither is
+// taken by another genre, and that its parent exists and is not the genre
+// itself or one of its subgenres.
+func (s *genreService) validateGenre(ctx context.Context, genre *models.Genre) error {
+	genre.Name = strings.TrimSpace(genre.Name)
+	if genre.Name == "" {
+		return fmt.Errorf("service: genre name is required: %w", ErrInvalidInput)
+	}
+	seen := map[string]bool{strings.ToLower(genre.Name): true}
+	aliases := genre.Aliases[:0]
+	for _, alias := range genre.Aliases {
+		alias = strings.TrimSpace(alias)
+		if key := strings.ToLower(alias); alias != "" && !seen[key] {
+			seen[key] = true
+			aliases = append(aliases, alias)
+		}
+	}
+	genre.Aliases = aliases
+
+	for _, name := range append([]string{genre.Name}, genre.Aliases...) {
+		other, err := s.repo.GetGenreByName(ctx, name)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("service: failed to get genre by name: %w", err)
+		}
+		if other.ID != genre.ID {
+			return fmt.Errorf("service: %q is already a name of genre %s: %w", name, other.ID, ErrInvalidInput)
+		}
+	}
+
+	genre.ParentID = strings.TrimSpace(genre.ParentID)
+	if genre.ParentID == "" {
+		return nil
+	}
+	if genre.ParentID == genre.ID {
+		return fmt.Errorf("service: genre cannot be its own parent: %w", ErrInvalidInput)
+	}
+	if _, err := s.repo.GetGenreByID(ctx, genre.ParentID); errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("service: parent genre %s does not exist: %w", genre.ParentID, ErrInvalidInput)
+	} else if err != nil {
+		return fmt.Errorf("service: failed to get parent genre: %w", err)
+	}
+	ancestors, err := s.repo.GetGenreAncestorIDs(ctx, genre.ParentID)
+	if err != nil {
+		return fmt.Errorf("service: failed to get parent genre ancestors: %w", err)
+	}
+	for _, id := range ancestors {
+		if id == genre.ID {
+			return fmt.Errorf("service: genre %s is a subgenre of %s and cannot be its parent: %w", genre.ParentID, genre.ID, ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// GetGenreTree retrieves the whole genre taxonomy as trees under the
+// top-level genres.
+func (s *genreService) GetGenreTree(ctx context.Context) ([]models.GenreNode, error) {
+	genres, err := listAllGenres(ctx, s.repo)
+	if err != nil {
+		return nil, err
+	}
+	return buildGenreTree(genres), nil
+}
+
+// GetGenreSubtree retrieves a genre with all its subgenres, returning nil if
+// the genre does not exist.
+func (s *genreService) GetGenreSubtree(ctx context.Context, id string) (*models.GenreNode, error) {
+	genres, err := listAllGenres(ctx, s.repo)
+	if err != nil {
+		return nil, err
+	}
+	children := make(map[string][]models.Genre)
+	var root *models.Genre
+	for i, g := range genres {
+		if g.ID == id {
+			root = &genres[i]
+		}
+		if g.ParentID != "" {
+			children[g.ParentID] = append(children[g.ParentID], g)
+		}
+	}
+	if root == nil {
+		return nil, nil
+	}
+	node := genreNode(*root, children, make(map[string]bool))
+	return &node, nil
+}
+
+// GetGenreBooks retrieves a page of the books in a genre or any of its
+// subgenres, returning nil if the genre does not exist.
+func (s *genreService) GetGenreBooks(ctx context.Context, id string, page models.PageRequest) (*models.Page[models.Book], error) {
+	if _, err := s.repo.GetGenreByID(ctx, id); errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("service: failed to get genre by ID: %w", err)
+	}
+	books, err := s.books.QueryBooks(ctx, models.BookFilter{GenreIDs: []string{id}}, page)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get genre books: %w", err)
+	}
+	return books, nil
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"book-recommendation-system/backend/models"
+	"book-recommendation-system/backend/repositories"
+)
+
+const (
+	// minGenreExamples is how many labelled books a genre needs before it is
+	// suggested; fewer are too little to learn it from.
+	minGenreExamples = 3
+	// minClassifyTokens is how many words an untagged book's title,
+	// description and subjects need between them to be classified.
+	minClassifyTokens = 5
+	// minSuggestionConfidence and maxSuggestionsPerBook bound which of a
+	// book's most likely genres are suggested.
+	minSuggestionConfidence = 0.25
+	maxSuggestionsPerBook   = 3
+)
+
+// GenreSuggestionService defines the interface for suggesting genres for
+// untagged books and reviewing the suggestions.
+type GenreSuggestionService interface {
+	GenerateSuggestions(ctx context.Context) (*models.GenreSuggestionRun, error)
+	GetGenreSuggestions(ctx context.Context, status string, page models.PageRequest) (*models.Page[models.GenreSuggestion], error)
+	ReviewGenreSuggestion(ctx context.Context, id, status string) (*models.GenreSuggestion, error)
+}
+
+// genreSuggestionService implements GenreSuggestionService.
+type genreSuggestionService struct {
+	books       repositories.BookRepository
+	suggestions repositories.GenreSuggestionRepository
+}
+
+// NewGenreSuggestionService creates a new GenreSuggestionService.
+func NewGenreSuggestionService(books repositories.BookRepository, suggestions repositories.GenreSuggestionRepository) GenreSuggestionService {
+	return &genreSuggestionService{books: books, suggestions: suggestions}
+}
+
+// classifierText is the text of a book the genre classifier reads.
+func classifierText(b models.Book) string {
+	return strings.Join(append([]string{b.Title, b.Description}, b.Subjects...), " ")
+}
+
+// GenerateSuggestions trains a genre classifier on the books already linked
+// to genres and suggests genres for the books with none, replacing the
+// suggestions still awaiting review. Genres already accepted or rejected for
+// a book are not suggested for it again.
+func (s *genreSuggestionService) GenerateSuggestions(ctx context.Context) (*models.GenreSuggestionRun, error) {
+	var labelled []labelledText
+	var untagged []models.Book
+	page := models.PageRequest{Limit: models.MaxPageLimit}
+	for {
+		result, err := s.books.GetAllBooks(ctx, page)
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to list books: %w", err)
+		}
+		ids := make([]string, len(result.Items))
+		for i, b := range result.Items {
+			ids[i] = b.ID
+		}
+		links, err := s.books.GetBookGenres(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to get book genres: %w", err)
+		}
+		genresOf := make(map[string][]string)
+		for _, l := range links {
+			genresOf[l.BookID] = append(genresOf[l.BookID], l.ID)
+		}
+		for _, b := range result.Items {
+			switch {
+			case len(genresOf[b.ID]) > 0:
+				labelled = append(labelled, labelledText{text: classifierText(b), labels: genresOf[b.ID]})
+			case strings.TrimSpace(b.Genre) == "":
+				untagged = append(untagged, b)
+			}
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		page.Cursor = result.NextCursor
+	}
+
+	examples := make(map[string]int)
+	for _, ex := range labelled {
+		for _, genre := range ex.labels {
+			examples[genre]++
+		}
+	}
+	training := labelled[:0]
+	for _, ex := range labelled {
+		var labels []string
+		for _, genre := range ex.labels {
+			if examples[genre] >= minGenreExamples {
+				labels = append(labels, genre)
+			}
+		}
+		if len(labels) > 0 {
+			ex.labels = labels
+			training = append(training, ex)
+		}
+	}
+	run := &models.GenreSuggestionRun{TrainingBooks: len(training)}
+	for _, count := range examples {
+		if count >= minGenreExamples {
+			run.Genres++
+		}
+	}
+
+	classifier := trainGenreClassifier(training)
+	now := time.Now()
+	var suggestions []models.GenreSuggestion
+	for _, b := range untagged {
+		text := classifierText(b)
+		if len(tokenize(text)) < minClassifyTokens {
+			continue
+		}
+		run.BooksClassified++
+		for i, p := range classifier.classify(text) {
+			if i == maxSuggestionsPerBook || p.probability < minSuggestionConfidence {
+				break
+			}
+			suggestions = append(suggestions, models.GenreSuggestion{
+				ID:         newID(),
+				BookID:     b.ID,
+				GenreID:    p.genre,
+				Confidence: p.probability,
+				Status:     models.GenreSuggestionPending,
+				CreatedAt:  now,
+			})
+		}
+	}
+
+	stored, err := s.suggestions.ReplacePendingSuggestions(ctx, suggestions)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to store genre suggestions: %w", err)
+	}
+	run.Suggestions = stored
+	return run, nil
+}
+
+// GetGenreSuggestions retrieves a page of the suggestions with a status,
+// pending by default, most confident first.
+func (s *genreSuggestionService) GetGenreSuggestions(ctx context.Context, status string, page models.PageRequest) (*models.Page[models.GenreSuggestion], error) {
+	switch status {
+	case "":
+		status = models.GenreSuggestionPending
+	case models.GenreSuggestionPending, models.GenreSuggestionAccepted, models.GenreSuggestionRejected:
+	default:
+		return nil, fmt.Errorf("service: unknown suggestion status %q: %w", status, ErrInvalidInput)
+	}
+	suggestions, err := s.suggestions.GetGenreSuggestions(ctx, status, page)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get genre suggestions: %w", err)
+	}
+	return suggestions, nil
+}
+
+// ReviewGenreSuggestion accepts or rejects a pending suggestion. Accepting it
+// tags the book with the genre. It returns the reviewed suggestion, or nil if
+// there is none with the ID.
+func (s *genreSuggestionService) ReviewGenreSuggestion(ctx context.Context, id, status string) (*models.GenreSuggestion, error) {
+	if status != models.GenreSuggestionAccepted && status != models.GenreSuggestionRejected {
+		return nil, fmt.Errorf("service: a suggestion can only be accepted or rejected, not %q: %w", status, ErrInvalidInput)
+	}
+	suggestion, err := s.suggestions.GetGenreSuggestionByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get genre suggestion: %w", err)
+	}
+	if suggestion == nil {
+		return nil, nil
+	}
+	if suggestion.Status != models.GenreSuggestionPending {
+		return nil, fmt.Errorf("service: suggestion %s was already %s: %w", id, suggestion.Status, ErrInvalidInput)
+	}
+
+	reviewed, err := s.suggestions.ReviewGenreSuggestion(ctx, id, status)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to review genre suggestion: %w", err)
+	}
+	if !reviewed {
+		return nil, fmt.Errorf("service: suggestion %s was already reviewed: %w", id, ErrInvalidInput)
+	}
+	suggestion, err = s.suggestions.GetGenreSuggestionByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get genre suggestion: %w", err)
+	}
+	return suggestion, nil
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"book-recommendation-system/backend/models"
+	"book-recommendation-system/backend/repositories"
+)
+
+// listAllGenres retrieves every genre, ordered by name.
+func listAllGenres(ctx context.Context, repo repositories.GenreRepository) ([]models.Genre, error) {
+	var all []models.Genre
+	page := models.PageRequest{Limit: models.MaxPageLimit}
+	for {
+		genres, err := repo.GetAllGenres(ctx, page)
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to list genres: %w", err)
+		}
+		all = append(all, genres.Items...)
+		if genres.NextCursor == "" {
+			return all, nil
+		}
+		page.Cursor = genres.NextCursor
+	}
+}
+
+// buildGenreTree arranges genres, ordered by name, into trees rooted at the
+// top-level genres. A genre whose parent is missing is treated as top-level.
+func buildGenreTree(genres []models.Genre) []models.GenreNode {
+	known := make(map[string]bool, len(genres))
+	for _, g := range genres {
+		known[g.ID] = true
+	}
+	children := make(map[string][]models.Genre)
+	var roots []models.Genre
+	for _, g := range genres {
+		if g.ParentID == "" || !known[g.ParentID] {
+			roots = append(roots, g)
+		} else {
+			children[g.ParentID] = append(children[g.ParentID], g)
+		}
+	}
+	nodes := make([]models.GenreNode, 0, len(roots))
+	for _, g := range roots {
+		nodes = append(nodes, genreNode(g, children, make(map[string]bool)))
+	}
+	return nodes
+}
+
+// genreNode builds the subtree under a genre. visited guards against cycles.
+func genreNode(g models.Genre, children map[string][]models.Genre, visited map[string]bool) models.GenreNode {
+	visited[g.ID] = true
+	node := models.GenreNode{Genre: g, Children: []models.GenreNode{}}
+	for _, child := range children[g.ID] {
+		if !visited[child.ID] {
+			node.Children = append(node.Children, genreNode(child, children, visited))
+		}
+	}
+	return node
+}
+
+// ancestorGenreWeight is how much a genre counts towards each of its
+// ancestors, per level: a book in Epic Fantasy is half a match for Fantasy
+// and a quarter of one for Fiction.
+const ancestorGenreWeight = 0.5
+
+// genreTaxonomy resolves genre names and aliases to genres and knows each
+// genre's ancestors.
+type genreTaxonomy struct {
+	byName map[string]string // normalised name or alias to genre ID
+	parent map[string]string // genre ID to parent genre ID
+}
+
+func newGenreTaxonomy(genres []models.Genre) *genreTaxonomy {
+	t := &genreTaxonomy{byName: make(map[string]string), parent: make(map[string]string)}
+	for _, g := range genres {
+		t.byName[normalizeName(g.Name)] = g.ID
+		if g.ParentID != "" {
+			t.parent[g.ID] = g.ParentID
+		}
+	}
+	// Aliases never shadow a genre's own name.
+	for _, g := range genres {
+		for _, alias := range g.Aliases {
+			if key := normalizeName(alias); t.byName[key] == "" {
+				t.byName[key] = g.ID
+			}
+		}
+	}
+	return t
+}
+
+// genreWeights returns the genres a genre name stands for with how fully it
+// matches each: 1 for the genre itself, halving for each ancestor. A name
+// that is not in the taxonomy stands only for itself, keyed by its
+// normalised form.
+func (t *genreTaxonomy) genreWeights(name string) map[string]float64 {
+	key := normalizeName(name)
+	if key == "" {
+		return nil
+	}
+	id, ok := t.byName[key]
+	if !ok {
+		return map[string]float64{key: 1}
+	}
+	weights := map[string]float64{id: 1}
+	weight := 1.0
+	for parent, ok := t.parent[id]; ok; parent, ok = t.parent[parent] {
+		if _, seen := weights[parent]; seen {
+			break
+		}
+		weight *= ancestorGenreWeight
+		weights[parent] = weight
+	}
+	return weights
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"book-recommendation-system/backend/models"
+)
+
+// Group aggregation strategies.
+const (
+	GroupStrategyAverage      = "average"
+	GroupStrategyLeastMisery  = "least_misery"
+	GroupStrategyMostPleasure = "most_pleasure"
+	GroupStrategyFairness     = "fairness"
+)
+
+const (
+	defaultGroupRecommendationLimit = 10
+	maxGroupRecommendationLimit     = 100
+	maxGroupSize                    = 50
+)
+
+// consumedInteractionTypes are the interaction types that mean a user has
+// already read a book, so it is not recommended to their group again.
+var consumedInteractionTypes = map[string]bool{
+	models.InteractionTypeRead:   true,
+	models.InteractionTypeBorrow: true,
+	models.InteractionTypeRating: true,
+}
+
+// GetGroupRecommendations aggregates the members' individual recommendations into
+// a single list for the group using the requested strategy. Books any member has
+// already read are excluded.
+func (s *recommendationService) GetGroupRecommendations(ctx context.Context, req models.GroupRecommendationRequest) ([]models.GroupRecommendation, error) {
+	members := normalizeGroupMembers(req.UserIDs)
+	if len(members) == 0 {
+		return nil, fmt.Errorf("service: group must have at least one member: %w", ErrInvalidInput)
+	}
+	if len(members) > maxGroupSize {
+		return nil, fmt.Errorf("service: group has %d members, at most %d allowed: %w", len(members), maxGroupSize, ErrInvalidInput)
+	}
+	strategy := req.Strategy
+	if strategy == "" {
+		strategy = GroupStrategyAverage
+	}
+	switch strategy {
+	case GroupStrategyAverage, GroupStrategyLeastMisery, GroupStrategyMostPleasure, GroupStrategyFairness:
+	default:
+		return nil, fmt.Errorf("service: unknown group strategy %q: %w", req.Strategy, ErrInvalidInput)
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultGroupRecommendationLimit
+	}
+	limit = min(limit, maxGroupRecommendationLimit)
+
+	recommendations, err := s.repo.GetRecommendationsByUserIDs(ctx, members)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get member recommendations: %w", err)
+	}
+	interactions, err := s.interactionRepo.GetUserInteractionsByUserIDs(ctx, members)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get member interactions: %w", err)
+	}
+
+	read := make(map[string]bool)
+	for _, ui := range interactions {
+		if consumedInteractionTypes[ui.InteractionType] {
+			read[ui.BookID] = true
+		}
+	}
+	prefs := memberPreferences(recommendations, read)
+
+	candidates := make([]string, 0, len(prefs))
+	for bookID := range prefs {
+		candidates = append(candidates, bookID)
+	}
+	sort.Strings(candidates)
+
+	var picks []models.GroupRecommendation
+	if strategy == GroupStrategyFairness {
+		picks = fairGroupPicks(candidates, members, prefs, limit)
+	} else {
+		picks = make([]models.GroupRecommendation, 0, len(candidates))
+		for _, bookID := range candidates {
+			picks = append(picks, models.GroupRecommendation{
+				BookID: bookID,
+				Score:  aggregateGroupScore(strategy, members, prefs[bookID]),
+			})
+		}
+		sort.SliceStable(picks, func(i, j int) bool {
+			return picks[i].Score > picks[j].Score
+		})
+		if len(picks) > limit {
+			picks = picks[:limit]
+		}
+	}
+
+	for i := range picks {
+		picks[i].Members = explainMemberPreferences(members, prefs[picks[i].BookID])
+	}
+	return picks, nil
+}
+
+// normalizeGroupMembers drops empty and duplicate user IDs, keeping order.
+func normalizeGroupMembers(userIDs []string) []string {
+	seen := make(map[string]bool, len(userIDs))
+	members := make([]string, 0, len(userIDs))
+	for _, id := range userIDs {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		members = append(members, id)
+	}
+	return members
+}
+
+// memberPreferences indexes predicted preferences by book and then user,
+// scaling each user's scores by their own maximum so that members who
+// receive uniformly high scores do not dominate the group. Books in
+// excluded are skipped.
+func memberPreferences(recommendations []models.Recommendation, excluded map[string]bool) map[string]map[string]float64 {
+	maxScore := make(map[string]float64)
+	for _, rec := range recommendations {
+		maxScore[rec.UserID] = max(maxScore[rec.UserID], rec.Score)
+	}
+
+	prefs := make(map[string]map[string]float64)
+	for _, rec := range recommendations {
+		if excluded[rec.BookID] || maxScore[rec.UserID] <= 0 {
+			continue
+		}
+		if prefs[rec.BookID] == nil {
+			prefs[rec.BookID] = make(map[string]float64)
+		}
+		score := max(rec.Score, 0) / maxScore[rec.UserID]
+		prefs[rec.BookID][rec.UserID] = max(prefs[rec.BookID][rec.UserID], score)
+	}
+	return prefs
+}
+
+// aggregateGroupScore combines the members' preferences for one book. A member
+// without a prediction for the book counts as zero.
+func aggregateGroupScore(strategy string, members []string, bookPrefs map[string]float64) float64 {
+	switch strategy {
+	case GroupStrategyLeastMisery:
+		score := 1.0
+		for _, m := range members {
+			score = min(score, bookPrefs[m])
+		}
+		return score
+	case GroupStrategyMostPleasure:
+		score := 0.0
+		for _, m := range members {
+			score = max(score, bookPrefs[m])
+		}
+		return score
+	default:
+		sum := 0.0
+		for _, m := range members {
+			sum += bookPrefs[m]
+		}
+		return sum / float64(len(members))
+	}
+}
+
+// fairGroupPicks greedily builds the list so that members who have been
+// served poorly by earlier picks weigh more in later ones: each member's
+// weight is 1/(1+satisfaction), where satisfaction is the sum of their
+// preferences for the books picked so far.
+func fairGroupPicks(candidates, members []string, prefs map[string]map[string]float64, limit int) []models.GroupRecommendation {
+	satisfaction := make(map[string]float64, len(members))
+	remaining := append([]string(nil), candidates...)
+	picks := make([]models.GroupRecommendation, 0, min(limit, len(candidates)))
+
+	for len(picks) < limit && len(remaining) > 0 {
+		best, bestScore := -1, -1.0
+		for i, bookID := range remaining {
+			weighted, totalWeight := 0.0, 0.0
+			for _, m := range members {
+				w := 1 / (1 + satisfaction[m])
+				weighted += w * prefs[bookID][m]
+				totalWeight += w
+			}
+			if score := weighted / totalWeight; score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+
+		bookID := remaining[best]
+		for _, m := range members {
+			satisfaction[m] += prefs[bookID][m]
+		}
+		picks = append(picks, models.GroupRecommendation{BookID: bookID, Score: bestScore})
+		remaining = append(remaining[:best], remaining[best+1:]...)
+	}
+	return picks
+}
+
+// explainMemberPreferences describes how each member is expected to like a book.
+func explainMemberPreferences(members []string, bookPrefs map[string]float64) []models.MemberPreference {
+	explained := make([]models.MemberPreference, 0, len(members))
+	for _, m := range members {
+		score, ok := bookPrefs[m]
+		var explanation string
+		switch {
+		case !ok:
+			explanation = "no prediction yet; not among this member's recommendations"
+		case score >= 0.75:
+			explanation = "expected to love it"
+		case score >= 0.5:
+			explanation = "expected to like it"
+		case score >= 0.25:
+			explanation = "expected to find it okay"
+		default:
+			explanation = "unlikely to enjoy it"
+		}
+		explained = append(explained, models.MemberPreference{UserID: m, Score: score, Explanation: explanation})
+	}
+	return explained
+}
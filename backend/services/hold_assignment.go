@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"book-recommendation-system/backend/models"
+	"book-recommendation-system/backend/repositories"
+)
+
+// days converts a library policy's number of days to a duration.
+func days(n int) time.Duration {
+	return time.Duration(n) * 24 * time.Hour
+}
+
+// holdFilledBy reports whether a copy held by a library can fill a hold.
+func holdFilledBy(hold models.Hold, libraryID string) bool {
+	return hold.LibraryID == "" || hold.LibraryID == libraryID
+}
+
+// holdAssigner gives copies that become available to the next hold in line.
+// Holds on a book form a single first-come, first-served queue; a hold for
+// one library is skipped over by copies from other libraries.
+type holdAssigner struct {
+	holds     repositories.HoldRepository
+	holdings  repositories.HoldingRepository
+	libraries repositories.LibraryRepository
+}
+
+// assignCopy puts an available copy on the hold shelf for the oldest waiting
+// hold it can fill, for as long as the hold's pickup library keeps copies
+// there. It returns the hold now ready, or nil if the copy is not available
+// or no hold wants it.
+func (a *holdAssigner) assignCopy(ctx context.Context, holdingID string) (*models.Hold, error) {
+	holding, err := a.holdings.GetHoldingByID(ctx, holdingID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get holding by ID: %w", err)
+	}
+	if holding.Status != models.HoldingStatusAvailable {
+		return nil, nil
+	}
+	waiting, err := a.holds.GetWaitingHolds(ctx, holding.BookID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get waiting holds: %w", err)
+	}
+
+	for _, hold := range waiting {
+		if !holdFilledBy(hold, holding.LibraryID) {
+			continue
+		}
+		pickup, err := a.libraries.GetLibraryByID(ctx, hold.PickupLibraryID)
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to get pickup library: %w", err)
+		}
+		now := time.Now()
+		assigned, err := a.holds.AssignHold(ctx, hold.ID, holding.ID, now, now.Add(days(pickup.HoldPickupDays)))
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to assign copy to hold: %w", err)
+		}
+		if assigned {
+			ready, err := a.holds.GetHoldByID(ctx, hold.ID)
+			if err != nil {
+				return nil, fmt.Errorf("service: failed to get hold by ID: %w", err)
+			}
+			return ready, nil
+		}
+		// The hold was closed or the copy taken in the meantime; only the
+		// former leaves the copy for the next hold.
+		holding, err = a.holdings.GetHoldingByID(ctx, holdingID)
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to get holding by ID: %w", err)
+		}
+		if holding.Status != models.HoldingStatusAvailable {
+			return nil, nil
+		}
+	}
+	return nil, nil
+}
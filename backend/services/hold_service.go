@@ -0,0 +1,375 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"book-recommendation-system/backend/models"
+	"book-recommendation-system/backend/repositories"
+)
+
+// HoldService defines the interface for placing and managing holds on books
+// and for the queues they form.
+type HoldService interface {
+	GetHoldByID(ctx context.Context, id string) (*models.Hold, error)
+	GetHolds(ctx context.Context, filter models.HoldFilter, page models.PageRequest) (*models.Page[models.Hold], error)
+	PlaceHold(ctx context.Context, hold *models.Hold) error
+	CancelHold(ctx context.Context, id string) (*models.Hold, error)
+	GetHoldPosition(ctx context.Context, id string) (*models.HoldPosition, error)
+	GetBookHoldQueue(ctx context.Context, bookID string) ([]models.Hold, error)
+	ExpireHolds(ctx context.Context) (*models.HoldExpiryRun, error)
+}
+
+// holdService implements HoldService.
+type holdService struct {
+	repo      repositories.HoldRepository
+	books     repositories.BookRepository
+	holdings  repositories.HoldingRepository
+	loans     repositories.LoanRepository
+	libraries repositories.LibraryRepository
+	assigner  *holdAssigner
+}
+
+// NewHoldService creates a new HoldService.
+func NewHoldService(repo repositories.HoldRepository, books repositories.BookRepository, holdings repositories.HoldingRepository, loans repositories.LoanRepository, libraries repositories.LibraryRepository) HoldService {
+	return &holdService{
+		repo:      repo,
+		books:     books,
+		holdings:  holdings,
+		loans:     loans,
+		libraries: libraries,
+		assigner:  &holdAssigner{holds: repo, holdings: holdings, libraries: libraries},
+	}
+}
+
+// isHoldStatus reports whether status is a known hold state.
+func isHoldStatus(status string) bool {
+	switch status {
+	case models.HoldStatusWaiting, models.HoldStatusReady, models.HoldStatusFulfilled, models.HoldStatusCancelled, models.HoldStatusExpired:
+		return true
+	}
+	return false
+}
+
+// GetHoldByID retrieves a hold by its ID, returning nil if there is none.
+func (s *holdService) GetHoldByID(ctx context.Context, id string) (*models.Hold, error) {
+	hold, err := s.repo.GetHoldByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get hold by ID: %w", err)
+	}
+	return hold, nil
+}
+
+// GetHolds retrieves a page of the holds matching a filter, oldest first.
+func (s *holdService) GetHolds(ctx context.Context, filter models.HoldFilter, page models.PageRequest) (*models.Page[models.Hold], error) {
+	for _, status := range filter.Statuses {
+		if !isHoldStatus(status) {
+			return nil, fmt.Errorf("service: unknown hold status %q: %w", status, ErrInvalidInput)
+		}
+	}
+	holds, err := s.repo.GetHolds(ctx, filter, page)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get holds: %w", err)
+	}
+	return holds, nil
+}
+
+// requireLibrary checks that a library a request refers to exists.
+func (s *holdService) requireLibrary(ctx context.Context, field, id string) error {
+	if _, err := s.libraries.GetLibraryByID(ctx, id); errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("service: %s %s does not exist: %w", field, id, ErrInvalidInput)
+	} else if err != nil {
+		return fmt.Errorf("service: failed to get library by ID: %w", err)
+	}
+	return nil
+}
+
+// PlaceHold joins the back of a book's queue for a copy from the hold's
+// library, or from any library if it has none, to be collected at its
+// pickup library (by default the hold's library). If a copy it can take is
+// available already, it goes straight to the hold shelf.
+func (s *holdService) PlaceHold(ctx context.Context, hold *models.Hold) error {
+	hold.UserID = strings.TrimSpace(hold.UserID)
+	if hold.UserID == "" || hold.BookID == "" {
+		return fmt.Errorf("service: user_id and book_id are required: %w", ErrInvalidInput)
+	}
+	if hold.PickupLibraryID == "" {
+		hold.PickupLibraryID = hold.LibraryID
+	}
+	if hold.PickupLibraryID == "" {
+		return fmt.Errorf("service: pickup_library_id is required: %w", ErrInvalidInput)
+	}
+	now := time.Now()
+	if hold.ExpiresAt != nil && !hold.ExpiresAt.After(now) {
+		return fmt.Errorf("service: expires_at must be in the future: %w", ErrInvalidInput)
+	}
+	if _, err := s.books.GetBookByID(ctx, hold.BookID); errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("service: book %s does not exist: %w", hold.BookID, ErrInvalidInput)
+	} else if err != nil {
+		return fmt.Errorf("service: failed to get book by ID: %w", err)
+	}
+	if hold.LibraryID != "" {
+		if err := s.requireLibrary(ctx, "library", hold.LibraryID); err != nil {
+			return err
+		}
+	}
+	if err := s.requireLibrary(ctx, "pickup library", hold.PickupLibraryID); err != nil {
+		return err
+	}
+	open, err := s.repo.GetOpenHold(ctx, hold.BookID, hold.UserID)
+	if err != nil {
+		return fmt.Errorf("service: failed to get open hold: %w", err)
+	}
+	if open != nil {
+		return fmt.Errorf("service: user %s already has hold %s on book %s: %w", hold.UserID, open.ID, hold.BookID, ErrInvalidInput)
+	}
+
+	hold.ID = newID()
+	hold.Status = models.HoldStatusWaiting
+	hold.CreatedAt = now
+	hold.HoldingID, hold.ReadyAt, hold.PickupBy, hold.ClosedAt = "", nil, nil, nil
+	if err := s.repo.CreateHold(ctx, hold); err != nil {
+		return fmt.Errorf("service: failed to create hold: %w", err)
+	}
+
+	copies, err := s.holdings.GetBookHoldings(ctx, hold.BookID)
+	if err != nil {
+		return fmt.Errorf("service: failed to get book holdings: %w", err)
+	}
+	for _, c := range copies {
+		if c.Status != models.HoldingStatusAvailable || !holdFilledBy(*hold, c.LibraryID) {
+			continue
+		}
+		ready, err := s.assigner.assignCopy(ctx, c.ID)
+		if err != nil {
+			return err
+		}
+		if ready != nil && ready.ID == hold.ID {
+			*hold = *ready
+			break
+		}
+	}
+	return nil
+}
+
+// CancelHold withdraws a waiting or ready hold. A copy waiting on the hold
+// shelf for it passes to the next hold in line. It returns the cancelled
+// hold, or nil if there is none with the ID.
+func (s *holdService) CancelHold(ctx context.Context, id string) (*models.Hold, error) {
+	existing, err := s.GetHoldByID(ctx, id)
+	if existing == nil || err != nil {
+		return nil, err
+	}
+	closed, err := s.repo.CloseHold(ctx, id, models.HoldStatusCancelled, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to cancel hold: %w", err)
+	}
+	if closed == nil {
+		return nil, fmt.Errorf("service: hold %s is already %s: %w", id, existing.Status, ErrInvalidInput)
+	}
+	if closed.Status == models.HoldStatusReady && closed.HoldingID != "" {
+		if _, err := s.assigner.assignCopy(ctx, closed.HoldingID); err != nil {
+			return nil, err
+		}
+	}
+	return s.GetHoldByID(ctx, id)
+}
+
+// GetBookHoldQueue retrieves a book's queue of waiting holds, first in line
+// first. It returns nil if the book does not exist.
+func (s *holdService) GetBookHoldQueue(ctx context.Context, bookID string) ([]models.Hold, error) {
+	if _, err := s.books.GetBookByID(ctx, bookID); errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("service: failed to get book by ID: %w", err)
+	}
+	holds, err := s.repo.GetWaitingHolds(ctx, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get waiting holds: %w", err)
+	}
+	if holds == nil {
+		holds = []models.Hold{}
+	}
+	return holds, nil
+}
+
+// holdsCompete reports whether two holds on a book can be filled by the same
+// copies.
+func holdsCompete(a, b models.Hold) bool {
+	return a.LibraryID == "" || b.LibraryID == "" || a.LibraryID == b.LibraryID
+}
+
+// GetHoldPosition reports where a waiting hold stands in its book's queue,
+// counting only the holds ahead that compete for the same copies, and
+// estimates when a copy will be ready for it. It returns nil if there is no
+// hold with the ID.
+func (s *holdService) GetHoldPosition(ctx context.Context, id string) (*models.HoldPosition, error) {
+	hold, err := s.GetHoldByID(ctx, id)
+	if hold == nil || err != nil {
+		return nil, err
+	}
+	position := &models.HoldPosition{HoldID: hold.ID, Status: hold.Status}
+	switch hold.Status {
+	case models.HoldStatusReady:
+		position.EstimatedReadyAt = hold.ReadyAt
+		return position, nil
+	case models.HoldStatusWaiting:
+	default:
+		return position, nil
+	}
+
+	waiting, err := s.repo.GetWaitingHolds(ctx, hold.BookID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get waiting holds: %w", err)
+	}
+	position.QueueLength = len(waiting)
+	ahead, queued := 0, false
+	for _, h := range waiting {
+		if h.ID == hold.ID {
+			queued = true
+			break
+		}
+		if holdsCompete(h, *hold) {
+			ahead++
+		}
+	}
+	if !queued {
+		// Expired, and waiting only to be closed.
+		return position, nil
+	}
+	position.Position = ahead + 1
+	if err := s.estimateReadyAt(ctx, *hold, position); err != nil {
+		return nil, err
+	}
+	return position, nil
+}
+
+// copyTurnaround is when a copy is next free to fill a hold and how long each
+// reader keeps it.
+type copyTurnaround struct {
+	freeAt time.Time
+	period time.Duration
+}
+
+// estimateReadyAt fills in the copies that can fill a hold and when one is
+// expected to be ready for it. Copies on loan come back on their due date
+// and copies on the hold shelf are assumed collected now; each hold ahead
+// then takes the first copy to come back and keeps it for a full loan
+// period.
+func (s *holdService) estimateReadyAt(ctx context.Context, hold models.Hold, position *models.HoldPosition) error {
+	copies, err := s.holdings.GetBookHoldings(ctx, hold.BookID)
+	if err != nil {
+		return fmt.Errorf("service: failed to get book holdings: %w", err)
+	}
+	dueAt, err := s.activeLoanDueDates(ctx, hold.BookID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	periods := make(map[string]time.Duration)
+	var turnarounds []copyTurnaround
+	for _, c := range copies {
+		if !holdFilledBy(hold, c.LibraryID) {
+			continue
+		}
+		period, ok := periods[c.LibraryID]
+		if !ok {
+			library, err := s.libraries.GetLibraryByID(ctx, c.LibraryID)
+			if err != nil {
+				return fmt.Errorf("service: failed to get library by ID: %w", err)
+			}
+			period = days(library.LoanPeriodDays)
+			periods[c.LibraryID] = period
+		}
+		switch c.Status {
+		case models.HoldingStatusAvailable:
+			turnarounds = append(turnarounds, copyTurnaround{freeAt: now, period: period})
+		case models.HoldingStatusOnLoan:
+			due, ok := dueAt[c.ID]
+			if !ok || due.Before(now) {
+				due = now
+			}
+			turnarounds = append(turnarounds, copyTurnaround{freeAt: due, period: period})
+		case models.HoldingStatusOnHold:
+			turnarounds = append(turnarounds, copyTurnaround{freeAt: now.Add(period), period: period})
+		}
+	}
+	position.EligibleCopies = len(turnarounds)
+	if len(turnarounds) == 0 {
+		return nil
+	}
+
+	for served := 1; ; served++ {
+		sort.Slice(turnarounds, func(i, j int) bool { return turnarounds[i].freeAt.Before(turnarounds[j].freeAt) })
+		if served == position.Position {
+			readyAt := turnarounds[0].freeAt
+			position.EstimatedReadyAt = &readyAt
+			return nil
+		}
+		turnarounds[0].freeAt = turnarounds[0].freeAt.Add(turnarounds[0].period)
+	}
+}
+
+// activeLoanDueDates maps each copy of a book that is out on loan to its due
+// date.
+func (s *holdService) activeLoanDueDates(ctx context.Context, bookID string) (map[string]time.Time, error) {
+	dueAt := make(map[string]time.Time)
+	filter := models.LoanFilter{BookID: bookID, Status: models.LoanStatusActive}
+	page := models.PageRequest{Limit: models.MaxPageLimit}
+	for {
+		loans, err := s.loans.GetLoans(ctx, filter, page)
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to get active loans: %w", err)
+		}
+		for _, l := range loans.Items {
+			dueAt[l.HoldingID] = l.DueAt
+		}
+		if loans.NextCursor == "" {
+			return dueAt, nil
+		}
+		page.Cursor = loans.NextCursor
+	}
+}
+
+// ExpireHolds closes the waiting holds past their expiry date and the ready
+// holds not collected by their pickup date. Copies left on the hold shelf
+// pass to the next hold in line.
+func (s *holdService) ExpireHolds(ctx context.Context) (*models.HoldExpiryRun, error) {
+	now := time.Now()
+	lapsed, err := s.repo.GetLapsedHolds(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get lapsed holds: %w", err)
+	}
+	run := &models.HoldExpiryRun{}
+	for _, h := range lapsed {
+		closed, err := s.repo.CloseHold(ctx, h.ID, models.HoldStatusExpired, now)
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to expire hold: %w", err)
+		}
+		if closed == nil {
+			continue
+		}
+		run.Expired++
+		if closed.Status != models.HoldStatusReady || closed.HoldingID == "" {
+			continue
+		}
+		run.CopiesReleased++
+		ready, err := s.assigner.assignCopy(ctx, closed.HoldingID)
+		if err != nil {
+			return nil, err
+		}
+		if ready != nil {
+			run.CopiesAssigned++
+		}
+	}
+	return run, nil
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"book-recommendation-system/backend/models"
+	"book-recommendation-system/backend/repositories"
+)
+
+// HoldingService defines the interface for managing the copies libraries
+// hold and reporting where books can be found.
+type HoldingService interface {
+	GetHoldingByID(ctx context.Context, id string) (*models.Holding, error)
+	GetHoldings(ctx context.Context, filter models.HoldingFilter, page models.PageRequest) (*models.Page[models.Holding], error)
+	CreateHolding(ctx context.Context, holding *models.Holding) error
+	UpdateHolding(ctx context.Context, holding *models.Holding) (*models.Holding, error)
+	DeleteHolding(ctx context.Context, id string) error
+	GetBookAvailability(ctx context.Context, bookID string) (*models.BookAvailability, error)
+	GetLibraryBooks(ctx context.Context, libraryID string, filter models.BookFilter, page models.PageRequest) (*models.Page[models.LibraryBook], error)
+}
+
+// holdingService implements HoldingService.
+type holdingService struct {
+	repo      repositories.HoldingRepository
+	books     repositories.BookRepository
+	libraries repositories.LibraryRepository
+	assigner  *holdAssigner
+}
+
+// NewHoldingService creates a new HoldingService.
+func NewHoldingService(repo repositories.HoldingRepository, books repositories.BookRepository, libraries repositories.LibraryRepository, holds repositories.HoldRepository) HoldingService {
+	return &holdingService{
+		repo:      repo,
+		books:     books,
+		libraries: libraries,
+		assigner:  &holdAssigner{holds: holds, holdings: repo, libraries: libraries},
+	}
+}
+
+// isHoldingStatus reports whether status is a known copy state.
+func isHoldingStatus(status string) bool {
+	switch status {
+	case models.HoldingStatusAvailable, models.HoldingStatusOnLoan, models.HoldingStatusOnHold, models.HoldingStatusLost, models.HoldingStatusInRepair:
+		return true
+	}
+	return false
+}
+
+// isCirculationStatus reports whether a copy state is entered and left only
+// through circulation: checking copies out and in, and filling holds.
+func isCirculationStatus(status string) bool {
+	return status == models.HoldingStatusOnLoan || status == models.HoldingStatusOnHold
+}
+
+// GetHoldingByID retrieves a holding by its ID, returning nil if there is none.
+func (s *holdingService) GetHoldingByID(ctx context.Context, id string) (*models.Holding, error) {
+	holding, err := s.repo.GetHoldingByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get holding by ID: %w", err)
+	}
+	return holding, nil
+}
+
+// GetHoldings retrieves a page of the holdings matching a filter.
+func (s *holdingService) GetHoldings(ctx context.Context, filter models.HoldingFilter, page models.PageRequest) (*models.Page[models.Holding], error) {
+	for _, status := range filter.Statuses {
+		if !isHoldingStatus(status) {
+			return nil, fmt.Errorf("service: unknown holding status %q: %w", status, ErrInvalidInput)
+		}
+	}
+	holdings, err := s.repo.GetHoldings(ctx, filter, page)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get holdings: %w", err)
+	}
+	return holdings, nil
+}
+
+// validateHolding checks a holding before it is stored: its library and book
+// must exist, its status must be known (available by default) and its
+// barcode, if any, must not label another copy.
+func (s *holdingService) validateHolding(ctx context.Context, holding *models.Holding) error {
+	holding.Barcode = strings.TrimSpace(holding.Barcode)
+	holding.ShelfLocation = strings.TrimSpace(holding.ShelfLocation)
+	if holding.LibraryID == "" || holding.BookID == "" {
+		return fmt.Errorf("service: library_id and book_id are required: %w", ErrInvalidInput)
+	}
+	if holding.Status == "" {
+		holding.Status = models.HoldingStatusAvailable
+	}
+	if !isHoldingStatus(holding.Status) {
+		return fmt.Errorf("service: unknown holding status %q: %w", holding.Status, ErrInvalidInput)
+	}
+
+	if _, err := s.libraries.GetLibraryByID(ctx, holding.LibraryID); errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("service: library %s does not exist: %w", holding.LibraryID, ErrInvalidInput)
+	} else if err != nil {
+		return fmt.Errorf("service: failed to get library by ID: %w", err)
+	}
+	if _, err := s.books.GetBookByID(ctx, holding.BookID); errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("service: book %s does not exist: %w", holding.BookID, ErrInvalidInput)
+	} else if err != nil {
+		return fmt.Errorf("service: failed to get book by ID: %w", err)
+	}
+	if holding.Barcode != "" {
+		labelled, err := s.repo.GetHoldingByBarcode(ctx, holding.Barcode)
+		if err != nil {
+			return fmt.Errorf("service: failed to get holding by barcode: %w", err)
+		}
+		if labelled != nil && labelled.ID != holding.ID {
+			return fmt.Errorf("service: barcode %q already labels holding %s: %w", holding.Barcode, labelled.ID, ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// CreateHolding adds a copy of a book to a library's collection. An
+// available copy goes to the first hold waiting for it.
+func (s *holdingService) CreateHolding(ctx context.Context, holding *models.Holding) error {
+	if err := s.validateHolding(ctx, holding); err != nil {
+		return err
+	}
+	if isCirculationStatus(holding.Status) {
+		return fmt.Errorf("service: a copy goes %s only through circulation: %w", holding.Status, ErrInvalidInput)
+	}
+	if holding.ID == "" {
+		holding.ID = newID()
+	}
+	if err := s.repo.CreateHolding(ctx, holding); err != nil {
+		return fmt.Errorf("service: failed to create holding: %w", err)
+	}
+	if _, err := s.assigner.assignCopy(ctx, holding.ID); err != nil {
+		return err
+	}
+	return s.reloadHolding(ctx, holding)
+}
+
+// UpdateHolding updates a copy's library, book, barcode, shelf location or
+// status, except whether it is on loan or on hold; a copy on loan or on hold
+// keeps its book and library. A copy made available goes to the first hold
+// waiting for it. It returns the updated holding, or nil if there is none
+// with the ID.
+func (s *holdingService) UpdateHolding(ctx context.Context, holding *models.Holding) (*models.Holding, error) {
+	existing, err := s.GetHoldingByID(ctx, holding.ID)
+	if existing == nil || err != nil {
+		return nil, err
+	}
+	if err := s.validateHolding(ctx, holding); err != nil {
+		return nil, err
+	}
+	if holding.Status != existing.Status && (isCirculationStatus(existing.Status) || isCirculationStatus(holding.Status)) {
+		return nil, fmt.Errorf("service: a copy goes on and off loan or hold only through circulation: %w", ErrInvalidInput)
+	}
+	if isCirculationStatus(existing.Status) && (holding.BookID != existing.BookID || holding.LibraryID != existing.LibraryID) {
+		return nil, fmt.Errorf("service: copy %s cannot change book or library while it is %s: %w", holding.ID, existing.Status, ErrInvalidInput)
+	}
+	updated, err := s.repo.UpdateHolding(ctx, holding, existing.Status)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to update holding: %w", err)
+	}
+	if !updated {
+		return nil, fmt.Errorf("service: copy %s changed status meanwhile: %w", holding.ID, ErrInvalidInput)
+	}
+	if _, err := s.assigner.assignCopy(ctx, holding.ID); err != nil {
+		return nil, err
+	}
+	if err := s.reloadHolding(ctx, holding); err != nil {
+		return nil, err
+	}
+	return holding, nil
+}
+
+// reloadHolding refreshes a stored holding's read-only fields.
+func (s *holdingService) reloadHolding(ctx context.Context, holding *models.Holding) error {
+	stored, err := s.repo.GetHoldingByID(ctx, holding.ID)
+	if err != nil {
+		return fmt.Errorf("service: failed to get holding by ID: %w", err)
+	}
+	*holding = *stored
+	return nil
+}
+
+// DeleteHolding removes a copy from its library's collection, unless it is
+// on loan or on hold or has ever been lent.
+func (s *holdingService) DeleteHolding(ctx context.Context, id string) error {
+	existing, err := s.GetHoldingByID(ctx, id)
+	if existing == nil || err != nil {
+		return err
+	}
+	if isCirculationStatus(existing.Status) {
+		return fmt.Errorf("service: copy %s cannot be deleted while it is %s: %w", id, existing.Status, ErrInvalidInput)
+	}
+	deleted, err := s.repo.DeleteHolding(ctx, id)
+	if err != nil {
+		return fmt.Errorf("service: failed to delete holding: %w", err)
+	}
+	if !deleted {
+		return fmt.Errorf("service: copy %s is in circulation or has been lent: %w", id, ErrInvalidInput)
+	}
+	return nil
+}
+
+// GetBookAvailability lists every library holding a copy of a book with its
+// copies and how many can be borrowed now. It returns nil if the book does
+// not exist.
+func (s *holdingService) GetBookAvailability(ctx context.Context, bookID string) (*models.BookAvailability, error) {
+	if _, err := s.books.GetBookByID(ctx, bookID); errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("service: failed to get book by ID: %w", err)
+	}
+	holdings, err := s.repo.GetBookHoldings(ctx, bookID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get book holdings: %w", err)
+	}
+
+	availability := &models.BookAvailability{BookID: bookID, Libraries: []models.LibraryAvailability{}}
+	// Holdings come grouped by library.
+	for _, h := range holdings {
+		n := len(availability.Libraries)
+		if n == 0 || availability.Libraries[n-1].LibraryID != h.LibraryID {
+			availability.Libraries = append(availability.Libraries, models.LibraryAvailability{LibraryID: h.LibraryID, LibraryName: h.LibraryName})
+			n++
+		}
+		library := &availability.Libraries[n-1]
+		library.Copies = append(library.Copies, h)
+		library.TotalCopies++
+		availability.TotalCopies++
+		if h.Status == models.HoldingStatusAvailable {
+			library.AvailableCopies++
+			availability.AvailableCopies++
+		}
+	}
+	return availability, nil
+}
+
+// GetLibraryBooks retrieves a page of the books a library holds that match a
+// catalogue filter, each with the library's copies of it. Filtering on
+// availability considers only the library's copies. It returns nil if the
+// library does not exist.
+func (s *holdingService) GetLibraryBooks(ctx context.Context, libraryID string, filter models.BookFilter, page models.PageRequest) (*models.Page[models.LibraryBook], error) {
+	if _, err := s.libraries.GetLibraryByID(ctx, libraryID); errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("service: failed to get library by ID: %w", err)
+	}
+	if err := normalizeBookFilter(&filter); err != nil {
+		return nil, err
+	}
+	filter.LibraryID = libraryID
+	books, err := s.books.QueryBooks(ctx, filter, page)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to query library books: %w", err)
+	}
+
+	ids := make([]string, len(books.Items))
+	for i, b := range books.Items {
+		ids[i] = b.ID
+	}
+	holdings, err := s.repo.GetLibraryHoldings(ctx, libraryID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get library holdings: %w", err)
+	}
+	copies := make(map[string][]models.Holding)
+	for _, h := range holdings {
+		copies[h.BookID] = append(copies[h.BookID], h)
+	}
+
+	result := &models.Page[models.LibraryBook]{
+		Items:      make([]models.LibraryBook, len(books.Items)),
+		NextCursor: books.NextCursor,
+		PrevCursor: books.PrevCursor,
+	}
+	for i, b := range books.Items {
+		item := models.LibraryBook{Book: b, Copies: copies[b.ID]}
+		for _, h := range item.Copies {
+			item.TotalCopies++
+			if h.Status == models.HoldingStatusAvailable {
+				item.AvailableCopies++
+			}
+		}
+		result.Items[i] = item
+	}
+	return result, nil
+}
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"book-recommendation-system/backend/marc"
+	"book-recommendation-system/backend/models"
+	"book-recommendation-system/backend/onix"
+)
+
+// Catalogue import formats.
+const (
+	ImportFormatCSV     = "csv"
+	ImportFormatJSONL   = "jsonl"
+	ImportFormatMARC    = "marc"    // MARC 21 in ISO 2709 transmission format
+	ImportFormatMARCXML = "marcxml" // MARC 21 in MARCXML
+	ImportFormatONIX    = "onix"    // ONIX for Books 3.0 with reference tags
+)
+
+// maxJSONLLineSize bounds a single JSON Lines record.
+const maxJSONLLineSize = 1 << 20
+
+// RecordReader streams catalogue records from an import source. Read returns
+// io.EOF after the last record. A *RowError means only that row is unreadable
+// and reading can continue; any other error ends the import.
+type RecordReader interface {
+	Read() (models.ImportRecord, error)
+}
+
+// RecordReadCloser is a RecordReader over resources that must be released.
+type RecordReadCloser interface {
+	RecordReader
+	io.Closer
+}
+
+// RowError reports a source row that could not be parsed.
+type RowError struct {
+	Row int
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// NewRecordReader returns a RecordReader for the given format.
+func NewRecordReader(format string, r io.Reader) (RecordReader, error) {
+	switch format {
+	case ImportFormatCSV:
+		return newCSVRecordReader(r)
+	case ImportFormatJSONL:
+		return newJSONLRecordReader(r), nil
+	case ImportFormatMARC:
+		return &marcRecordReader{r: marc.NewReader(r)}, nil
+	case ImportFormatMARCXML:
+		return &marcRecordReader{r: marc.NewXMLReader(r)}, nil
+	case ImportFormatONIX:
+		return &onixRecordReader{r: onix.NewReader(r)}, nil
+	default:
+		return nil, fmt.Errorf("service: unknown import format %q: %w", format, ErrInvalidInput)
+	}
+}
+
+// csvRecordReader reads CSV with a header row naming the columns. Columns are
+// matched case-insensitively; unknown columns are ignored. "author" and
+// "authors" hold a free-text list of names, as do "genre" and "genres";
+// "subjects" holds tags separated by commas, semicolons or bars; "copies" is
+// the number of copies a library import holds, 1 by default.
+type csvRecordReader struct {
+	r       *csv.Reader
+	columns map[string]int
+	row     int
+}
+
+func newCSVRecordReader(r io.Reader) (*csvRecordReader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("service: CSV import has no header row: %w", ErrInvalidInput)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to read CSV header: %w: %w", err, ErrInvalidInput)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, dup := columns[name]; !dup {
+			columns[name] = i
+		}
+	}
+	if _, ok := columns["isbn"]; !ok {
+		return nil, fmt.Errorf("service: CSV header has no isbn column: %w", ErrInvalidInput)
+	}
+	return &csvRecordReader{r: cr, columns: columns}, nil
+}
+
+func (c *csvRecordReader) field(fields []string, names ...string) string {
+	for _, name := range names {
+		if i, ok := c.columns[name]; ok && i < len(fields) {
+			return strings.TrimSpace(fields[i])
+		}
+	}
+	return ""
+}
+
+func (c *csvRecordReader) Read() (models.ImportRecord, error) {
+	fields, err := c.r.Read()
+	if errors.Is(err, io.EOF) {
+		return models.ImportRecord{}, io.EOF
+	}
+	c.row++
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return models.ImportRecord{}, &RowError{Row: c.row, Err: err}
+		}
+		return models.ImportRecord{}, fmt.Errorf("service: failed to read CSV: %w", err)
+	}
+
+	rec := models.ImportRecord{
+		Row: c.row,
+		Book: models.Book{
+			Title:         c.field(fields, "title"),
+			ISBN:          c.field(fields, "isbn"),
+			Description:   c.field(fields, "description"),
+			CoverImageURL: c.field(fields, "cover_image_url"),
+			Series:        c.field(fields, "series"),
+			Language:      c.field(fields, "language"),
+			Publisher:     c.field(fields, "publisher"),
+			Format:        c.field(fields, "format"),
+			Edition:       c.field(fields, "edition"),
+			Subjects:      splitTags(c.field(fields, "subjects", "subject")),
+		},
+		Contributors: authorContributors(splitNames(c.field(fields, "authors", "author"))),
+		GenreNames:   splitGenres(c.field(fields, "genres", "genre")),
+		Copies:       1,
+	}
+	if s := c.field(fields, "publication_year"); s != "" {
+		if rec.Book.PublicationYear, err = strconv.Atoi(s); err != nil {
+			return rec, &RowError{Row: c.row, Err: fmt.Errorf("invalid publication_year %q", s)}
+		}
+	}
+	if s := c.field(fields, "series_position"); s != "" {
+		if rec.Book.SeriesPosition, err = strconv.ParseFloat(s, 64); err != nil {
+			return rec, &RowError{Row: c.row, Err: fmt.Errorf("invalid series_position %q", s)}
+		}
+	}
+	if s := c.field(fields, "page_count", "pages"); s != "" {
+		if rec.Book.PageCount, err = strconv.Atoi(s); err != nil {
+			return rec, &RowError{Row: c.row, Err: fmt.Errorf("invalid page_count %q", s)}
+		}
+	}
+	if s := c.field(fields, "copies"); s != "" {
+		if rec.Copies, err = strconv.Atoi(s); err != nil || rec.Copies < 0 {
+			return rec, &RowError{Row: c.row, Err: fmt.Errorf("invalid copies %q", s)}
+		}
+	}
+	return rec, nil
+}
+
+// jsonlRecord is one line of a JSON Lines import. Authors and genres may be
+// given as arrays of names or as free-text lists in author and genre.
+type jsonlRecord struct {
+	Title           string   `json:"title"`
+	Author          string   `json:"author"`
+	Authors         []string `json:"authors"`
+	ISBN            string   `json:"isbn"`
+	Description     string   `json:"description"`
+	CoverImageURL   string   `json:"cover_image_url"`
+	Genre           string   `json:"genre"`
+	Genres          []string `json:"genres"`
+	PublicationYear int      `json:"publication_year"`
+	Series          string   `json:"series"`
+	SeriesPosition  float64  `json:"series_position"`
+	Language        string   `json:"language"`
+	PageCount       int      `json:"page_count"`
+	Publisher       string   `json:"publisher"`
+	Format          string   `json:"format"`
+	Edition         string   `json:"edition"`
+	Subjects        []string `json:"subjects"`
+	Copies          *int     `json:"copies"`
+}
+
+// jsonlRecordReader reads one JSON object per line, skipping blank lines.
+type jsonlRecordReader struct {
+	s   *bufio.Scanner
+	row int
+}
+
+func newJSONLRecordReader(r io.Reader) *jsonlRecordReader {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), maxJSONLLineSize)
+	return &jsonlRecordReader{s: s}
+}
+
+func (j *jsonlRecordReader) Read() (models.ImportRecord, error) {
+	for j.s.Scan() {
+		j.row++
+		line := strings.TrimSpace(j.s.Text())
+		if line == "" {
+			continue
+		}
+
+		var raw jsonlRecord
+		if err := json.Unmarshal([]byte(line), &raw); err != nil {
+			return models.ImportRecord{}, &RowError{Row: j.row, Err: fmt.Errorf("invalid JSON: %v", err)}
+		}
+		rec := models.ImportRecord{
+			Row: j.row,
+			Book: models.Book{
+				Title:           strings.TrimSpace(raw.Title),
+				ISBN:            strings.TrimSpace(raw.ISBN),
+				Description:     raw.Description,
+				CoverImageURL:   raw.CoverImageURL,
+				PublicationYear: raw.PublicationYear,
+				Series:          strings.TrimSpace(raw.Series),
+				SeriesPosition:  raw.SeriesPosition,
+				Language:        strings.TrimSpace(raw.Language),
+				PageCount:       raw.PageCount,
+				Publisher:       raw.Publisher,
+				Format:          raw.Format,
+				Edition:         raw.Edition,
+				Subjects:        trimNames(raw.Subjects),
+			},
+			Contributors: authorContributors(trimNames(raw.Authors)),
+			GenreNames:   trimNames(raw.Genres),
+			Copies:       1,
+		}
+		if raw.Copies != nil {
+			if *raw.Copies < 0 {
+				return rec, &RowError{Row: j.row, Err: fmt.Errorf("invalid copies %d", *raw.Copies)}
+			}
+			rec.Copies = *raw.Copies
+		}
+		if len(rec.Contributors) == 0 {
+			rec.Contributors = authorContributors(splitNames(raw.Author))
+		}
+		if len(rec.GenreNames) == 0 {
+			rec.GenreNames = splitGenres(raw.Genre)
+		}
+		return rec, nil
+	}
+	if err := j.s.Err(); err != nil {
+		return models.ImportRecord{}, fmt.Errorf("service: failed to read JSON Lines: %w", err)
+	}
+	return models.ImportRecord{}, io.EOF
+}
+
+// authorContributors credits each of names as an author.
+func authorContributors(names []string) []models.ImportContributor {
+	contributors := make([]models.ImportContributor, len(names))
+	for i, name := range names {
+		contributors[i] = models.ImportContributor{Name: name, Role: models.AuthorRoleAuthor}
+	}
+	return contributors
+}
+
+// splitTags splits a free-text list of tags separated by commas, semicolons
+// or bars.
+func splitTags(list string) []string {
+	return trimNames(strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == ';' || r == '|' }))
+}
+
+// trimNames trims names and drops empty ones.
+func trimNames(names []string) []string {
+	trimmed := make([]string, 0, len(names))
+	for _, n := range names {
+		if n = strings.TrimSpace(n); n != "" {
+			trimmed = append(trimmed, n)
+		}
+	}
+	return trimmed
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"book-recommendation-system/backend/isbn"
+	"book-recommendation-system/backend/models"
+	"book-recommendation-system/backend/repositories"
+)
+
+// ISBNBackfillReport summarises a run of ISBNBackfiller.
+type ISBNBackfillReport struct {
+	BooksNormalized int           `json:"books_normalized"`
+	BooksMerged     []ISBNMerge   `json:"books_merged"`
+	InvalidISBNs    []InvalidISBN `json:"invalid_isbns"`
+	BooksUnchanged  int           `json:"books_unchanged"` // already normalised
+}
+
+// ISBNMerge records a book merged into the book already stored under its
+// normalised ISBN.
+type ISBNMerge struct {
+	BookID     string `json:"book_id"`
+	ISBN       string `json:"isbn"`
+	SurvivorID string `json:"survivor_id"`
+}
+
+// InvalidISBN records a book whose ISBN cannot be normalised, for review.
+type InvalidISBN struct {
+	BookID string `json:"book_id"`
+	ISBN   string `json:"isbn"`
+	Reason string `json:"reason"`
+}
+
+// ISBNBackfiller rewrites the ISBNs of books stored before ISBNs were
+// normalised as bare ISBN-13s.
+type ISBNBackfiller struct {
+	books      repositories.BookRepository
+	duplicates DuplicateService
+}
+
+// NewISBNBackfiller creates a new ISBNBackfiller.
+func NewISBNBackfiller(books repositories.BookRepository, duplicates DuplicateService) *ISBNBackfiller {
+	return &ISBNBackfiller{books: books, duplicates: duplicates}
+}
+
+// Run normalises the ISBN of every book. A book whose normalised ISBN is
+// already another book's is the same edition stored twice, and is merged
+// into that book. Books with invalid ISBNs are left as they are and
+// reported.
+func (b *ISBNBackfiller) Run(ctx context.Context) (*ISBNBackfillReport, error) {
+	report := &ISBNBackfillReport{BooksMerged: []ISBNMerge{}, InvalidISBNs: []InvalidISBN{}}
+	page := models.PageRequest{Limit: models.MaxPageLimit}
+	for {
+		books, err := b.books.GetAllBooks(ctx, page)
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to list books: %w", err)
+		}
+		for _, book := range books.Items {
+			if err := b.normalizeBook(ctx, book, report); err != nil {
+				return nil, err
+			}
+		}
+		if books.NextCursor == "" {
+			return report, nil
+		}
+		page.Cursor = books.NextCursor
+	}
+}
+
+func (b *ISBNBackfiller) normalizeBook(ctx context.Context, book models.Book, report *ISBNBackfillReport) error {
+	normalized, err := isbn.Normalize(book.ISBN)
+	if err != nil {
+		report.InvalidISBNs = append(report.InvalidISBNs, InvalidISBN{BookID: book.ID, ISBN: book.ISBN, Reason: err.Error()})
+		return nil
+	}
+	if normalized == book.ISBN {
+		report.BooksUnchanged++
+		return nil
+	}
+
+	twin, err := b.books.GetBookByISBN(ctx, normalized)
+	if err != nil {
+		return fmt.Errorf("service: failed to get book by ISBN: %w", err)
+	}
+	if twin != nil {
+		if _, err := b.duplicates.MergeBooks(ctx, models.BookMergeRequest{SurvivorID: twin.ID, DuplicateIDs: []string{book.ID}}); err != nil {
+			return fmt.Errorf("service: failed to merge book %s into %s: %w", book.ID, twin.ID, err)
+		}
+		report.BooksMerged = append(report.BooksMerged, ISBNMerge{BookID: book.ID, ISBN: book.ISBN, SurvivorID: twin.ID})
+		return nil
+	}
+	if err := b.books.SetBookISBN(ctx, book.ID, normalized); err != nil {
+		return fmt.Errorf("service: failed to set book ISBN: %w", err)
+	}
+	report.BooksNormalized++
+	return nil
+}
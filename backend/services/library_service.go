@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
+	"math"
 
 	"book-recommendation-system/backend/models"
 	"book-recommendation-system/backend/repositories"
@@ -11,12 +12,32 @@ import (
 // LibraryService defines the interface for library-related business logic.
 type LibraryService interface {
 	GetLibraryByID(ctx context.Context, id string) (*models.Library, error)
-	GetAllLibraries(ctx context.Context) ([]models.Library, error)
+	GetAllLibraries(ctx context.Context, page models.PageRequest) (*models.Page[models.Library], error)
+	GetNearbyLibraries(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]models.NearbyLibrary, error)
 	CreateLibrary(ctx context.Context, library *models.Library) error
 	UpdateLibrary(ctx context.Context, library *models.Library) error
 	DeleteLibrary(ctx context.Context, id string) error
 }
 
+const (
+	// defaultNearbyLibraryRadiusKm is how far away libraries are searched for
+	// when no radius is given.
+	defaultNearbyLibraryRadiusKm = 10.0
+	// maxNearbyLibraryRadiusKm caps the search radius, beyond which libraries
+	// are no longer nearby and the search would scan most of the table.
+	maxNearbyLibraryRadiusKm = 500.0
+	// defaultNearbyLibraries and maxNearbyLibraries bound how many nearby
+	// libraries are returned.
+	defaultNearbyLibraries = 20
+	maxNearbyLibraries     = 100
+	// defaultLoanPeriodDays, defaultMaxRenewals and defaultHoldPickupDays are
+	// a library's loan period, renewal limit and hold shelf time when none
+	// are given.
+	defaultLoanPeriodDays = 21
+	defaultMaxRenewals    = 2
+	defaultHoldPickupDays = 7
+)
+
 // libraryService implements LibraryService.
 type libraryService struct {
 	repo repositories.LibraryRepository
@@ -36,18 +57,79 @@ func (s *libraryService) GetLibraryByID(ctx context.Context, id string) (*models
 	return library, nil
 }
 
-// GetAllLibraries retrieves all libraries using the repository.
-func (s *libraryService) GetAllLibraries(ctx context.Context) ([]models.Library, error) {
-	libraries, err := s.repo.GetAllLibraries(ctx)
+// GetAllLibraries retrieves a page of all libraries using the repository.
+func (s *libraryService) GetAllLibraries(ctx context.Context, page models.PageRequest) (*models.Page[models.Library], error) {
+	libraries, err := s.repo.GetAllLibraries(ctx, page)
 	if err != nil {
 		return nil, fmt.Errorf("service: failed to get all libraries: %w", err)
 	}
 	return libraries, nil
 }
 
+// GetNearbyLibraries retrieves the libraries within radiusKm of (lat, lng),
+// nearest first, with their great-circle distances. A zero radius or limit
+// means the default; larger ones are capped.
+func (s *libraryService) GetNearbyLibraries(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]models.NearbyLibrary, error) {
+	for _, v := range []float64{lat, lng, radiusKm} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, fmt.Errorf("service: coordinates and radius must be finite numbers, got %g: %w", v, ErrInvalidInput)
+		}
+	}
+	if lat < -90 || lat > 90 {
+		return nil, fmt.Errorf("service: latitude must be between -90 and 90, got %g: %w", lat, ErrInvalidInput)
+	}
+	if lng < -180 || lng > 180 {
+		return nil, fmt.Errorf("service: longitude must be between -180 and 180, got %g: %w", lng, ErrInvalidInput)
+	}
+	if radiusKm < 0 {
+		return nil, fmt.Errorf("service: radius must not be negative, got %g: %w", radiusKm, ErrInvalidInput)
+	}
+	if limit < 0 {
+		return nil, fmt.Errorf("service: limit must not be negative, got %d: %w", limit, ErrInvalidInput)
+	}
+	if radiusKm == 0 {
+		radiusKm = defaultNearbyLibraryRadiusKm
+	}
+	radiusKm = min(radiusKm, maxNearbyLibraryRadiusKm)
+	if limit == 0 {
+		limit = defaultNearbyLibraries
+	}
+	limit = min(limit, maxNearbyLibraries)
+
+	libraries, err := s.repo.GetLibrariesNear(ctx, lat, lng, radiusKm, limit)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get nearby libraries: %w", err)
+	}
+	if libraries == nil {
+		libraries = []models.NearbyLibrary{}
+	}
+	return libraries, nil
+}
+
+// validateCirculationPolicy checks a library's circulation policy,
+// defaulting its loan period, renewal limit and hold shelf time.
+func validateCirculationPolicy(library *models.Library) error {
+	if library.MaxRenewals == nil {
+		maxRenewals := defaultMaxRenewals
+		library.MaxRenewals = &maxRenewals
+	}
+	if library.LoanPeriodDays < 0 || *library.MaxRenewals < 0 || library.HoldPickupDays < 0 {
+		return fmt.Errorf("service: loan_period_days, max_renewals and hold_pickup_days must not be negative: %w", ErrInvalidInput)
+	}
+	if library.LoanPeriodDays == 0 {
+		library.LoanPeriodDays = defaultLoanPeriodDays
+	}
+	if library.HoldPickupDays == 0 {
+		library.HoldPickupDays = defaultHoldPickupDays
+	}
+	return nil
+}
+
 // CreateLibrary creates a new library using the repository.
 func (s *libraryService) CreateLibrary(ctx context.Context, library *models.Library) error {
-	// Add any business logic/validation before creating a library
+	if err := validateCirculationPolicy(library); err != nil {
+		return err
+	}
 	err := s.repo.CreateLibrary(ctx, library)
 	if err != nil {
 		return fmt.Errorf("service: failed to create library: %w", err)
@@ -57,7 +139,9 @@ func (s *libraryService) CreateLibrary(ctx context.Context, library *models.Libr
 
 // UpdateLibrary updates an existing library using the repository.
 func (s *libraryService) UpdateLibrary(ctx context.Context, library *models.Library) error {
-	// Add any business logic/validation before updating a library
+	if err := validateCirculationPolicy(library); err != nil {
+		return err
+	}
 	err := s.repo.UpdateLibrary(ctx, library)
 	if err != nil {
 		return fmt.Errorf("service: failed to update library: %w", err)
@@ -65,12 +149,15 @@ func (s *libraryService) UpdateLibrary(ctx context.Context, library *models.Libr
 	return nil
 }
 
-// DeleteLibrary deletes a library by its ID using the repository.
+// DeleteLibrary deletes a library by its ID using the repository, unless it
+// has loans or holds.
 func (s *libraryService) DeleteLibrary(ctx context.Context, id string) error {
-	// Add any business logic/validation before deleting a library
-	err := s.repo.DeleteLibrary(ctx, id)
+	deleted, err := s.repo.DeleteLibrary(ctx, id)
 	if err != nil {
 		return fmt.Errorf("service: failed to delete library: %w", err)
 	}
+	if !deleted {
+		return fmt.Errorf("service: library %s has loans or holds and cannot be deleted: %w", id, ErrInvalidInput)
+	}
 	return nil
 }
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"book-recommendation-system/backend/models"
+	"book-recommendation-system/backend/repositories"
+)
+
+// LoanService defines the interface for circulation: lending copies to
+// users, renewing and returning them, and finding overdue loans.
+type LoanService interface {
+	GetLoanByID(ctx context.Context, id string) (*models.Loan, error)
+	GetLoans(ctx context.Context, filter models.LoanFilter, page models.PageRequest) (*models.Page[models.Loan], error)
+	CheckOut(ctx context.Context, req models.CheckOutRequest) (*models.Loan, error)
+	RenewLoan(ctx context.Context, id string) (*models.Loan, error)
+	CheckIn(ctx context.Context, id string) (*models.CheckInResult, error)
+	CheckInByBarcode(ctx context.Context, barcode string) (*models.CheckInResult, error)
+}
+
+// loanService implements LoanService.
+type loanService struct {
+	repo      repositories.LoanRepository
+	holdings  repositories.HoldingRepository
+	libraries repositories.LibraryRepository
+	holds     repositories.HoldRepository
+	assigner  *holdAssigner
+}
+
+// NewLoanService creates a new LoanService.
+func NewLoanService(repo repositories.LoanRepository, holdings repositories.HoldingRepository, libraries repositories.LibraryRepository, holds repositories.HoldRepository) LoanService {
+	return &loanService{
+		repo:      repo,
+		holdings:  holdings,
+		libraries: libraries,
+		holds:     holds,
+		assigner:  &holdAssigner{holds: holds, holdings: holdings, libraries: libraries},
+	}
+}
+
+// GetLoanByID retrieves a loan by its ID, returning nil if there is none.
+func (s *loanService) GetLoanByID(ctx context.Context, id string) (*models.Loan, error) {
+	loan, err := s.repo.GetLoanByID(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get loan by ID: %w", err)
+	}
+	return loan, nil
+}
+
+// GetLoans retrieves a page of the loans matching a filter, soonest due
+// first.
+func (s *loanService) GetLoans(ctx context.Context, filter models.LoanFilter, page models.PageRequest) (*models.Page[models.Loan], error) {
+	switch filter.Status {
+	case "", models.LoanStatusActive, models.LoanStatusOverdue, models.LoanStatusReturned:
+	default:
+		return nil, fmt.Errorf("service: unknown loan status %q: %w", filter.Status, ErrInvalidInput)
+	}
+	loans, err := s.repo.GetLoans(ctx, filter, page)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get loans: %w", err)
+	}
+	return loans, nil
+}
+
+// CheckOut lends a copy to a user until the end of its library's loan
+// period, and records that the user borrowed the book so that
+// recommendations learn from it. The copy must be available, or on the hold
+// shelf for the user. Borrowing a book closes the user's hold on it.
+func (s *loanService) CheckOut(ctx context.Context, req models.CheckOutRequest) (*models.Loan, error) {
+	req.UserID = strings.TrimSpace(req.UserID)
+	if req.UserID == "" {
+		return nil, fmt.Errorf("service: user_id is required: %w", ErrInvalidInput)
+	}
+	holding, err := s.findCopy(ctx, req.HoldingID, req.Barcode)
+	if err != nil {
+		return nil, err
+	}
+	var holdID string
+	switch holding.Status {
+	case models.HoldingStatusAvailable:
+	case models.HoldingStatusOnHold:
+		hold, err := s.holds.GetReadyHoldByHolding(ctx, holding.ID)
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to get ready hold: %w", err)
+		}
+		if hold == nil || hold.UserID != req.UserID {
+			return nil, fmt.Errorf("service: copy %s is on the hold shelf for another reader: %w", holding.ID, ErrInvalidInput)
+		}
+		holdID = hold.ID
+	default:
+		return nil, fmt.Errorf("service: copy %s is %s, not available: %w", holding.ID, holding.Status, ErrInvalidInput)
+	}
+	library, err := s.libraries.GetLibraryByID(ctx, holding.LibraryID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get library by ID: %w", err)
+	}
+
+	now := time.Now()
+	loan := &models.Loan{
+		ID:           newID(),
+		HoldingID:    holding.ID,
+		BookID:       holding.BookID,
+		LibraryID:    holding.LibraryID,
+		UserID:       req.UserID,
+		CheckedOutAt: now,
+		DueAt:        now.Add(days(library.LoanPeriodDays)),
+	}
+	borrow := &models.UserInteraction{
+		ID:              newID(),
+		UserID:          req.UserID,
+		BookID:          holding.BookID,
+		InteractionType: models.InteractionTypeBorrow,
+		Timestamp:       now,
+	}
+	ok, err := s.repo.CheckOut(ctx, loan, borrow, holdID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to check out copy: %w", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("service: copy %s is no longer available: %w", holding.ID, ErrInvalidInput)
+	}
+	if holdID == "" {
+		if err := s.closeOpenHold(ctx, loan.BookID, loan.UserID, now); err != nil {
+			return nil, err
+		}
+	}
+	return s.GetLoanByID(ctx, loan.ID)
+}
+
+// closeOpenHold marks a user's hold on a book they borrowed some other copy
+// of as fulfilled. A copy already on the hold shelf for them passes to the
+// next hold in line.
+func (s *loanService) closeOpenHold(ctx context.Context, bookID, userID string, now time.Time) error {
+	open, err := s.holds.GetOpenHold(ctx, bookID, userID)
+	if open == nil || err != nil {
+		return err
+	}
+	closed, err := s.holds.CloseHold(ctx, open.ID, models.HoldStatusFulfilled, now)
+	if err != nil {
+		return fmt.Errorf("service: failed to fulfil hold: %w", err)
+	}
+	if closed != nil && closed.Status == models.HoldStatusReady && closed.HoldingID != "" {
+		if _, err := s.assigner.assignCopy(ctx, closed.HoldingID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findCopy looks a copy up by its holding ID or, failing that, its barcode.
+func (s *loanService) findCopy(ctx context.Context, holdingID, barcode string) (*models.Holding, error) {
+	barcode = strings.TrimSpace(barcode)
+	switch {
+	case holdingID != "":
+		holding, err := s.holdings.GetHoldingByID(ctx, holdingID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("service: holding %s does not exist: %w", holdingID, ErrInvalidInput)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to get holding by ID: %w", err)
+		}
+		return holding, nil
+	case barcode != "":
+		holding, err := s.holdings.GetHoldingByBarcode(ctx, barcode)
+		if err != nil {
+			return nil, fmt.Errorf("service: failed to get holding by barcode: %w", err)
+		}
+		if holding == nil {
+			return nil, fmt.Errorf("service: no copy has barcode %q: %w", barcode, ErrInvalidInput)
+		}
+		return holding, nil
+	}
+	return nil, fmt.Errorf("service: holding_id or barcode is required: %w", ErrInvalidInput)
+}
+
+// RenewLoan extends an active loan by its library's loan period from today,
+// up to the library's renewal limit and only while no hold the copy could
+// fill is waiting; a renewal never brings the due date forward. It returns
+// the renewed loan, or nil if there is none with the ID.
+func (s *loanService) RenewLoan(ctx context.Context, id string) (*models.Loan, error) {
+	loan, err := s.GetLoanByID(ctx, id)
+	if loan == nil || err != nil {
+		return nil, err
+	}
+	if loan.ReturnedAt != nil {
+		return nil, fmt.Errorf("service: loan %s was already returned: %w", id, ErrInvalidInput)
+	}
+	library, err := s.libraries.GetLibraryByID(ctx, loan.LibraryID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get library by ID: %w", err)
+	}
+	if loan.Renewals >= *library.MaxRenewals {
+		return nil, fmt.Errorf("service: loan %s has reached the limit of %d renewals: %w", id, *library.MaxRenewals, ErrInvalidInput)
+	}
+	waiting, err := s.holds.GetWaitingHolds(ctx, loan.BookID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get waiting holds: %w", err)
+	}
+	for _, hold := range waiting {
+		if holdFilledBy(hold, loan.LibraryID) {
+			return nil, fmt.Errorf("service: loan %s cannot be renewed while other readers are waiting for the book: %w", id, ErrInvalidInput)
+		}
+	}
+
+	dueAt := time.Now().Add(days(library.LoanPeriodDays))
+	if dueAt.Before(loan.DueAt) {
+		dueAt = loan.DueAt
+	}
+	renewed, err := s.repo.RenewLoan(ctx, id, dueAt, *library.MaxRenewals)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to renew loan: %w", err)
+	}
+	if !renewed {
+		return nil, fmt.Errorf("service: loan %s was returned or renewed meanwhile: %w", id, ErrInvalidInput)
+	}
+	return s.GetLoanByID(ctx, id)
+}
+
+// CheckIn returns a loaned copy, making it available again or, if a hold is
+// waiting for it, putting it on the hold shelf. It returns the closed loan
+// and any hold the copy was assigned to, or nil if there is no loan with the
+// ID.
+func (s *loanService) CheckIn(ctx context.Context, id string) (*models.CheckInResult, error) {
+	ok, err := s.repo.CheckIn(ctx, id, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to check in loan: %w", err)
+	}
+	loan, err := s.GetLoanByID(ctx, id)
+	if loan == nil || err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("service: loan %s was already returned: %w", id, ErrInvalidInput)
+	}
+	hold, err := s.assigner.assignCopy(ctx, loan.HoldingID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.CheckInResult{Loan: *loan, Hold: hold}, nil
+}
+
+// CheckInByBarcode returns the loaned copy with a barcode, as scanned at the
+// desk.
+func (s *loanService) CheckInByBarcode(ctx context.Context, barcode string) (*models.CheckInResult, error) {
+	if strings.TrimSpace(barcode) == "" {
+		return nil, fmt.Errorf("service: barcode is required: %w", ErrInvalidInput)
+	}
+	holding, err := s.findCopy(ctx, "", barcode)
+	if err != nil {
+		return nil, err
+	}
+	loan, err := s.repo.GetActiveLoanByHolding(ctx, holding.ID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get active loan: %w", err)
+	}
+	if loan == nil {
+		return nil, fmt.Errorf("service: copy %q is not on loan: %w", barcode, ErrInvalidInput)
+	}
+	return s.CheckIn(ctx, loan.ID)
+}
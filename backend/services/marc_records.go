@@ -0,0 +1,214 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"book-recommendation-system/backend/isbn"
+	"book-recommendation-system/backend/marc"
+	"book-recommendation-system/backend/models"
+)
+
+// yearPattern finds a publication year in free text such as "c1965." or
+// "[1999?]". Its first submatch is the year; any letters may precede it, but
+// not other digits.
+var yearPattern = regexp.MustCompile(`(?:^|[^0-9])((?:1[5-9]|20)\d{2})(?:[^0-9]|$)`)
+
+// findYear returns the first publication year in free text, or 0 if there is
+// none.
+func findYear(s string) int {
+	m := yearPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0
+	}
+	year, _ := strconv.Atoi(m[1])
+	return year
+}
+
+// pagesPattern finds the page count in a physical description such as
+// "xii, 310 pages :" or "245 p.".
+var pagesPattern = regexp.MustCompile(`(\d+)\s*(?:pages|p\.|p\b)`)
+
+// marcRecordReader adapts a MARC reader to a RecordReader, numbering records
+// from 1 in the order they appear.
+type marcRecordReader struct {
+	r interface {
+		Read() (*marc.Record, error)
+	}
+	row int
+}
+
+func (m *marcRecordReader) Read() (models.ImportRecord, error) {
+	rec, err := m.r.Read()
+	if err == nil || errors.Is(err, marc.ErrMalformedRecord) {
+		m.row++
+	}
+	if errors.Is(err, marc.ErrMalformedRecord) {
+		return models.ImportRecord{}, &RowError{Row: m.row, Err: err}
+	}
+	if err != nil {
+		return models.ImportRecord{}, err
+	}
+	return marcImportRecord(m.row, rec), nil
+}
+
+// marcImportRecord maps the bibliographic fields of a MARC 21 record to an
+// import record:
+//
+//	020 $a         ISBN (the first valid one)
+//	100 $a, 700 $a main and added entries, with roles from $e or $4
+//	245 $a $b      title and subtitle
+//	250 $a         edition statement
+//	264 $b, 260 $b publisher
+//	300 $a         page count
+//	520 $a         summary
+//	650 $a         topical subjects, imported as genres
+//	653 $a         uncontrolled index terms, imported as subject tags
+//	264 $c, 260 $c publication year, falling back to 008/07-10
+//	008/35-37      language
+//	852, 952       one copy per holdings or item field, at least one
+func marcImportRecord(row int, rec *marc.Record) models.ImportRecord {
+	out := models.ImportRecord{Row: row, Copies: 1}
+	book := &out.Book
+
+	for _, f := range rec.Fields("020") {
+		candidate, _, _ := strings.Cut(strings.TrimSpace(f.Subfield('a')), " ")
+		if candidate == "" {
+			continue
+		}
+		if book.ISBN == "" {
+			book.ISBN = candidate
+		}
+		if isbn.Validate(candidate) == nil {
+			book.ISBN = candidate
+			break
+		}
+	}
+
+	for _, tag := range []string{"100", "700"} {
+		for _, f := range rec.Fields(tag) {
+			if name := trimISBD(f.Subfield('a')); name != "" {
+				out.Contributors = append(out.Contributors, models.ImportContributor{Name: name, Role: marcRelatorRole(f)})
+			}
+		}
+	}
+
+	if f := rec.Fields("245"); len(f) > 0 {
+		title := trimISBD(f[0].Subfield('a'))
+		if subtitle := trimISBD(f[0].Subfield('b')); subtitle != "" {
+			title += ": " + subtitle
+		}
+		book.Title = title
+	}
+	if f := rec.Fields("250"); len(f) > 0 {
+		book.Edition = trimISBD(f[0].Subfield('a'))
+	}
+	book.Publisher = marcPublisher(rec)
+	if f := rec.Fields("300"); len(f) > 0 {
+		if m := pagesPattern.FindStringSubmatch(f[0].Subfield('a')); m != nil {
+			book.PageCount, _ = strconv.Atoi(m[1])
+		}
+	}
+	if f := rec.Fields("520"); len(f) > 0 {
+		book.Description = strings.TrimSpace(f[0].Subfield('a'))
+	}
+	if fixed := rec.ControlField("008"); len(fixed) >= 38 {
+		book.Language = marcToISO639[strings.TrimSpace(fixed[35:38])]
+	}
+
+	seen := make(map[string]bool)
+	for _, f := range rec.Fields("650") {
+		subject := trimISBD(f.Subfield('a'))
+		if key := normalizeName(subject); key != "" && !seen[key] {
+			seen[key] = true
+			out.GenreNames = append(out.GenreNames, subject)
+		}
+	}
+
+	for _, f := range rec.Fields("653") {
+		for _, term := range f.SubfieldValues('a') {
+			if term = trimISBD(term); term != "" {
+				book.Subjects = append(book.Subjects, term)
+			}
+		}
+	}
+
+	book.PublicationYear = marcPublicationYear(rec)
+
+	if copies := len(rec.Fields("852")) + len(rec.Fields("952")); copies > 0 {
+		out.Copies = copies
+	}
+	return out
+}
+
+// marcRelatorRole maps the relator term ($e) or code ($4) of a name entry to
+// an author role, treating unmarked and unknown relators as authors.
+func marcRelatorRole(f marc.DataField) string {
+	relators := append(f.SubfieldValues('4'), f.SubfieldValues('e')...)
+	for _, r := range relators {
+		switch strings.ToLower(trimISBD(r)) {
+		case "trl", "translator":
+			return models.AuthorRoleTranslator
+		case "ill", "illustrator":
+			return models.AuthorRoleIllustrator
+		}
+	}
+	return models.AuthorRoleAuthor
+}
+
+// marcPublisher prefers the publisher in a publication statement in 264
+// (second indicator 1), then 260.
+func marcPublisher(rec *marc.Record) string {
+	for _, f := range rec.Fields("264") {
+		if f.Ind2 == '1' {
+			if name := trimISBD(f.Subfield('b')); name != "" {
+				return name
+			}
+		}
+	}
+	for _, f := range rec.Fields("260") {
+		if name := trimISBD(f.Subfield('b')); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// marcPublicationYear prefers a publication statement in 264 (second
+// indicator 1), then 260, then the fixed-length date in 008.
+func marcPublicationYear(rec *marc.Record) int {
+	var statements []string
+	for _, f := range rec.Fields("264") {
+		if f.Ind2 == '1' {
+			statements = append(statements, f.Subfield('c'))
+		}
+	}
+	for _, f := range rec.Fields("260") {
+		statements = append(statements, f.Subfield('c'))
+	}
+	if fixed := rec.ControlField("008"); len(fixed) >= 11 {
+		statements = append(statements, fixed[7:11])
+	}
+	for _, s := range statements {
+		if year := findYear(s); year != 0 {
+			return year
+		}
+	}
+	return 0
+}
+
+// trimISBD strips the ISBD punctuation MARC puts at the end of subfields,
+// such as the " /" before a statement of responsibility or the final full
+// stop, keeping the full stop after an initial.
+func trimISBD(s string) string {
+	s = strings.TrimRight(strings.TrimSpace(s), " /:;,=")
+	if strings.HasSuffix(s, ".") {
+		words := strings.Fields(s)
+		if last := words[len(words)-1]; len([]rune(strings.TrimSuffix(last, "."))) > 1 {
+			s = strings.TrimSuffix(s, ".")
+		}
+	}
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"testing"
+
+	"book-recommendation-system/backend/marc"
+)
+
+func TestMARCPublicationYear(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []marc.DataField
+		fixed  string
+		want   int
+	}{
+		{"260 plain", []marc.DataField{field260("1965.")}, "", 1965},
+		{"260 copyright", []marc.DataField{field260("c1965.")}, "", 1965},
+		{"260 phonogram", []marc.DataField{field260("p2010")}, "", 2010},
+		{"260 bracketed guess", []marc.DataField{field260("[1999?]")}, "", 1999},
+		{"260 publication and copyright", []marc.DataField{field260("1972, c1971.")}, "", 1972},
+		{"260 no year", []marc.DataField{field260("[n.d.]")}, "", 0},
+		{"260 digits too long", []marc.DataField{field260("19650")}, "", 0},
+		{"264 publication", []marc.DataField{field264('1', "[2015]")}, "", 2015},
+		{"264 copyright only", []marc.DataField{field264('4', "©2014")}, "", 0},
+		{"264 before 260", []marc.DataField{field260("c1990."), field264('1', "2001.")}, "", 2001},
+		{"264 copyright falls back to 008", []marc.DataField{field264('4', "℗2014")}, "150101s2013    xxu           000 0 eng d", 2013},
+		{"008 only", nil, "850101s1984    nyu           000 1 eng  ", 1984},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &marc.Record{DataFields: tt.fields}
+			if tt.fixed != "" {
+				rec.ControlFields = []marc.ControlField{{Tag: "008", Value: tt.fixed}}
+			}
+			if got := marcPublicationYear(rec); got != tt.want {
+				t.Errorf("marcPublicationYear() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindYear(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"1965", 1965},
+		{"c1965.", 1965},
+		{"p2010", 2010},
+		{"March 4, 1999", 1999},
+		{"1999-03-04", 1999},
+		{"circa 1500s", 1500},
+		{"1499", 0},
+		{"12005", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := findYear(tt.in); got != tt.want {
+			t.Errorf("findYear(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func field260(c string) marc.DataField {
+	return marc.DataField{Tag: "260", Ind1: ' ', Ind2: ' ', Subfields: []marc.Subfield{{Code: 'a', Value: "New York :"}, {Code: 'c', Value: c}}}
+}
+
+func field264(ind2 byte, c string) marc.DataField {
+	return marc.DataField{Tag: "264", Ind1: ' ', Ind2: ind2, Subfields: []marc.Subfield{{Code: 'a', Value: "London :"}, {Code: 'c', Value: c}}}
+}
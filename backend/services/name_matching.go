@@ -0,0 +1,137 @@
+package services
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// normalizeName lowercases a person or genre name, drops punctuation,
+// collapses whitespace and initials, and turns "Last, First" into
+// "first last", so that trivially different spellings compare equal.
+func normalizeName(name string) string {
+	if last, first, found := strings.Cut(name, ","); found && !strings.Contains(first, ",") {
+		name = first + " " + last
+	}
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteRune(r)
+		case unicode.IsSpace(r) || r == '.' || r == '-' || r == '_':
+			space = true
+		}
+	}
+	return joinInitials(strings.Fields(b.String()))
+}
+
+// joinInitials joins words, running consecutive initials together so that
+// "j r r tolkien" and "jrr tolkien" normalise alike.
+func joinInitials(words []string) string {
+	var b strings.Builder
+	for i, w := range words {
+		initial := len([]rune(w)) == 1
+		prevInitial := i > 0 && len([]rune(words[i-1])) == 1
+		if i > 0 && !(initial && prevInitial) {
+			b.WriteByte(' ')
+		}
+		b.WriteString(w)
+	}
+	return b.String()
+}
+
+// splitNames splits a free-text list of people such as "Terry Pratchett &
+// Neil Gaiman" or "Pratchett, Terry; Gaiman, Neil" into its entries. A single comma is
+// read as "Last, First" rather than as a separator when either side is one
+// word.
+func splitNames(list string) []string {
+	var parts []string
+	for _, part := range strings.FieldsFunc(list, func(r rune) bool { return r == ';' || r == '&' || r == '/' || r == '|' }) {
+		for _, p := range strings.Split(part, " and ") {
+			parts = append(parts, splitCommaNames(p)...)
+		}
+	}
+
+	names := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			names = append(names, p)
+		}
+	}
+	return names
+}
+
+// splitGenres splits a free-text genre list such as "Fantasy, Horror" into its entries.
+func splitGenres(list string) []string {
+	var names []string
+	for _, p := range strings.FieldsFunc(list, func(r rune) bool { return r == ',' || r == ';' || r == '&' || r == '/' || r == '|' }) {
+		if p = strings.TrimSpace(p); p != "" {
+			names = append(names, p)
+		}
+	}
+	return names
+}
+
+func splitCommaNames(s string) []string {
+	pieces := strings.Split(s, ",")
+	if len(pieces) == 2 && (len(strings.Fields(pieces[0])) == 1 || len(strings.Fields(pieces[1])) == 1) {
+		return []string{s}
+	}
+	return pieces
+}
+
+// nameSimilarity scores how alike two normalised names are, from 0 to 1,
+// as one minus their edit distance relative to the longer name. Word order
+// is ignored so "gaiman neil" matches "neil gaiman".
+func nameSimilarity(a, b string) float64 {
+	a, b = sortWords(a), sortWords(b)
+	if a == b {
+		return 1
+	}
+	longest := max(len([]rune(a)), len([]rune(b)))
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(levenshtein(a, b))/float64(longest)
+}
+
+// lastWord returns the last word of a normalised name.
+func lastWord(s string) string {
+	if i := strings.LastIndexByte(s, ' '); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
+
+func sortWords(s string) string {
+	words := strings.Fields(s)
+	slices.Sort(words)
+	return strings.Join(words, " ")
+}
+
+// levenshtein returns the number of single-rune edits between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
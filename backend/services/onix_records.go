@@ -0,0 +1,231 @@
+package services
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"book-recommendation-system/backend/isbn"
+	"book-recommendation-system/backend/models"
+	"book-recommendation-system/backend/onix"
+)
+
+// onixContributorRoles maps ONIX contributor roles (code list 17) to author
+// roles. Contributors in other roles, such as editors, are not imported.
+var onixContributorRoles = map[string]string{
+	"A01": models.AuthorRoleAuthor,
+	"A12": models.AuthorRoleIllustrator,
+	"B06": models.AuthorRoleTranslator,
+}
+
+// markupTag matches an HTML or XHTML tag in ONIX text content.
+var markupTag = regexp.MustCompile(`<[^>]*>`)
+
+// onixRecordReader adapts an ONIX reader to a RecordReader, numbering
+// products from 1 in the order they appear.
+type onixRecordReader struct {
+	r   *onix.Reader
+	row int
+}
+
+func (o *onixRecordReader) Read() (models.ImportRecord, error) {
+	p, err := o.r.Read()
+	if err != nil {
+		return models.ImportRecord{}, err
+	}
+	o.row++
+	switch p.NotificationType {
+	case onix.NotificationTestUpdate, onix.NotificationTestRecord:
+		return models.ImportRecord{}, &RowError{Row: o.row, Err: fmt.Errorf("product %s is a test record", p.RecordReference)}
+	}
+	return onixImportRecord(o.row, p), nil
+}
+
+// onixImportRecord maps an ONIX product to an import record. Delete
+// notifications become deletes and block updates become partial updates
+// carrying only the blocks they include.
+func onixImportRecord(row int, p *onix.Product) models.ImportRecord {
+	rec := models.ImportRecord{Row: row, Copies: 1}
+	book := &rec.Book
+	book.ISBN = onixISBN(p.ProductIdentifiers)
+	switch p.NotificationType {
+	case onix.NotificationDelete:
+		rec.Delete = true
+		return rec
+	case onix.NotificationUpdate:
+		rec.Partial = true
+	}
+
+	if d := p.DescriptiveDetail; d != nil {
+		for _, td := range d.TitleDetails {
+			for _, te := range td.TitleElements {
+				switch {
+				case td.TitleType == onix.TitleTypeDistinctive && te.TitleElementLevel == onix.TitleLevelProduct && book.Title == "":
+					book.Title = strings.TrimSpace(te.Title())
+					if subtitle := strings.TrimSpace(te.Subtitle); subtitle != "" {
+						book.Title += ": " + subtitle
+					}
+				case te.TitleElementLevel == onix.TitleLevelCollection && book.Series == "":
+					setOnixSeries(book, te)
+				}
+			}
+		}
+		for _, c := range d.Collections {
+			for _, td := range c.TitleDetails {
+				for _, te := range td.TitleElements {
+					if te.TitleElementLevel == onix.TitleLevelCollection && book.Series == "" {
+						setOnixSeries(book, te)
+					}
+				}
+			}
+		}
+
+		for _, c := range d.Contributors {
+			name := strings.TrimSpace(c.Name())
+			if name == "" {
+				continue
+			}
+			for _, code := range c.ContributorRoles {
+				if role, ok := onixContributorRoles[code]; ok {
+					rec.Contributors = append(rec.Contributors, models.ImportContributor{Name: name, Role: role})
+					break
+				}
+			}
+		}
+
+		book.Format = onixFormat(d.ProductForm)
+		book.Edition = strings.TrimSpace(d.EditionStatement)
+		for _, l := range d.Languages {
+			if l.LanguageRole == onix.LanguageRoleText {
+				book.Language = marcToISO639[strings.ToLower(strings.TrimSpace(l.LanguageCode))]
+				break
+			}
+		}
+		for _, e := range d.Extents {
+			if (e.ExtentType == onix.ExtentMainContentPages || e.ExtentType == onix.ExtentContentPages) && e.ExtentUnit == onix.ExtentUnitPages {
+				if pages, err := strconv.Atoi(strings.TrimSpace(e.ExtentValue)); err == nil && pages > 0 {
+					book.PageCount = pages
+					break
+				}
+			}
+		}
+
+		seen := make(map[string]bool)
+		var main, other []string
+		for _, s := range d.Subjects {
+			if s.SubjectSchemeIdentifier != onix.SubjectSchemeBISAC {
+				continue
+			}
+			genre := bisacGenre(s.SubjectCode, s.SubjectHeadingText)
+			key := normalizeName(genre)
+			if key == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			if s.MainSubject != nil {
+				main = append(main, genre)
+			} else {
+				other = append(other, genre)
+			}
+		}
+		rec.GenreNames = append(main, other...)
+	}
+
+	if c := p.CollateralDetail; c != nil {
+		var description, short string
+		for _, tc := range c.TextContents {
+			switch {
+			case tc.TextType == onix.TextTypeDescription && description == "":
+				description = plainText(tc.Text.Raw)
+			case tc.TextType == onix.TextTypeShortDescription && short == "":
+				short = plainText(tc.Text.Raw)
+			}
+		}
+		if description == "" {
+			description = short
+		}
+		book.Description = description
+
+		for _, r := range c.SupportingResources {
+			if r.ResourceContentType == onix.ResourceFrontCover && r.ResourceMode == onix.ResourceModeImage && len(r.ResourceVersions) > 0 {
+				book.CoverImageURL = strings.TrimSpace(r.ResourceVersions[0].ResourceLink)
+				break
+			}
+		}
+	}
+
+	if pd := p.PublishingDetail; pd != nil {
+		for _, pub := range pd.Publishers {
+			if pub.PublishingRole == onix.PublishingRolePublisher {
+				book.Publisher = strings.TrimSpace(pub.PublisherName)
+				break
+			}
+		}
+		for _, d := range pd.PublishingDates {
+			date := strings.TrimSpace(d.Date)
+			if d.PublishingDateRole != onix.PublishingDatePublication || len(date) < 4 {
+				continue
+			}
+			if year, err := strconv.Atoi(date[:4]); err == nil {
+				book.PublicationYear = year
+				break
+			}
+		}
+	}
+	return rec
+}
+
+// onixFormat maps an ONIX product form to a book format, or "" for forms
+// that are none of them.
+func onixFormat(form string) string {
+	switch {
+	case form == onix.ProductFormHardback:
+		return models.BookFormatHardcover
+	case form == onix.ProductFormPaperback:
+		return models.BookFormatPaperback
+	case strings.HasPrefix(form, onix.ProductFormDigital):
+		return models.BookFormatEbook
+	case strings.HasPrefix(form, onix.ProductFormAudio):
+		return models.BookFormatAudio
+	}
+	return ""
+}
+
+// onixISBN returns the product's ISBN, preferring an ISBN-13, then a
+// GTIN-13 in the book range, then an ISBN-10.
+func onixISBN(ids []onix.ProductIdentifier) string {
+	for _, idType := range []string{onix.ProductIDISBN13, onix.ProductIDGTIN13, onix.ProductIDISBN10} {
+		for _, id := range ids {
+			value := strings.TrimSpace(id.IDValue)
+			if id.ProductIDType != idType {
+				continue
+			}
+			if idType == onix.ProductIDGTIN13 && !strings.HasPrefix(isbn.Strip(value), "978") && !strings.HasPrefix(isbn.Strip(value), "979") {
+				continue
+			}
+			return value
+		}
+	}
+	return ""
+}
+
+// setOnixSeries sets the book's series from a collection-level title element.
+func setOnixSeries(book *models.Book, te onix.TitleElement) {
+	book.Series = strings.TrimSpace(te.Title())
+	if position, err := strconv.ParseFloat(strings.TrimSpace(te.PartNumber), 64); err == nil && position > 0 {
+		book.SeriesPosition = position
+	}
+}
+
+// plainText reduces ONIX text content, which may be XHTML, escaped HTML or
+// plain text, to plain text.
+func plainText(raw string) string {
+	raw = strings.NewReplacer("<![CDATA[", "", "]]>", "").Replace(raw)
+	// Strip tags both before and after unescaping, for XHTML embedded as
+	// elements and for HTML embedded as escaped text.
+	text := html.UnescapeString(markupTag.ReplaceAllString(raw, " "))
+	text = html.UnescapeString(markupTag.ReplaceAllString(text, " "))
+	return strings.Join(strings.Fields(text), " ")
+}
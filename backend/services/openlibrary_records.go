@@ -0,0 +1,401 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"book-recommendation-system/backend/isbn"
+	"book-recommendation-system/backend/models"
+	"book-recommendation-system/backend/openlibrary"
+)
+
+// ImportFormatOpenLibrary identifies imports from Open Library dumps.
+const ImportFormatOpenLibrary = "openlibrary"
+
+// maxOpenLibraryGenres caps how many of a work's subjects become genres;
+// Open Library subjects are numerous and get less relevant down the list.
+const maxOpenLibraryGenres = 5
+
+// maxOpenLibraryWorks caps how many works an import indexes before reading
+// the editions. An unfiltered dump names tens of millions, too many to hold
+// in memory, so such an import is refused and should be narrowed by language
+// or subject.
+const maxOpenLibraryWorks = 2_000_000
+
+// openLibraryNoiseSubjects are housekeeping subjects that are not genres.
+var openLibraryNoiseSubjects = map[string]bool{
+	"accessible book":          true,
+	"protected daisy":          true,
+	"in library":               true,
+	"lending library":          true,
+	"overdrive":                true,
+	"large type books":         true,
+	"open library staff picks": true,
+	"nyt bestseller":           true,
+}
+
+// marcToISO639 maps the MARC language codes used by Open Library to the
+// two-letter codes stored on books, for the languages we see most.
+var marcToISO639 = map[string]string{
+	"ara": "ar", "chi": "zh", "cze": "cs", "dan": "da", "dut": "nl",
+	"eng": "en", "fin": "fi", "fre": "fr", "ger": "de", "gre": "el",
+	"heb": "he", "hin": "hi", "hun": "hu", "ita": "it", "jpn": "ja",
+	"kor": "ko", "nor": "no", "pol": "pl", "por": "pt", "rum": "ro",
+	"rus": "ru", "spa": "es", "swe": "sv", "tur": "tr", "ukr": "uk",
+}
+
+// DumpOpener opens a dump file for reading. It is called once per pass.
+type DumpOpener func() (io.ReadCloser, error)
+
+// OpenLibraryDumps are the dumps to import from. Editions is required; the
+// works and authors dumps add subjects, descriptions and author names. A
+// combined dump may be given for all three.
+type OpenLibraryDumps struct {
+	Editions DumpOpener
+	Works    DumpOpener
+	Authors  DumpOpener
+}
+
+// OpenLibraryFilter limits an import to a subset of the dump. Languages are
+// MARC codes such as "eng" or two-letter codes such as "en"; subjects match
+// work or edition subjects case-insensitively. Empty lists match everything.
+type OpenLibraryFilter struct {
+	Languages []string
+	Subjects  []string
+}
+
+// openLibraryWork is what an import keeps of a work.
+type openLibraryWork struct {
+	subjects     []string // the subjects usable as genres, at most maxOpenLibraryGenres
+	subjectMatch bool     // whether a subject passes the subject filter
+	description  string
+	authorKeys   []string
+	cover        int64
+}
+
+// openLibraryRecordReader reads the editions of an Open Library dump as
+// import records. Before the first record it makes a pass over the editions,
+// the works and the authors to collect what the selected editions need, so
+// only that subset is held in memory, up to maxOpenLibraryWorks works. Records are numbered by their line in
+// the editions dump.
+type openLibraryRecordReader struct {
+	dumps     OpenLibraryDumps
+	languages map[string]bool
+	subjects  map[string]bool
+
+	works   map[string]*openLibraryWork
+	authors map[string]string // key -> name
+	seen    map[string]int    // ISBN -> row it was imported from
+
+	editions io.Closer
+	r        *openlibrary.Reader
+}
+
+// NewOpenLibraryRecordReader returns a reader over the editions of dumps that
+// pass filter. Close it when done.
+func NewOpenLibraryRecordReader(dumps OpenLibraryDumps, filter OpenLibraryFilter) (RecordReadCloser, error) {
+	if dumps.Editions == nil {
+		return nil, fmt.Errorf("service: an editions dump is required: %w", ErrInvalidInput)
+	}
+	o := &openLibraryRecordReader{
+		dumps:     dumps,
+		languages: make(map[string]bool),
+		subjects:  make(map[string]bool),
+		works:     make(map[string]*openLibraryWork),
+		authors:   make(map[string]string),
+		seen:      make(map[string]int),
+	}
+	for _, lang := range filter.Languages {
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		for marcCode, code := range marcToISO639 {
+			if code == lang {
+				lang = marcCode
+			}
+		}
+		if lang != "" {
+			o.languages[lang] = true
+		}
+	}
+	for _, s := range filter.Subjects {
+		if s = strings.ToLower(strings.TrimSpace(s)); s != "" {
+			o.subjects[s] = true
+		}
+	}
+
+	if err := o.index(); err != nil {
+		return nil, err
+	}
+	f, err := dumps.Editions()
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to open editions dump: %w", err)
+	}
+	o.editions = f
+	o.r = openlibrary.NewReader(f)
+	return o, nil
+}
+
+// Close closes the editions dump.
+func (o *openLibraryRecordReader) Close() error {
+	return o.editions.Close()
+}
+
+// index collects the works and authors of the editions in the subset.
+func (o *openLibraryRecordReader) index() error {
+	authorKeys := make(map[string]bool)
+	err := scanDump(o.dumps.Editions, openlibrary.TypeEdition, func(rec *openlibrary.Record) error {
+		var e openlibrary.Edition
+		if json.Unmarshal(rec.JSON, &e) != nil || editionISBN(&e) == "" || !o.languageMatches(&e) {
+			return nil
+		}
+		for _, w := range e.Works {
+			if o.works[w.Key] == nil {
+				o.works[w.Key] = &openLibraryWork{}
+			}
+		}
+		if len(o.works) > maxOpenLibraryWorks {
+			return fmt.Errorf("service: the editions to import belong to more than %d works; import a subset by language or subject: %w", maxOpenLibraryWorks, ErrInvalidInput)
+		}
+		for _, a := range e.Authors {
+			authorKeys[a.Key] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if o.dumps.Works != nil {
+		err = scanDump(o.dumps.Works, openlibrary.TypeWork, func(rec *openlibrary.Record) error {
+			w, ok := o.works[rec.Key]
+			if !ok {
+				return nil
+			}
+			var work openlibrary.Work
+			if json.Unmarshal(rec.JSON, &work) != nil {
+				return nil
+			}
+			w.subjects = openLibraryGenres(work.Subjects)
+			w.subjectMatch = o.anySubjectMatches(work.Subjects)
+			w.description = string(work.Description)
+			if len(work.Covers) > 0 {
+				w.cover = work.Covers[0]
+			}
+			for _, a := range work.Authors {
+				w.authorKeys = append(w.authorKeys, a.Author.Key)
+				authorKeys[a.Author.Key] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if o.dumps.Authors != nil {
+		err = scanDump(o.dumps.Authors, openlibrary.TypeAuthor, func(rec *openlibrary.Record) error {
+			if !authorKeys[rec.Key] {
+				return nil
+			}
+			var a openlibrary.Author
+			if json.Unmarshal(rec.JSON, &a) == nil && strings.TrimSpace(a.Name) != "" {
+				o.authors[rec.Key] = strings.TrimSpace(a.Name)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanDump calls fn for every record of the given type in a dump, stopping
+// at the first error fn returns.
+func scanDump(open DumpOpener, recordType string, fn func(*openlibrary.Record) error) error {
+	f, err := open()
+	if err != nil {
+		return fmt.Errorf("service: failed to open dump: %w", err)
+	}
+	defer f.Close()
+	r := openlibrary.NewReader(f)
+	for {
+		rec, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if rec.Type == recordType {
+			if err := fn(rec); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (o *openLibraryRecordReader) Read() (models.ImportRecord, error) {
+	for {
+		rec, err := o.r.Read()
+		if err != nil {
+			return models.ImportRecord{}, err
+		}
+		if rec.Type != openlibrary.TypeEdition {
+			continue
+		}
+		var e openlibrary.Edition
+		if err := json.Unmarshal(rec.JSON, &e); err != nil {
+			return models.ImportRecord{}, &RowError{Row: rec.Line, Err: fmt.Errorf("edition %s: invalid JSON: %v", rec.Key, err)}
+		}
+		isbn13 := editionISBN(&e)
+		if isbn13 == "" || !o.languageMatches(&e) {
+			continue
+		}
+		work := o.editionWork(&e)
+		if !o.subjectMatches(&e, work) {
+			continue
+		}
+		if first, dup := o.seen[isbn13]; dup {
+			return models.ImportRecord{}, &RowError{Row: rec.Line, Err: fmt.Errorf("edition %s: duplicate ISBN %s, already imported from row %d", rec.Key, isbn13, first)}
+		}
+		o.seen[isbn13] = rec.Line
+		return o.importRecord(rec.Line, &e, isbn13, work), nil
+	}
+}
+
+// importRecord maps an edition and its work to an import record.
+func (o *openLibraryRecordReader) importRecord(row int, e *openlibrary.Edition, isbn13 string, work *openLibraryWork) models.ImportRecord {
+	rec := models.ImportRecord{Row: row, Copies: 1}
+	book := &rec.Book
+	book.ISBN = isbn13
+	book.Title = strings.TrimSpace(e.Title)
+	if subtitle := strings.TrimSpace(e.Subtitle); subtitle != "" {
+		book.Title += ": " + subtitle
+	}
+	book.Description = strings.TrimSpace(string(e.Description))
+	if book.Description == "" {
+		book.Description = strings.TrimSpace(work.description)
+	}
+	book.PublicationYear = findYear(e.PublishDate)
+	if len(e.Covers) > 0 && e.Covers[0] > 0 {
+		book.CoverImageURL = openlibrary.CoverURL(e.Covers[0])
+	} else if work.cover > 0 {
+		book.CoverImageURL = openlibrary.CoverURL(work.cover)
+	}
+	if len(e.Series) > 0 {
+		book.Series, book.SeriesPosition = parseOpenLibrarySeries(e.Series[0])
+	}
+	for _, l := range e.Languages {
+		if code, ok := marcToISO639[openlibrary.LanguageCode(l)]; ok {
+			book.Language = code
+			break
+		}
+	}
+	book.PageCount = e.NumberOfPages
+	if len(e.Publishers) > 0 {
+		book.Publisher = strings.TrimSpace(e.Publishers[0])
+	}
+	book.Format = normalizeBookFormat(e.PhysicalFormat)
+	book.Edition = strings.TrimSpace(e.EditionName)
+
+	authorKeys := make([]string, 0, len(e.Authors))
+	for _, a := range e.Authors {
+		authorKeys = append(authorKeys, a.Key)
+	}
+	if len(authorKeys) == 0 {
+		authorKeys = work.authorKeys
+	}
+	for _, key := range authorKeys {
+		if name, ok := o.authors[key]; ok {
+			rec.Contributors = append(rec.Contributors, models.ImportContributor{Name: name, Role: models.AuthorRoleAuthor})
+		}
+	}
+
+	rec.GenreNames = openLibraryGenres(append(append([]string(nil), work.subjects...), e.Subjects...))
+	return rec
+}
+
+// openLibraryGenres returns the first maxOpenLibraryGenres subjects usable as
+// genres. Tagged subjects ("nyt:...", "series=...") and subdivided headings
+// ("Desert -- Fiction") are skipped as too specific to be genres.
+func openLibraryGenres(subjects []string) []string {
+	var genres []string
+	seen := make(map[string]bool)
+	for _, s := range subjects {
+		s = strings.TrimSpace(s)
+		key := normalizeName(s)
+		if key == "" || seen[key] || openLibraryNoiseSubjects[key] || strings.ContainsAny(s, ":=") || strings.Contains(s, "--") {
+			continue
+		}
+		seen[key] = true
+		genres = append(genres, s)
+		if len(genres) == maxOpenLibraryGenres {
+			break
+		}
+	}
+	return genres
+}
+
+// editionWork returns the indexed work of an edition, or an empty one.
+func (o *openLibraryRecordReader) editionWork(e *openlibrary.Edition) *openLibraryWork {
+	for _, w := range e.Works {
+		if work := o.works[w.Key]; work != nil {
+			return work
+		}
+	}
+	return &openLibraryWork{}
+}
+
+func (o *openLibraryRecordReader) languageMatches(e *openlibrary.Edition) bool {
+	if len(o.languages) == 0 {
+		return true
+	}
+	for _, l := range e.Languages {
+		if o.languages[openlibrary.LanguageCode(l)] {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *openLibraryRecordReader) subjectMatches(e *openlibrary.Edition, work *openLibraryWork) bool {
+	return len(o.subjects) == 0 || work.subjectMatch || o.anySubjectMatches(e.Subjects)
+}
+
+// anySubjectMatches reports whether one of subjects passes the subject filter.
+func (o *openLibraryRecordReader) anySubjectMatches(subjects []string) bool {
+	for _, s := range subjects {
+		if o.subjects[strings.ToLower(strings.TrimSpace(s))] {
+			return true
+		}
+	}
+	return false
+}
+
+// editionISBN returns an edition's first valid ISBN as an ISBN-13, or "".
+func editionISBN(e *openlibrary.Edition) string {
+	for _, candidate := range append(append([]string(nil), e.ISBN13...), e.ISBN10...) {
+		if normalized, err := isbn.Normalize(candidate); err == nil {
+			return normalized
+		}
+	}
+	return ""
+}
+
+// parseOpenLibrarySeries splits a series statement such as "Dune chronicles ; 1"
+// or "Discworld -- 5" into the series name and position.
+func parseOpenLibrarySeries(s string) (string, float64) {
+	for _, sep := range []string{";", "--", "#", ","} {
+		if name, part, found := strings.Cut(s, sep); found {
+			part = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(part), "no."))
+			if position, err := strconv.ParseFloat(strings.TrimSpace(part), 64); err == nil && position > 0 {
+				return strings.TrimSpace(name), position
+			}
+		}
+	}
+	return strings.TrimSpace(s), 0
+}
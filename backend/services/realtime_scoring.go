@@ -0,0 +1,301 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"book-recommendation-system/backend/models"
+)
+
+// Tiers that can serve a real-time recommendation request, from freshest to
+// most generic.
+const (
+	TierRealtime = "realtime"
+	TierCached   = "cached"
+	TierPopular  = "popular"
+)
+
+const (
+	// realtimeBudgetShare is the share of the remaining request deadline the
+	// real-time scorer may use; the rest is left for the fallbacks.
+	realtimeBudgetShare = 0.7
+	// popularFallbackTimeout bounds the popularity query, which runs even if
+	// the request deadline has already passed so that something is served.
+	popularFallbackTimeout = 250 * time.Millisecond
+	// popularityWindow is how far back interactions count towards popularity.
+	popularityWindow = 30 * 24 * time.Hour
+	// popularListSize is how many popular books are fetched and remembered.
+	popularListSize = 100
+	// maxScoringNeighbours caps how many similar users are consulted when
+	// scoring, bounding the work done per request.
+	maxScoringNeighbours = 50
+	// maxCoInteractionsPerBook caps how many of the most recent interactions
+	// with each of a user's books are read to find similar users, so that
+	// popular books do not blow the scoring budget.
+	maxCoInteractionsPerBook = 500
+)
+
+// interactionWeights is how strongly each interaction type signals interest.
+var interactionWeights = map[string]float64{
+	models.InteractionTypeView:   0.5,
+	models.InteractionTypeClick:  0.5,
+	models.InteractionTypeLike:   2,
+	models.InteractionTypeRating: 2,
+	models.InteractionTypeRead:   3,
+	models.InteractionTypeBorrow: 3,
+}
+
+// interactionWeight returns the interest weight of an interaction type,
+// defaulting to 1 for types without an explicit weight.
+func interactionWeight(interactionType string) float64 {
+	if w, ok := interactionWeights[interactionType]; ok {
+		return w
+	}
+	return 1
+}
+
+// popularCache keeps the last popularity list that was read successfully,
+// so the final fallback tier still answers when the database does not.
+type popularCache struct {
+	mu    sync.RWMutex
+	books []models.BookPopularity
+}
+
+func (c *popularCache) get() []models.BookPopularity {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.books
+}
+
+func (c *popularCache) set(books []models.BookPopularity) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.books = books
+}
+
+// ScoreRecommendationsForUser computes fresh recommendations for a user within the
+// deadline of ctx. If real-time scoring is too slow, fails or has nothing to offer,
+// it falls back to the precomputed recommendations and then to popular books. It
+// returns the tier that served the request.
+func (s *recommendationService) ScoreRecommendationsForUser(ctx context.Context, userID string, limit int) ([]models.Recommendation, string, error) {
+	if limit <= 0 {
+		return nil, "", fmt.Errorf("service: limit must be positive, got %d: %w", limit, ErrInvalidInput)
+	}
+
+	realtimeCtx := ctx
+	if deadline, ok := ctx.Deadline(); ok {
+		budget := time.Duration(float64(time.Until(deadline)) * realtimeBudgetShare)
+		var cancel context.CancelFunc
+		realtimeCtx, cancel = context.WithTimeout(ctx, budget)
+		defer cancel()
+	}
+	recommendations, err := s.scoreRealtime(realtimeCtx, userID, limit)
+	if err == nil && len(recommendations) > 0 {
+		return recommendations, TierRealtime, nil
+	}
+	if err != nil {
+		log.Printf("Real-time scoring for user %s failed, falling back: %v", userID, err)
+	}
+
+	recommendations, err = s.GetRecommendationsByUserID(ctx, userID)
+	if err == nil && len(recommendations) > 0 {
+		if len(recommendations) > limit {
+			recommendations = recommendations[:limit]
+		}
+		return recommendations, TierCached, nil
+	}
+	if err != nil {
+		log.Printf("Cached recommendations for user %s unavailable, falling back: %v", userID, err)
+	}
+
+	popularCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), popularFallbackTimeout)
+	defer cancel()
+	recommendations, err = s.popularRecommendations(popularCtx, userID, limit)
+	if err != nil {
+		return nil, "", err
+	}
+	return recommendations, TierPopular, nil
+}
+
+// scoreRealtime scores works for a user by user-based collaborative filtering
+// over interaction history: users who engaged with the same works, through
+// any of their editions, are neighbours, and the works they engaged with are
+// scored by neighbour similarity times interaction weight. Scores are blended
+// with how well each book's content matches the user's (see
+// blendContentScores), and each work is then served as the edition that
+// suits the user best.
+func (s *recommendationService) scoreRealtime(ctx context.Context, userID string, limit int) ([]models.Recommendation, error) {
+	own, err := s.interactionRepo.GetUserInteractionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get user interactions: %w", err)
+	}
+	workOf := make(map[string]string)
+	if err := s.addBookWorks(ctx, workOf, interactionBookIDs(own)); err != nil {
+		return nil, err
+	}
+	profile := interestProfile(own, workOf)
+	if len(profile) == 0 {
+		return nil, nil
+	}
+
+	seedWorks := make([]string, 0, len(profile))
+	for workID := range profile {
+		seedWorks = append(seedWorks, workID)
+	}
+	editions, err := s.bookRepo.GetBooksByWorkIDs(ctx, seedWorks)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get editions: %w", err)
+	}
+	seedIDs := make([]string, 0, len(editions))
+	for _, b := range editions {
+		workOf[b.ID] = b.WorkID
+		seedIDs = append(seedIDs, b.ID)
+	}
+	coInteractions, err := s.interactionRepo.GetRecentInteractionsByBookIDs(ctx, seedIDs, maxCoInteractionsPerBook)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get co-interactions: %w", err)
+	}
+
+	similarity := make(map[string]float64)
+	for _, ui := range coInteractions {
+		if ui.UserID == userID {
+			continue
+		}
+		similarity[ui.UserID] += profile[workKey(workOf, ui.BookID)] * interactionWeight(ui.InteractionType)
+	}
+	neighbours := topNeighbours(similarity, maxScoringNeighbours)
+	if len(neighbours) == 0 {
+		return nil, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	neighbourInteractions, err := s.interactionRepo.GetUserInteractionsByUserIDs(ctx, neighbours)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get neighbour interactions: %w", err)
+	}
+	if err := s.addBookWorks(ctx, workOf, interactionBookIDs(neighbourInteractions)); err != nil {
+		return nil, err
+	}
+	scores := make(map[string]float64)
+	// Each work is scored under one of its editions until the best edition
+	// is chosen below.
+	edition := make(map[string]string)
+	for _, ui := range neighbourInteractions {
+		workID := workKey(workOf, ui.BookID)
+		if _, seen := profile[workID]; seen {
+			continue
+		}
+		scores[workID] += similarity[ui.UserID] * interactionWeight(ui.InteractionType)
+		if _, ok := edition[workID]; !ok {
+			edition[workID] = ui.BookID
+		}
+	}
+
+	now := time.Now()
+	recommendations := make([]models.Recommendation, 0, len(scores))
+	for workID, score := range scores {
+		recommendations = append(recommendations, models.Recommendation{UserID: userID, BookID: edition[workID], Score: score, GeneratedAt: now})
+	}
+	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].BookID < recommendations[j].BookID
+	})
+	normalizeScores(recommendations)
+	recommendations, err = s.blendContentScores(ctx, own, editions, recommendations)
+	if err != nil {
+		return nil, err
+	}
+
+	recommendations, err = s.applySeriesRule(ctx, userID, recommendations)
+	if err != nil {
+		return nil, err
+	}
+	recommendations, err = s.applyWorkRule(ctx, userID, recommendations)
+	if err != nil {
+		return nil, err
+	}
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	return recommendations, nil
+}
+
+// interestProfile sums interaction weights per work for one user.
+func interestProfile(interactions []models.UserInteraction, workOf map[string]string) map[string]float64 {
+	profile := make(map[string]float64)
+	for _, ui := range interactions {
+		profile[workKey(workOf, ui.BookID)] += interactionWeight(ui.InteractionType)
+	}
+	return profile
+}
+
+// topNeighbours returns up to n user IDs with the highest similarity.
+func topNeighbours(similarity map[string]float64, n int) []string {
+	neighbours := make([]string, 0, len(similarity))
+	for userID := range similarity {
+		neighbours = append(neighbours, userID)
+	}
+	sort.Slice(neighbours, func(i, j int) bool {
+		if similarity[neighbours[i]] != similarity[neighbours[j]] {
+			return similarity[neighbours[i]] > similarity[neighbours[j]]
+		}
+		return neighbours[i] < neighbours[j]
+	})
+	if len(neighbours) > n {
+		neighbours = neighbours[:n]
+	}
+	return neighbours
+}
+
+// normalizeScores scales scores, which must be sorted descending, so the
+// best recommendation scores 1.
+func normalizeScores(recommendations []models.Recommendation) {
+	if len(recommendations) == 0 || recommendations[0].Score <= 0 {
+		return
+	}
+	top := recommendations[0].Score
+	for i := range recommendations {
+		recommendations[i].Score /= top
+	}
+}
+
+// popularRecommendations recommends the most popular recent books the user has
+// not already read, serving the last known list if the database cannot be
+// reached. If what the user has read cannot be looked up either, the list is
+// served unfiltered.
+func (s *recommendationService) popularRecommendations(ctx context.Context, userID string, limit int) ([]models.Recommendation, error) {
+	popular, err := s.interactionRepo.GetPopularBooks(ctx, time.Now().Add(-popularityWindow), popularListSize)
+	if err != nil {
+		popular = s.popular.get()
+		if popular == nil {
+			return nil, fmt.Errorf("service: failed to get popular books: %w", err)
+		}
+		log.Printf("Popular books unavailable, serving last known list: %v", err)
+	} else {
+		s.popular.set(popular)
+	}
+
+	now := time.Now()
+	recommendations := make([]models.Recommendation, 0, len(popular))
+	for _, p := range popular {
+		recommendations = append(recommendations, models.Recommendation{UserID: userID, BookID: p.BookID, Score: float64(p.InteractionCount), GeneratedAt: now})
+	}
+	if unread, err := s.applyWorkRule(ctx, userID, recommendations); err != nil {
+		log.Printf("Read books for user %s unavailable, serving popular books unfiltered: %v", userID, err)
+	} else {
+		recommendations = unread
+	}
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	normalizeScores(recommendations)
+	return recommendations, nil
+}
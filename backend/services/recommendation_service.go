@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"book-recommendation-system/backend/models"
 	"book-recommendation-system/backend/repositories"
@@ -10,51 +11,164 @@ import (
 
 // RecommendationService defines the interface for recommendation-related business logic.
 type RecommendationService interface {
-	GetAllRecommendations(ctx context.Context) ([]models.Recommendation, error)
-	GetRecommendationsByUserID(ctx context.Context, userID string) ([]models.Recommendation, error)
+	GetRecommendationByID(ctx context.Context, id string) (*models.Recommendation, error)
+	GetAllRecommendations(ctx context.Context, page models.PageRequest) (*models.Page[models.Recommendation], error)
 	GetRecommendationsByUserID(ctx context.Context, userID string) ([]models.Recommendation, error)
+	GetContextualRecommendationsByUserID(ctx context.Context, userID string, contextTokens []string) ([]models.Recommendation, error)
+	GetNearbyRecommendationsByUserID(ctx context.Context, userID string, contextTokens []string, near NearbyQuery) ([]models.NearbyRecommendation, error)
+	GetGroupRecommendations(ctx context.Context, req models.GroupRecommendationRequest) ([]models.GroupRecommendation, error)
+	GetContinueReading(ctx context.Context, userID string) ([]models.ContinueReading, error)
+	ScoreRecommendationsForUser(ctx context.Context, userID string, limit int) ([]models.Recommendation, string, error)
 	CreateRecommendation(ctx context.Context, recommendation *models.Recommendation) error
 	UpdateRecommendation(ctx context.Context, recommendation *models.Recommendation) error
 	DeleteRecommendation(ctx context.Context, id string) error
 }
 
+// NearbyQuery restricts or boosts recommendations to books available at
+// libraries within RadiusKm of a point.
+type NearbyQuery struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+	// Boost keeps books without a nearby copy, ranking nearby ones higher,
+	// instead of dropping them.
+	Boost bool
+}
+
+// maxNearbyBoost is the score multiplier bonus for a book available at
+// distance zero; it falls off linearly to nothing at the edge of the radius.
+const maxNearbyBoost = 0.5
+
 // recommendationService implements RecommendationService.
 type recommendationService struct {
-	repo repositories.RecommendationRepository
+	repo            repositories.RecommendationRepository
+	interactionRepo repositories.UserInteractionRepository
+	holdingRepo     repositories.HoldingRepository
+	bookRepo        repositories.BookRepository
+	genreRepo       repositories.GenreRepository
+	popular         popularCache
 }
 
 // NewRecommendationService creates a new RecommendationService.
-func NewRecommendationService(repo repositories.RecommendationRepository) RecommendationService {
-	return &recommendationService{repo: repo}
+func NewRecommendationService(repo repositories.RecommendationRepository, interactionRepo repositories.UserInteractionRepository, holdingRepo repositories.HoldingRepository, bookRepo repositories.BookRepository, genreRepo repositories.GenreRepository) RecommendationService {
+	return &recommendationService{repo: repo, interactionRepo: interactionRepo, holdingRepo: holdingRepo, bookRepo: bookRepo, genreRepo: genreRepo}
 }
 
 // GetRecommendationByID retrieves a recommendation by its ID using the repository.
 func (s *recommendationService) GetRecommendationByID(ctx context.Context, id string) (*models.Recommendation, error) {
 	recommendation, err := s.repo.GetRecommendationByID(ctx, id)
 	if err != nil {
-// GetAllRecommendations retrieves all recommendations using the repository.
-func (s *recommendationService) GetAllRecommendations(ctx context.Context) ([]models.Recommendation, error) {
-	recommendations, err := s.repo.GetAllRecommendations(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("service: failed to get all recommendations: %w", err)
+		return nil, fmt.Errorf("service: failed to get recommendation by ID: %w", err)
 	}
-	return recommendations, nil
+	return recommendation, nil
 }
 
-// GetRecommendationsByUserID retrieves recommendations for a specific user using the repository.
+// GetAllRecommendations retrieves a page of all recommendations using the repository.
+func (s *recommendationService) GetAllRecommendations(ctx context.Context, page models.PageRequest) (*models.Page[models.Recommendation], error) {
+	recommendations, err := s.repo.GetAllRecommendations(ctx, page)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get all recommendations: %w", err)
 	}
-	return recommendation, nil
+	return recommendations, nil
 }
 
-// GetRecommendationsByUserID retrieves recommendations for a specific user using the repository.
+// GetRecommendationsByUserID retrieves recommendations for a specific user using the repository,
+// keeping only the volume of each series the user should read next and the
+// best edition of each work.
 func (s *recommendationService) GetRecommendationsByUserID(ctx context.Context, userID string) ([]models.Recommendation, error) {
 	recommendations, err := s.repo.GetRecommendationsByUserID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("service: failed to get recommendations by user ID: %w", err)
 	}
+	recommendations, err = s.applySeriesRule(ctx, userID, recommendations)
+	if err != nil {
+		return nil, err
+	}
+	return s.applyWorkRule(ctx, userID, recommendations)
+}
+
+// GetContextualRecommendationsByUserID retrieves a user's recommendations re-weighted
+// for the request context (e.g., "weekend", "mobile") using interaction history.
+func (s *recommendationService) GetContextualRecommendationsByUserID(ctx context.Context, userID string, contextTokens []string) ([]models.Recommendation, error) {
+	recommendations, err := s.GetRecommendationsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	contextTokens = normalizeContextTokens(contextTokens)
+	if len(recommendations) == 0 || len(contextTokens) == 0 {
+		return recommendations, nil
+	}
+
+	bookIDs := make([]string, len(recommendations))
+	for i, rec := range recommendations {
+		bookIDs[i] = rec.BookID
+	}
+	interactions, err := s.interactionRepo.GetUserInteractionsByBookIDs(ctx, bookIDs)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get interactions for contextual recommendations: %w", err)
+	}
+
+	weights := contextWeights(interactions, contextTokens)
+	for i := range recommendations {
+		if w, ok := weights[recommendations[i].BookID]; ok {
+			recommendations[i].Score *= w
+		}
+	}
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].Score > recommendations[j].Score
+	})
 	return recommendations, nil
 }
 
+// GetNearbyRecommendationsByUserID retrieves a user's recommendations limited to, or
+// boosted for, books currently available at libraries near the given point.
+func (s *recommendationService) GetNearbyRecommendationsByUserID(ctx context.Context, userID string, contextTokens []string, near NearbyQuery) ([]models.NearbyRecommendation, error) {
+	if near.RadiusKm <= 0 {
+		return nil, fmt.Errorf("service: radius must be positive, got %g km: %w", near.RadiusKm, ErrInvalidInput)
+	}
+
+	recommendations, err := s.GetContextualRecommendationsByUserID(ctx, userID, contextTokens)
+	if err != nil {
+		return nil, err
+	}
+	if len(recommendations) == 0 {
+		return []models.NearbyRecommendation{}, nil
+	}
+
+	bookIDs := make([]string, len(recommendations))
+	for i, rec := range recommendations {
+		bookIDs[i] = rec.BookID
+	}
+	locations, err := s.holdingRepo.GetAvailableBookLocationsNear(ctx, bookIDs, near.Latitude, near.Longitude, near.RadiusKm)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get nearby book locations: %w", err)
+	}
+	locationsByBook := make(map[string][]models.BookLocation)
+	for _, loc := range locations {
+		locationsByBook[loc.BookID] = append(locationsByBook[loc.BookID], loc)
+	}
+
+	nearby := make([]models.NearbyRecommendation, 0, len(recommendations))
+	for _, rec := range recommendations {
+		libs := locationsByBook[rec.BookID]
+		if len(libs) == 0 {
+			if !near.Boost {
+				continue
+			}
+			libs = []models.BookLocation{}
+		} else if near.Boost {
+			// Locations are ordered nearest first.
+			rec.Score *= 1 + maxNearbyBoost*(1-libs[0].DistanceKm/near.RadiusKm)
+		}
+		nearby = append(nearby, models.NearbyRecommendation{Recommendation: rec, Libraries: libs})
+	}
+	sort.SliceStable(nearby, func(i, j int) bool {
+		return nearby[i].Score > nearby[j].Score
+	})
+	return nearby, nil
+}
+
 // CreateRecommendation creates a new recommendation using the repository.
 func (s *recommendationService) CreateRecommendation(ctx context.Context, recommendation *models.Recommendation) error {
 	// Add any business logic/validation before creating a recommendation
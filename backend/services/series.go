@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"book-recommendation-system/backend/models"
+)
+
+// nextInSeriesBoost multiplies the score of the next unread volume of a
+// series the user has already started.
+const nextInSeriesBoost = 1.25
+
+// seriesProgress is a user's position in one series.
+type seriesProgress struct {
+	volumesRead      int
+	lastReadPosition float64
+	lastReadAt       time.Time
+	// next is the lowest-positioned volume the user has not read, or nil
+	// when they have read every volume.
+	next *models.Book
+}
+
+// started reports whether the user has read any volume of the series.
+func (p *seriesProgress) started() bool {
+	return p.volumesRead > 0
+}
+
+// buildSeriesProgress computes progress through each series from its volumes,
+// which must be ordered by series position, and the user's read books. The
+// editions of a volume share a work, and reading any of them reads the volume.
+func buildSeriesProgress(volumes []models.Book, readAt map[string]time.Time) map[string]*seriesProgress {
+	workReadAt := make(map[string]time.Time)
+	for _, book := range volumes {
+		if at, ok := readAt[book.ID]; ok && at.After(workReadAt[book.WorkID]) {
+			workReadAt[book.WorkID] = at
+		}
+	}
+
+	progress := make(map[string]*seriesProgress)
+	counted := make(map[string]bool)
+	for i := range volumes {
+		book := &volumes[i]
+		if book.Series == "" || book.SeriesPosition <= 0 || counted[book.WorkID] {
+			continue
+		}
+		counted[book.WorkID] = true
+		p := progress[book.Series]
+		if p == nil {
+			p = &seriesProgress{}
+			progress[book.Series] = p
+		}
+		if at, ok := workReadAt[book.WorkID]; ok {
+			p.volumesRead++
+			p.lastReadPosition = max(p.lastReadPosition, book.SeriesPosition)
+			if at.After(p.lastReadAt) {
+				p.lastReadAt = at
+			}
+		} else if p.next == nil {
+			p.next = book
+		}
+	}
+	return progress
+}
+
+// readBooks returns when the user last read each book, based on interactions
+// that mean the book has been read.
+func readBooks(interactions []models.UserInteraction) map[string]time.Time {
+	readAt := make(map[string]time.Time)
+	for _, ui := range interactions {
+		if !consumedInteractionTypes[ui.InteractionType] {
+			continue
+		}
+		if at, ok := readAt[ui.BookID]; !ok || ui.Timestamp.After(at) {
+			readAt[ui.BookID] = ui.Timestamp
+		}
+	}
+	return readAt
+}
+
+// applySeriesRule keeps at most one volume per series in a user's
+// recommendations: the next unread volume of a started series, or the first
+// volume of an unstarted one. Recommendations for later volumes are replaced
+// by that volume, carrying their score, and the next volume of a started
+// series is boosted.
+func (s *recommendationService) applySeriesRule(ctx context.Context, userID string, recommendations []models.Recommendation) ([]models.Recommendation, error) {
+	if len(recommendations) == 0 {
+		return recommendations, nil
+	}
+
+	bookIDs := make([]string, len(recommendations))
+	for i, rec := range recommendations {
+		bookIDs[i] = rec.BookID
+	}
+	books, err := s.bookRepo.GetBooksByIDs(ctx, bookIDs)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get recommended books: %w", err)
+	}
+	booksByID := make(map[string]models.Book, len(books))
+	seriesSeen := make(map[string]bool)
+	var series []string
+	for _, b := range books {
+		booksByID[b.ID] = b
+		if b.Series != "" && b.SeriesPosition > 0 && !seriesSeen[b.Series] {
+			seriesSeen[b.Series] = true
+			series = append(series, b.Series)
+		}
+	}
+	if len(series) == 0 {
+		return recommendations, nil
+	}
+
+	volumes, err := s.bookRepo.GetBooksBySeries(ctx, series)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get series volumes: %w", err)
+	}
+	interactions, err := s.interactionRepo.GetUserInteractionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get user interactions: %w", err)
+	}
+	progress := buildSeriesProgress(volumes, readBooks(interactions))
+
+	// Keep one recommendation per series, keyed by the volume to recommend.
+	bySeries := make(map[string]int)
+	result := make([]models.Recommendation, 0, len(recommendations))
+	for _, rec := range recommendations {
+		book, ok := booksByID[rec.BookID]
+		p := progress[book.Series]
+		if !ok || p == nil || p.next == nil || book.SeriesPosition < p.next.SeriesPosition {
+			result = append(result, rec)
+			continue
+		}
+
+		if rec.BookID != p.next.ID {
+			rec.ID = "" // no longer the stored recommendation
+			rec.BookID = p.next.ID
+		}
+		if p.started() {
+			rec.Score *= nextInSeriesBoost
+		}
+		if i, seen := bySeries[book.Series]; seen {
+			if rec.Score > result[i].Score {
+				result[i] = rec
+			}
+			continue
+		}
+		bySeries[book.Series] = len(result)
+		result = append(result, rec)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Score > result[j].Score
+	})
+	return result, nil
+}
+
+// GetContinueReading returns the next unread volume of every series the user has
+// started, most recently read series first.
+func (s *recommendationService) GetContinueReading(ctx context.Context, userID string) ([]models.ContinueReading, error) {
+	interactions, err := s.interactionRepo.GetUserInteractionsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get user interactions: %w", err)
+	}
+	readAt := readBooks(interactions)
+	if len(readAt) == 0 {
+		return []models.ContinueReading{}, nil
+	}
+
+	readIDs := make([]string, 0, len(readAt))
+	for id := range readAt {
+		readIDs = append(readIDs, id)
+	}
+	books, err := s.bookRepo.GetBooksByIDs(ctx, readIDs)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get read books: %w", err)
+	}
+	seriesSeen := make(map[string]bool)
+	var series []string
+	for _, b := range books {
+		if b.Series != "" && b.SeriesPosition > 0 && !seriesSeen[b.Series] {
+			seriesSeen[b.Series] = true
+			series = append(series, b.Series)
+		}
+	}
+
+	volumes, err := s.bookRepo.GetBooksBySeries(ctx, series)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get series volumes: %w", err)
+	}
+	progress := buildSeriesProgress(volumes, readAt)
+
+	continueReading := make([]models.ContinueReading, 0, len(progress))
+	for name, p := range progress {
+		if !p.started() || p.next == nil {
+			continue
+		}
+		continueReading = append(continueReading, models.ContinueReading{
+			Series:           name,
+			VolumesRead:      p.volumesRead,
+			LastReadPosition: p.lastReadPosition,
+			NextBook:         *p.next,
+		})
+	}
+	sort.Slice(continueReading, func(i, j int) bool {
+		a, b := progress[continueReading[i].Series], progress[continueReading[j].Series]
+		if !a.lastReadAt.Equal(b.lastReadAt) {
+			return a.lastReadAt.After(b.lastReadAt)
+		}
+		return continueReading[i].Series < continueReading[j].Series
+	})
+	return continueReading, nil
+}
@@ -10,8 +10,8 @@ import (
 
 // UserInteractionService defines the interface for user interaction-related business logic.
 type UserInteractionService interface {
-	GetAllUserInteractions(ctx context.Context) ([]models.UserInteraction, error)
-	GetUserInteractionsByUserID(ctx context.Context, userID string) ([]models.UserInteraction, error)
+	GetUserInteractionByID(ctx context.Context, id string) (*models.UserInteraction, error)
+	GetAllUserInteractions(ctx context.Context, page models.PageRequest) (*models.Page[models.UserInteraction], error)
 	GetUserInteractionsByUserID(ctx context.Context, userID string) ([]models.UserInteraction, error)
 	CreateUserInteraction(ctx context.Context, userInteraction *models.UserInteraction) error
 	UpdateUserInteraction(ctx context.Context, userInteraction *models.UserInteraction) error
@@ -32,18 +32,18 @@ func NewUserInteractionService(repo repositories.UserInteractionRepository) User
 func (s *userInteractionService) GetUserInteractionByID(ctx context.Context, id string) (*models.UserInteraction, error) {
 	userInteraction, err := s.repo.GetUserInteractionByID(ctx, id)
 	if err != nil {
-// GetAllUserInteractions retrieves all user interactions using the repository.
-func (s *userInteractionService) GetAllUserInteractions(ctx context.Context) ([]models.UserInteraction, error) {
-	userInteractions, err := s.repo.GetAllUserInteractions(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("service: failed to get all user interactions: %w", err)
+		return nil, fmt.Errorf("service: failed to get user interaction by ID: %w", err)
 	}
-	return userInteractions, nil
+	return userInteraction, nil
 }
 
-// GetUserInteractionsByUserID retrieves user interactions for a specific user using the repository.
+// GetAllUserInteractions retrieves a page of all user interactions using the repository.
+func (s *userInteractionService) GetAllUserInteractions(ctx context.Context, page models.PageRequest) (*models.Page[models.UserInteraction], error) {
+	userInteractions, err := s.repo.GetAllUserInteractions(ctx, page)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get all user interactions: %w", err)
 	}
-	return userInteraction, nil
+	return userInteractions, nil
 }
 
 // GetUserInteractionsByUserID retrieves user interactions for a specific user using the repository.
@@ -55,9 +55,24 @@ func (s *userInteractionService) GetUserInteractionsByUserID(ctx context.Context
 	return userInteractions, nil
 }
 
+// validateRating checks that rating interactions carry a star rating and
+// other interactions do not.
+func validateRating(ui *models.UserInteraction) error {
+	if ui.InteractionType == models.InteractionTypeRating {
+		if ui.Rating < models.MinRating || ui.Rating > models.MaxRating {
+			return fmt.Errorf("service: rating must be between %d and %d, got %d: %w", models.MinRating, models.MaxRating, ui.Rating, ErrInvalidInput)
+		}
+	} else if ui.Rating != 0 {
+		return fmt.Errorf("service: only rating interactions can have a rating: %w", ErrInvalidInput)
+	}
+	return nil
+}
+
 // CreateUserInteraction creates a new user interaction using the repository.
 func (s *userInteractionService) CreateUserInteraction(ctx context.Context, userInteraction *models.UserInteraction) error {
-	// Add any business logic/validation before creating a user interaction
+	if err := validateRating(userInteraction); err != nil {
+		return err
+	}
 	err := s.repo.CreateUserInteraction(ctx, userInteraction)
 	if err != nil {
 		return fmt.Errorf("service: failed to create user interaction: %w", err)
@@ -67,7 +82,9 @@ func (s *userInteractionService) CreateUserInteraction(ctx context.Context, user
 
 // UpdateUserInteraction updates an existing user interaction using the repository.
 func (s *userInteractionService) UpdateUserInteraction(ctx context.Context, userInteraction *models.UserInteraction) error {
-	// Add any business logic/validation before updating a user interaction
+	if err := validateRating(userInteraction); err != nil {
+		return err
+	}
 	err := s.repo.UpdateUserInteraction(ctx, userInteraction)
 	if err != nil {
 		return fmt.Errorf("service: failed to update user interaction: %w", err)
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"book-recommendation-system/backend/models"
+	"book-recommendation-system/backend/repositories"
+)
+
+// WorkService defines the interface for grouping books into works.
+type WorkService interface {
+	GetWorkByID(ctx context.Context, id string) (*models.Work, error)
+	AddEditions(ctx context.Context, workID string, bookIDs []string) (*models.Work, error)
+	SplitEdition(ctx context.Context, workID, bookID string) (*models.Work, error)
+}
+
+// workService implements WorkService.
+type workService struct {
+	repo     repositories.WorkRepository
+	bookRepo repositories.BookRepository
+}
+
+// NewWorkService creates a new WorkService.
+func NewWorkService(repo repositories.WorkRepository, bookRepo repositories.BookRepository) WorkService {
+	return &workService{repo: repo, bookRepo: bookRepo}
+}
+
+// GetWorkByID retrieves a work and its editions, returning nil if there is none.
+func (s *workService) GetWorkByID(ctx context.Context, id string) (*models.Work, error) {
+	work, err := s.repo.GetWorkByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get work by ID: %w", err)
+	}
+	if work == nil {
+		return nil, nil
+	}
+	editions, err := s.bookRepo.GetBooksByWorkIDs(ctx, []string{id})
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get work editions: %w", err)
+	}
+	work.Editions = editions
+	if work.Editions == nil {
+		work.Editions = []models.Book{}
+	}
+	return work, nil
+}
+
+// AddEditions makes the given books editions of a work, such as when grouping
+// a translation with its original. Works left without editions are deleted.
+// It returns the updated work, or nil if the work does not exist.
+func (s *workService) AddEditions(ctx context.Context, workID string, bookIDs []string) (*models.Work, error) {
+	seen := make(map[string]bool)
+	ids := make([]string, 0, len(bookIDs))
+	for _, id := range bookIDs {
+		id = strings.TrimSpace(id)
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("service: at least one book ID is required: %w", ErrInvalidInput)
+	}
+
+	work, err := s.repo.GetWorkByID(ctx, workID)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get work by ID: %w", err)
+	}
+	if work == nil {
+		return nil, nil
+	}
+	books, err := s.bookRepo.GetBooksByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("service: failed to get books: %w", err)
+	}
+	found := make(map[string]bool, len(books))
+	for _, b := range books {
+		found[b.ID] = true
+	}
+	for _, id := range ids {
+		if !found[id] {
+			return nil, fmt.Errorf("service: book %s does not exist: %w", id, ErrInvalidInput)
+		}
+	}
+
+	if err := s.repo.MoveEditions(ctx, workID, ids); err != nil {
+		return nil, fmt.Errorf("service: failed to add editions: %w", err)
+	}
+	return s.GetWorkByID(ctx, workID)
+}
+
+// SplitEdition removes a book from a work it was wrongly grouped into, making
+// it the only edition of a new work named after it. It returns the new work,
+// or nil if the work does not exist.
+func (s *workService) SplitEdition(ctx context.Context, workID, bookID string) (*models.Work, error) {
+	work, err := s.GetWorkByID(ctx, workID)
+	if err != nil {
+		return nil, err
+	}
+	if work == nil {
+		return nil, nil
+	}
+	var book *models.Book
+	for i := range work.Editions {
+		if work.Editions[i].ID == bookID {
+			book = &work.Editions[i]
+		}
+	}
+	if book == nil {
+		return nil, fmt.Errorf("service: book %s is not an edition of work %s: %w", bookID, workID, ErrInvalidInput)
+	}
+	if len(work.Editions) == 1 {
+		return nil, fmt.Errorf("service: book %s is already the only edition of work %s: %w", bookID, workID, ErrInvalidInput)
+	}
+
+	split := &models.Work{ID: newID(), Title: book.Title, Author: book.Author}
+	if err := s.repo.SplitEdition(ctx, bookID, split); err != nil {
+		return nil, fmt.Errorf("service: failed to split edition: %w", err)
+	}
+	return s.GetWorkByID(ctx, split.ID)
+}
@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStore is a BlobStore keeping blobs as files under a root directory.
+// A blob's content type is derived from its key's extension.
+type LocalStore struct {
+	root string
+}
+
+// NewLocalStore returns a LocalStore rooted at dir, creating it if needed.
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("storage: failed to create %s: %w", dir, err)
+	}
+	return &LocalStore{root: dir}, nil
+}
+
+// path returns the file path of a key.
+func (s *LocalStore) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || strings.HasPrefix(key, "../") || key == ".." {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+// Put writes the blob to a temporary file and renames it into place.
+func (s *LocalStore) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return fmt.Errorf("storage: failed to create directory for %s: %w", key, err)
+	}
+	f, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("storage: failed to store %s: %w", key, err)
+	}
+	defer os.Remove(f.Name()) // fails harmlessly once renamed
+
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return fmt.Errorf("storage: failed to store %s: %w", key, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("storage: failed to store %s: %w", key, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := os.Rename(f.Name(), p); err != nil {
+		return fmt.Errorf("storage: failed to store %s: %w", key, err)
+	}
+	return nil
+}
+
+// Get opens the blob's file.
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadSeekCloser, BlobInfo, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, BlobInfo{}, err
+	}
+	f, err := os.Open(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, BlobInfo{}, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	if err != nil {
+		return nil, BlobInfo{}, fmt.Errorf("storage: failed to open %s: %w", key, err)
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, BlobInfo{}, fmt.Errorf("storage: failed to open %s: %w", key, err)
+	}
+	info := BlobInfo{
+		ContentType: mime.TypeByExtension(path.Ext(key)),
+		Size:        stat.Size(),
+		ModTime:     stat.ModTime(),
+	}
+	if info.ContentType == "" {
+		info.ContentType = "application/octet-stream"
+	}
+	return f, info, nil
+}
+
+// Delete removes the blob's file.
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("storage: failed to delete %s: %w", key, err)
+	}
+	return nil
+}
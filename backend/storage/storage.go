@@ -0,0 +1,39 @@
+// Package storage stores binary objects such as cover images under string
+// keys, behind an interface so that the local filesystem can be swapped for
+// an object store.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when no blob is stored under a key.
+	ErrNotFound = errors.New("storage: blob not found")
+	// ErrInvalidKey is returned for keys that are empty, absolute or try to
+	// escape the store with "..".
+	ErrInvalidKey = errors.New("storage: invalid key")
+)
+
+// BlobInfo describes a stored blob.
+type BlobInfo struct {
+	ContentType string
+	Size        int64
+	ModTime     time.Time
+}
+
+// BlobStore stores blobs under slash-separated keys such as
+// "covers/123/small.jpg".
+type BlobStore interface {
+	// Put stores the content of r under key, replacing any blob already
+	// there. Readers never see a partly written blob.
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	// Get opens the blob stored under key. The caller must close it.
+	Get(ctx context.Context, key string) (io.ReadSeekCloser, BlobInfo, error)
+	// Delete removes the blob stored under key. Deleting a missing blob is
+	// not an error.
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,5 @@
+ALTER TABLE user_interactions
+    ADD COLUMN IF NOT EXISTS device VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS locale VARCHAR(35) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS location VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS client_time_zone VARCHAR(64) NOT NULL DEFAULT '';
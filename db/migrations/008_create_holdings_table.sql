@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS holdings (
+    id VARCHAR(255) PRIMARY KEY,
+    library_id VARCHAR(255) NOT NULL,
+    book_id VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'available', -- e.g., 'available', 'on_loan'
+    CONSTRAINT fk_library
+        FOREIGN KEY(library_id)
+        REFERENCES libraries(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_book
+        FOREIGN KEY(book_id)
+        REFERENCES books(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_holdings_book_id_status ON holdings (book_id, status);
+CREATE INDEX IF NOT EXISTS idx_holdings_library_id ON holdings (library_id);
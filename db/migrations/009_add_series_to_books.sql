@@ -0,0 +1,5 @@
+ALTER TABLE books
+    ADD COLUMN IF NOT EXISTS series VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS series_position DECIMAL(6, 2) NOT NULL DEFAULT 0; -- 0 when not part of a series
+
+CREATE INDEX IF NOT EXISTS idx_books_series ON books (series, series_position) WHERE series <> '';
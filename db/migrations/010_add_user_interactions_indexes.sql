@@ -0,0 +1,4 @@
+CREATE INDEX IF NOT EXISTS idx_user_interactions_user_id ON user_interactions (user_id);
+CREATE INDEX IF NOT EXISTS idx_user_interactions_book_id ON user_interactions (book_id);
+CREATE INDEX IF NOT EXISTS idx_user_interactions_timestamp ON user_interactions (timestamp);
+CREATE INDEX IF NOT EXISTS idx_recommendations_user_id ON recommendations (user_id);
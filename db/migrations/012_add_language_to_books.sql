@@ -0,0 +1,7 @@
+ALTER TABLE books
+    ADD COLUMN IF NOT EXISTS language VARCHAR(8) NOT NULL DEFAULT ''; -- ISO 639 code, e.g., 'en'
+
+CREATE INDEX IF NOT EXISTS idx_books_genre ON books (lower(genre));
+CREATE INDEX IF NOT EXISTS idx_books_author ON books (lower(author));
+CREATE INDEX IF NOT EXISTS idx_books_publication_year ON books (publication_year);
+CREATE INDEX IF NOT EXISTS idx_books_language ON books (language);
@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS book_authors (
+    book_id VARCHAR(255) NOT NULL,
+    author_id VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'author',
+    position INT NOT NULL DEFAULT 0, -- display order among the book's contributors
+    PRIMARY KEY (book_id, author_id, role),
+    CONSTRAINT chk_book_authors_role CHECK (role IN ('author', 'translator', 'illustrator')),
+    CONSTRAINT fk_book
+        FOREIGN KEY(book_id)
+        REFERENCES books(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_author
+        FOREIGN KEY(author_id)
+        REFERENCES authors(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_book_authors_author_id ON book_authors (author_id);
+
+CREATE TABLE IF NOT EXISTS book_genres (
+    book_id VARCHAR(255) NOT NULL,
+    genre_id VARCHAR(255) NOT NULL,
+    PRIMARY KEY (book_id, genre_id),
+    CONSTRAINT fk_book
+        FOREIGN KEY(book_id)
+        REFERENCES books(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_genre
+        FOREIGN KEY(genre_id)
+        REFERENCES genres(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_book_genres_genre_id ON book_genres (genre_id);
+
+-- Existing rows are linked by the backfill command (cmd/backfill-book-relations),
+-- which matches the free-text books.author and books.genre values to authors
+-- and genres by normalised name, creating any that are missing.
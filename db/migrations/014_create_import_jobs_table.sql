@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id VARCHAR(255) PRIMARY KEY,
+    source VARCHAR(255) NOT NULL,
+    format VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL, -- 'running', 'completed', 'failed'
+    last_committed_row INT NOT NULL DEFAULT 0,
+    created INT NOT NULL DEFAULT 0,
+    updated INT NOT NULL DEFAULT 0,
+    rejected INT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,2 @@
+ALTER TABLE import_jobs
+    ADD COLUMN IF NOT EXISTS library_id VARCHAR(255) NOT NULL DEFAULT ''; -- library whose copies are recorded as holdings, if any
@@ -0,0 +1,2 @@
+ALTER TABLE import_jobs
+    ADD COLUMN IF NOT EXISTS deleted INT NOT NULL DEFAULT 0; -- books removed by delete notifications, e.g., from ONIX feeds
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS works (
+    id VARCHAR(255) PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    author VARCHAR(255) NOT NULL
+);
+
+-- Every existing book starts out as the only edition of a work with the
+-- book's own ID; editions are grouped afterwards through the works API.
+INSERT INTO works (id, title, author)
+SELECT id, title, author FROM books
+ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE books
+    ADD COLUMN IF NOT EXISTS work_id VARCHAR(255);
+
+UPDATE books SET work_id = id WHERE work_id IS NULL;
+
+ALTER TABLE books
+    ALTER COLUMN work_id SET NOT NULL;
+
+-- Deferred so that a new book and its work can be inserted in either order
+-- within one transaction.
+ALTER TABLE books
+    DROP CONSTRAINT IF EXISTS fk_work;
+ALTER TABLE books
+    ADD CONSTRAINT fk_work
+        FOREIGN KEY(work_id)
+        REFERENCES works(id)
+        DEFERRABLE INITIALLY DEFERRED;
+
+CREATE INDEX IF NOT EXISTS idx_books_work_id ON books (work_id);
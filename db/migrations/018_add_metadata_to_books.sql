@@ -0,0 +1,16 @@
+ALTER TABLE books
+    ADD COLUMN IF NOT EXISTS page_count INT NOT NULL DEFAULT 0, -- 0 if unknown
+    ADD COLUMN IF NOT EXISTS publisher VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS format VARCHAR(20) NOT NULL DEFAULT '', -- 'hardcover', 'paperback', 'ebook' or 'audio'; '' if unknown
+    ADD COLUMN IF NOT EXISTS edition VARCHAR(100) NOT NULL DEFAULT '', -- e.g., '2nd edition'
+    ADD COLUMN IF NOT EXISTS subjects TEXT[] NOT NULL DEFAULT '{}'; -- free subject tags, lower-cased
+
+ALTER TABLE books
+    DROP CONSTRAINT IF EXISTS chk_books_format;
+ALTER TABLE books
+    ADD CONSTRAINT chk_books_format CHECK (format IN ('', 'hardcover', 'paperback', 'ebook', 'audio'));
+
+CREATE INDEX IF NOT EXISTS idx_books_publisher ON books (lower(publisher));
+CREATE INDEX IF NOT EXISTS idx_books_format ON books (format);
+CREATE INDEX IF NOT EXISTS idx_books_page_count ON books (page_count);
+CREATE INDEX IF NOT EXISTS idx_books_subjects ON books USING GIN (subjects);
@@ -0,0 +1,7 @@
+ALTER TABLE user_interactions
+    ADD COLUMN IF NOT EXISTS rating SMALLINT NOT NULL DEFAULT 0; -- 1 to 5 stars on 'rating' interactions, 0 otherwise
+
+ALTER TABLE user_interactions
+    DROP CONSTRAINT IF EXISTS chk_user_interactions_rating;
+ALTER TABLE user_interactions
+    ADD CONSTRAINT chk_user_interactions_rating CHECK (rating BETWEEN 0 AND 5);
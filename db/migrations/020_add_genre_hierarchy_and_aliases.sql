@@ -0,0 +1,29 @@
+ALTER TABLE genres
+    ADD COLUMN IF NOT EXISTS parent_id VARCHAR(255); -- NULL for top-level genres
+
+ALTER TABLE genres
+    DROP CONSTRAINT IF EXISTS fk_parent;
+ALTER TABLE genres
+    ADD CONSTRAINT fk_parent
+        FOREIGN KEY(parent_id)
+        REFERENCES genres(id)
+        ON DELETE SET NULL;
+ALTER TABLE genres
+    DROP CONSTRAINT IF EXISTS chk_genres_parent;
+ALTER TABLE genres
+    ADD CONSTRAINT chk_genres_parent CHECK (parent_id <> id);
+
+CREATE INDEX IF NOT EXISTS idx_genres_parent_id ON genres (parent_id);
+
+-- Alternative names a genre is known by, e.g., 'SF' and 'Sci-Fi' for 'Science Fiction'.
+CREATE TABLE IF NOT EXISTS genre_aliases (
+    genre_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    PRIMARY KEY (genre_id, name),
+    CONSTRAINT fk_genre
+        FOREIGN KEY(genre_id)
+        REFERENCES genres(id)
+        ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_genre_aliases_name ON genre_aliases (lower(name));
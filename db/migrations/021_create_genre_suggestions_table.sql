@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS genre_suggestions (
+    id VARCHAR(255) PRIMARY KEY,
+    book_id VARCHAR(255) NOT NULL,
+    genre_id VARCHAR(255) NOT NULL,
+    confidence DOUBLE PRECISION NOT NULL, -- classifier probability, from 0 to 1
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT chk_genre_suggestions_status CHECK (status IN ('pending', 'accepted', 'rejected')),
+    CONSTRAINT uq_genre_suggestions_book_genre UNIQUE (book_id, genre_id),
+    CONSTRAINT fk_book
+        FOREIGN KEY(book_id)
+        REFERENCES books(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_genre
+        FOREIGN KEY(genre_id)
+        REFERENCES genres(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_genre_suggestions_status_confidence ON genre_suggestions (status, confidence DESC, id DESC);
@@ -0,0 +1,2 @@
+-- Supports the bounding-box prefilter of nearest-library searches.
+CREATE INDEX IF NOT EXISTS idx_libraries_latitude_longitude ON libraries (latitude, longitude);
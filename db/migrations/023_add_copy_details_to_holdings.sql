@@ -0,0 +1,11 @@
+ALTER TABLE holdings
+    ADD COLUMN IF NOT EXISTS barcode VARCHAR(64), -- unique across the consortium; NULL for copies not yet labelled
+    ADD COLUMN IF NOT EXISTS shelf_location VARCHAR(255) NOT NULL DEFAULT '';
+
+ALTER TABLE holdings
+    DROP CONSTRAINT IF EXISTS chk_holdings_status;
+ALTER TABLE holdings
+    ADD CONSTRAINT chk_holdings_status CHECK (status IN ('available', 'on_loan', 'lost', 'in_repair'));
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_holdings_barcode ON holdings (barcode);
+CREATE INDEX IF NOT EXISTS idx_holdings_library_id_book_id ON holdings (library_id, book_id);
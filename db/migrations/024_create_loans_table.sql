@@ -0,0 +1,38 @@
+-- Each library's circulation policy.
+ALTER TABLE libraries
+    ADD COLUMN IF NOT EXISTS loan_period_days INTEGER NOT NULL DEFAULT 21, -- also how far a renewal extends a loan
+    ADD COLUMN IF NOT EXISTS max_renewals INTEGER NOT NULL DEFAULT 2;
+
+ALTER TABLE libraries
+    DROP CONSTRAINT IF EXISTS chk_libraries_loan_policy;
+ALTER TABLE libraries
+    ADD CONSTRAINT chk_libraries_loan_policy CHECK (loan_period_days > 0 AND max_renewals >= 0);
+
+CREATE TABLE IF NOT EXISTS loans (
+    id VARCHAR(255) PRIMARY KEY,
+    holding_id VARCHAR(255) NOT NULL,
+    book_id VARCHAR(255) NOT NULL,
+    library_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    checked_out_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    due_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    renewals INTEGER NOT NULL DEFAULT 0,
+    returned_at TIMESTAMP WITH TIME ZONE, -- NULL while the copy is out
+    CONSTRAINT fk_holding
+        FOREIGN KEY(holding_id)
+        REFERENCES holdings(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_book
+        FOREIGN KEY(book_id)
+        REFERENCES books(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_library
+        FOREIGN KEY(library_id)
+        REFERENCES libraries(id)
+        ON DELETE CASCADE
+);
+
+-- A copy can be on only one loan at a time.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_loans_active_holding_id ON loans (holding_id) WHERE returned_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_loans_user_id ON loans (user_id);
+CREATE INDEX IF NOT EXISTS idx_loans_due_at_active ON loans (due_at) WHERE returned_at IS NULL;
@@ -0,0 +1,53 @@
+-- How long a library keeps a copy on its hold shelf for the reader it was
+-- assigned to.
+ALTER TABLE libraries
+    ADD COLUMN IF NOT EXISTS hold_pickup_days INTEGER NOT NULL DEFAULT 7;
+
+ALTER TABLE libraries
+    DROP CONSTRAINT IF EXISTS chk_libraries_hold_policy;
+ALTER TABLE libraries
+    ADD CONSTRAINT chk_libraries_hold_policy CHECK (hold_pickup_days > 0);
+
+-- Copies assigned to a hold wait on the hold shelf.
+ALTER TABLE holdings
+    DROP CONSTRAINT IF EXISTS chk_holdings_status;
+ALTER TABLE holdings
+    ADD CONSTRAINT chk_holdings_status CHECK (status IN ('available', 'on_loan', 'on_hold', 'lost', 'in_repair'));
+
+CREATE TABLE IF NOT EXISTS holds (
+    id VARCHAR(255) PRIMARY KEY,
+    book_id VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    library_id VARCHAR(255), -- only copies at this library can fill the hold; NULL for any library
+    pickup_library_id VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'waiting',
+    holding_id VARCHAR(255), -- the copy assigned to a ready hold
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE, -- the reader no longer wants the book after this
+    ready_at TIMESTAMP WITH TIME ZONE,
+    pickup_by TIMESTAMP WITH TIME ZONE,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT chk_holds_status CHECK (status IN ('waiting', 'ready', 'fulfilled', 'cancelled', 'expired')),
+    CONSTRAINT fk_book
+        FOREIGN KEY(book_id)
+        REFERENCES books(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_library
+        FOREIGN KEY(library_id)
+        REFERENCES libraries(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_pickup_library
+        FOREIGN KEY(pickup_library_id)
+        REFERENCES libraries(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_holding
+        FOREIGN KEY(holding_id)
+        REFERENCES holdings(id)
+        ON DELETE SET NULL
+);
+
+-- A reader has at most one open hold per book, and a copy fills at most one.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_holds_open_book_id_user_id ON holds (book_id, user_id) WHERE status IN ('waiting', 'ready');
+CREATE UNIQUE INDEX IF NOT EXISTS idx_holds_ready_holding_id ON holds (holding_id) WHERE status = 'ready';
+CREATE INDEX IF NOT EXISTS idx_holds_waiting_book_id ON holds (book_id, created_at, id) WHERE status = 'waiting';
+CREATE INDEX IF NOT EXISTS idx_holds_user_id ON holds (user_id);
@@ -0,0 +1,3 @@
+-- Serves the most recent interactions with a book, which real-time scoring
+-- reads to find similar readers.
+CREATE INDEX IF NOT EXISTS idx_user_interactions_book_id_timestamp ON user_interactions (book_id, timestamp DESC);
@@ -0,0 +1,44 @@
+-- Loans and holds are circulation records: deleting a book, library or copy
+-- must not silently drop them, so such a delete fails while any remain.
+ALTER TABLE loans
+    DROP CONSTRAINT IF EXISTS fk_holding,
+    DROP CONSTRAINT IF EXISTS fk_book,
+    DROP CONSTRAINT IF EXISTS fk_library;
+ALTER TABLE loans
+    ADD CONSTRAINT fk_holding
+        FOREIGN KEY(holding_id)
+        REFERENCES holdings(id)
+        ON DELETE RESTRICT,
+    ADD CONSTRAINT fk_book
+        FOREIGN KEY(book_id)
+        REFERENCES books(id)
+        ON DELETE RESTRICT,
+    ADD CONSTRAINT fk_library
+        FOREIGN KEY(library_id)
+        REFERENCES libraries(id)
+        ON DELETE RESTRICT;
+
+ALTER TABLE holds
+    DROP CONSTRAINT IF EXISTS fk_book,
+    DROP CONSTRAINT IF EXISTS fk_library,
+    DROP CONSTRAINT IF EXISTS fk_pickup_library;
+ALTER TABLE holds
+    ADD CONSTRAINT fk_book
+        FOREIGN KEY(book_id)
+        REFERENCES books(id)
+        ON DELETE RESTRICT,
+    ADD CONSTRAINT fk_library
+        FOREIGN KEY(library_id)
+        REFERENCES libraries(id)
+        ON DELETE RESTRICT,
+    ADD CONSTRAINT fk_pickup_library
+        FOREIGN KEY(pickup_library_id)
+        REFERENCES libraries(id)
+        ON DELETE RESTRICT;
+
+-- Serve the checks made before deleting a book, library or copy.
+CREATE INDEX IF NOT EXISTS idx_loans_book_id ON loans (book_id);
+CREATE INDEX IF NOT EXISTS idx_loans_library_id ON loans (library_id);
+CREATE INDEX IF NOT EXISTS idx_loans_holding_id ON loans (holding_id);
+CREATE INDEX IF NOT EXISTS idx_holds_library_id ON holds (library_id);
+CREATE INDEX IF NOT EXISTS idx_holds_pickup_library_id ON holds (pickup_library_id);